@@ -0,0 +1,390 @@
+package main
+
+import (
+    "fmt"
+    "sort"
+    "strings"
+)
+
+// splitBill rounds every share UP and collects a few extra cents, and
+// splitBillEvenly is still a stub. Here we split a real order three ways:
+// by the items each guest had, by custom shares, or evenly. Leftover cents
+// go out by the largest-remainder method, so the shares always add up to
+// exactly the total - tax and tip included. Integer / and % do all the work.
+
+// Cents keeps money as whole cents
+type Cents int64
+
+func (c Cents) String() string {
+    // c/100 and c%100 both carry the sign, so -50 would print as "$0.-50"
+    if c < 0 {
+        return "-" + (-c).String()
+    }
+    return fmt.Sprintf("$%d.%02d", c/100, c%100)
+}
+
+type OrderItem struct {
+    Name     string
+    Price    Cents
+    Quantity int
+}
+
+type Order struct {
+    ID       string
+    Items    []OrderItem
+    TaxBasis int // Tax rate in basis points: 850 = 8.50%
+    Tip      Cents
+}
+
+func (o Order) Subtotal() Cents {
+    var subtotal Cents
+    for _, item := range o.Items {
+        subtotal += item.Price * Cents(item.Quantity)
+    }
+    return subtotal
+}
+
+func (o Order) Tax() Cents {
+    // Round half up: add half the divisor before dividing
+    return (o.Subtotal()*Cents(o.TaxBasis) + 5000) / 10000
+}
+
+func (o Order) Total() Cents {
+    return o.Subtotal() + o.Tax() + o.Tip
+}
+
+// allocate splits total by weights. Each part gets total*w/W (integer
+// division), then the cents lost to rounding go to the parts with the
+// largest remainders (total*w % W). The parts always sum to total.
+func allocate(total Cents, weights []int64) []Cents {
+    parts := make([]Cents, len(weights))
+
+    var sum int64
+    for _, w := range weights {
+        sum += w
+    }
+    if sum == 0 {
+        return parts
+    }
+
+    remainders := make([]int, len(weights))
+    var given Cents
+    for i, w := range weights {
+        parts[i] = Cents(int64(total) * w / sum)
+        given += parts[i]
+        remainders[i] = i
+    }
+
+    sort.SliceStable(remainders, func(a, b int) bool {
+        ra := int64(total) * weights[remainders[a]] % sum
+        rb := int64(total) * weights[remainders[b]] % sum
+        return ra > rb
+    })
+
+    for i := 0; given < total; i++ {
+        parts[remainders[i%len(remainders)]]++
+        given++
+    }
+
+    return parts
+}
+
+// === Split Checks ===
+
+type Share struct {
+    Guest    string
+    Subtotal Cents
+    Tax      Cents
+    Tip      Cents
+    Tender   string
+    Paid     Cents
+}
+
+func (s Share) Amount() Cents {
+    return s.Subtotal + s.Tax + s.Tip
+}
+
+type SplitCheck struct {
+    Order  Order
+    Shares []Share
+}
+
+// newSplitCheck spreads tax and tip by weight. With subtotals given (split
+// by items) each guest pays for exactly what they had; otherwise the whole
+// total is allocated first so equal weights really pay equal amounts.
+func newSplitCheck(order Order, guests []string, weights []int64, subtotals []Cents) *SplitCheck {
+    taxes := allocate(order.Tax(), weights)
+    tips := allocate(order.Tip, weights)
+
+    if subtotals == nil {
+        subtotals = allocate(order.Total(), weights)
+        for i := range subtotals {
+            subtotals[i] -= taxes[i] + tips[i]
+        }
+    }
+
+    check := &SplitCheck{Order: order}
+    for i, guest := range guests {
+        check.Shares = append(check.Shares, Share{
+            Guest:    guest,
+            Subtotal: subtotals[i],
+            Tax:      taxes[i],
+            Tip:      tips[i],
+        })
+    }
+    return check
+}
+
+// SplitEvenly divides the whole bill into equal shares
+func SplitEvenly(order Order, guests ...string) *SplitCheck {
+    weights := make([]int64, len(guests))
+    for i := range weights {
+        weights[i] = 1
+    }
+    return newSplitCheck(order, guests, weights, nil)
+}
+
+// SplitByShares divides the bill by custom weights, e.g. 2:1:1. Every
+// weight must be positive: a zero or negative one would leave the shares
+// short of the total.
+func SplitByShares(order Order, shares map[string]int64) (*SplitCheck, error) {
+    if len(shares) == 0 {
+        return nil, fmt.Errorf("order %s: no one to split between", order.ID)
+    }
+    var guests, bad []string
+    for guest, weight := range shares {
+        guests = append(guests, guest)
+        if weight <= 0 {
+            bad = append(bad, fmt.Sprintf("%s (%d)", guest, weight))
+        }
+    }
+    sort.Strings(guests)
+    if len(bad) > 0 {
+        sort.Strings(bad)
+        return nil, fmt.Errorf("order %s: shares must be positive: %s", order.ID, strings.Join(bad, ", "))
+    }
+
+    weights := make([]int64, len(guests))
+    for i, guest := range guests {
+        weights[i] = shares[guest]
+    }
+    return newSplitCheck(order, guests, weights, nil), nil
+}
+
+// SplitByItems charges each guest for what they had; a shared item is
+// divided between everyone assigned to it
+func SplitByItems(order Order, assignments map[string][]string) (*SplitCheck, error) {
+    onOrder := make(map[string]bool)
+    for _, item := range order.Items {
+        onOrder[item.Name] = true
+    }
+    var unknown []string
+    for name := range assignments {
+        if !onOrder[name] {
+            unknown = append(unknown, name)
+        }
+    }
+    if len(unknown) > 0 {
+        sort.Strings(unknown) // Map order is random; keep the message stable
+        return nil, fmt.Errorf("not on order %s: %s", order.ID, strings.Join(unknown, ", "))
+    }
+
+    var guests []string
+    index := make(map[string]int)
+    for _, item := range order.Items {
+        for _, guest := range assignments[item.Name] {
+            if _, seen := index[guest]; !seen {
+                index[guest] = len(guests)
+                guests = append(guests, guest)
+            }
+        }
+    }
+
+    subtotals := make([]Cents, len(guests))
+    for _, item := range order.Items {
+        owners := assignments[item.Name]
+        if len(owners) == 0 {
+            return nil, fmt.Errorf("nobody is assigned to %s", item.Name)
+        }
+
+        weights := make([]int64, len(owners))
+        for i := range weights {
+            weights[i] = 1
+        }
+
+        for i, part := range allocate(item.Price*Cents(item.Quantity), weights) {
+            subtotals[index[owners[i]]] += part
+        }
+    }
+
+    weights := make([]int64, len(subtotals))
+    for i, subtotal := range subtotals {
+        weights[i] = int64(subtotal)
+    }
+    return newSplitCheck(order, guests, weights, subtotals), nil
+}
+
+// Pay settles one share with its own tender and returns any change
+func (c *SplitCheck) Pay(guest, tender string, amount Cents) (Cents, error) {
+    if tender == "" {
+        return 0, fmt.Errorf("%s: tender is required", guest)
+    }
+    for i := range c.Shares {
+        share := &c.Shares[i]
+        if share.Guest != guest {
+            continue
+        }
+        if share.Tender != "" { // Paid may be $0.00 for a guest who had nothing
+            return 0, fmt.Errorf("%s already paid by %s", guest, share.Tender)
+        }
+        if amount < share.Amount() {
+            return 0, fmt.Errorf("%s owes %s, only %s tendered", guest, share.Amount(), amount)
+        }
+
+        share.Tender = tender
+        share.Paid = share.Amount()
+        return amount - share.Amount(), nil
+    }
+    return 0, fmt.Errorf("no share for %s", guest)
+}
+
+func (c *SplitCheck) Balance() Cents {
+    balance := c.Order.Total()
+    for _, share := range c.Shares {
+        balance -= share.Paid
+    }
+    return balance
+}
+
+func (c *SplitCheck) Print(title string) {
+    fmt.Println(title)
+    fmt.Printf("  %-8s %9s %7s %7s %9s  %s\n", "Guest", "Items", "Tax", "Tip", "Share", "Tender")
+
+    var sum Cents
+    for _, share := range c.Shares {
+        tender := share.Tender
+        if tender == "" {
+            tender = "unpaid"
+        }
+        fmt.Printf("  %-8s %9s %7s %7s %9s  %s\n",
+            share.Guest, share.Subtotal, share.Tax, share.Tip, share.Amount(), tender)
+        sum += share.Amount()
+    }
+
+    mark := "✅"
+    if sum != c.Order.Total() {
+        mark = fmt.Sprintf("❌ (%s)", c.Order.Total())
+    }
+    fmt.Printf("  %-35s %9s %s\n", "Sum of shares", sum, mark)
+    fmt.Println()
+}
+
+func main() {
+    fmt.Println("=== GoCoffee Bill Splitting ===")
+    fmt.Println()
+
+    order := Order{
+        ID: "ORD-2041",
+        Items: []OrderItem{
+            {"Latte", 450, 2},
+            {"Cappuccino", 400, 1},
+            {"Croissant", 325, 1},
+            {"Blueberry Muffin", 300, 1},
+        },
+        TaxBasis: 850,
+        Tip:      400,
+    }
+
+    fmt.Printf("Order %s\n", order.ID)
+    fmt.Printf("  Subtotal: %s  Tax: %s  Tip: %s  Total: %s\n\n",
+        order.Subtotal(), order.Tax(), order.Tip, order.Total())
+
+    // 1. Evenly - $24.89 doesn't divide by three, so two guests pay a cent more
+    even := SplitEvenly(order, "Ana", "Ben", "Chloe")
+    even.Print("Split evenly:")
+
+    // 2. By custom shares - Ana covers half
+    shares, err := SplitByShares(order, map[string]int64{"Ana": 2, "Ben": 1, "Chloe": 1})
+    if err != nil {
+        fmt.Printf("❌ %v\n", err)
+        return
+    }
+    shares.Print("Split by shares (2:1:1):")
+
+    // A zero share would leave the bill short, so it's refused
+    if _, err := SplitByShares(order, map[string]int64{"Ana": 2, "Ben": 0, "Chloe": 1}); err != nil {
+        fmt.Printf("❌ %v\n", err)
+    }
+
+    // 3. By items - Ben and Chloe shared the muffin
+    byItems, err := SplitByItems(order, map[string][]string{
+        "Latte":            {"Ana"},
+        "Cappuccino":       {"Ben"},
+        "Croissant":        {"Chloe"},
+        "Blueberry Muffin": {"Ben", "Chloe"},
+    })
+    if err != nil {
+        fmt.Printf("❌ %v\n", err)
+        return
+    }
+
+    // A typo in an item name is an error, not a free coffee
+    if _, err := SplitByItems(order, map[string][]string{
+        "Latte": {"Ana"}, "Capuccino": {"Ben"}, "Croissant": {"Chloe"}, "Blueberry Muffin": {"Ben"},
+    }); err != nil {
+        fmt.Printf("❌ %v\n", err)
+    }
+
+    // Each share is paid with its own tender
+    payments := []struct {
+        guest  string
+        tender string
+        amount Cents
+    }{
+        {"Ana", "card", 0},
+        {"Ben", "cash", 1000},
+        {"Chloe", "mobile", 0},
+    }
+
+    for _, p := range payments {
+        amount := p.amount
+        if amount == 0 {
+            amount = shareFor(byItems, p.guest) // Card and mobile pay exactly
+        }
+        change, err := byItems.Pay(p.guest, p.tender, amount)
+        if err != nil {
+            fmt.Printf("❌ %v\n", err)
+            continue
+        }
+        if change > 0 {
+            fmt.Printf("💵 %s paid %s cash, change %s\n", p.guest, amount, change)
+        }
+    }
+    if _, err := byItems.Pay("Ana", "card", shareFor(byItems, "Ana")); err != nil {
+        fmt.Printf("❌ %v\n", err) // Tapped twice
+    }
+    fmt.Println()
+
+    byItems.Print("Split by items:")
+    fmt.Printf("Balance due: %s\n", byItems.Balance())
+
+    // The old way, for comparison
+    fmt.Println("\n" + strings.Repeat("-", 40))
+    oldShare := (order.Total() + 2) / 3 // Round up like splitBill
+    fmt.Printf("Old splitBill: 3 × %s = %s (%s too much)\n",
+        oldShare, oldShare*3, oldShare*3-order.Total())
+}
+
+func shareFor(check *SplitCheck, guest string) Cents {
+    for _, share := range check.Shares {
+        if share.Guest == guest {
+            return share.Amount()
+        }
+    }
+    return 0
+}
+
+// Key points:
+// 1. Integer division (/) gives the base share, modulo (%) the remainder
+// 2. Handing leftover cents to the largest remainders is fair and exact
+// 3. Tax and tip follow each guest's subtotal, so shares stay proportional
@@ -16,6 +16,7 @@ examples=(
     "07_practical_examples.go"
     "08_operator_pitfalls.go"
     "09_operators_challenge.go"
+    "10_bill_splitting.go"
 )
 
 for example in "${examples[@]}"; do
@@ -4,6 +4,8 @@ import (
     "fmt"
     "strings"
     "time"
+
+    "fmt-examples/textlayout"
 )
 
 type OrderItem struct {
@@ -147,12 +149,11 @@ func printFancyReceipt(r Receipt) {
         }
         
         price := fmt.Sprintf("$%.2f", item.Price*float64(item.Quantity))
-        spacing := width - len(name) - len(price) - 4
-        fmt.Printf("║ %s%*s%s ║\n", name, spacing, "", price)
+        fmt.Printf("║ %s ║\n", textlayout.Justify(name, price, width-4))
         
         for _, mod := range item.Mods {
             modText := "  ⤷ " + mod
-            fmt.Printf("║ %-*s ║\n", width-4, modText)
+            fmt.Printf("║ %s ║\n", textlayout.PadRight(modText, width-4))
         }
     }
     
@@ -181,15 +182,12 @@ func printMinimalReceipt(r Receipt) {
     fmt.Println("Thank you!")
 }
 
+// center and printReceiptLine measure display width, so "Café Crème"
+// and emoji line up with plain ASCII names
 func center(s string, width int) string {
-    if len(s) >= width {
-        return s
-    }
-    padding := (width - len(s)) / 2
-    return fmt.Sprintf("%*s%s%*s", padding, "", s, width-len(s)-padding, "")
+    return textlayout.Center(s, width)
 }
 
 func printReceiptLine(label, value string, width int) {
-    spacing := width - len(label) - len(value) - 4
-    fmt.Printf("║ %s%*s%s ║\n", label, spacing, "", value)
+    fmt.Printf("║ %s ║\n", textlayout.Justify(label, value, width-4))
 }
\ No newline at end of file
@@ -3,6 +3,8 @@ package main
 import (
     "fmt"
     "strings"
+
+    "fmt-examples/textlayout"
 )
 
 type MenuItem struct {
@@ -223,6 +225,5 @@ func printPriceCell(price float64) {
 }
 
 func center(text string, width int) string {
-    padding := (width - len(text)) / 2
-    return fmt.Sprintf("%*s%s%*s", padding, "", text, width-len(text)-padding, "")
+    return textlayout.Center(text, width)
 }
\ No newline at end of file
@@ -4,6 +4,8 @@ import (
     "fmt"
     "strings"
     "time"
+
+    "fmt-examples/textlayout"
 )
 
 type SalesData struct {
@@ -202,14 +204,11 @@ func formatMoney(amount float64) string {
     return fmt.Sprintf("$%.2f", amount)
 }
 
+// truncate cuts by display width and never splits a rune in half
 func truncate(s string, maxLen int) string {
-    if len(s) <= maxLen {
-        return s
-    }
-    return s[:maxLen-3] + "..."
+    return textlayout.Truncate(s, maxLen)
 }
 
 func center(s string, width int) string {
-    padding := (width - len(s)) / 2
-    return fmt.Sprintf("%*s%s%*s", padding, "", s, width-len(s)-padding, "")
+    return textlayout.Center(s, width)
 }
\ No newline at end of file
@@ -3,6 +3,7 @@ package main
 import (
     "bufio"
     "fmt"
+    "io"
     "os"
     "strconv"
     "strings"
@@ -15,8 +16,7 @@ func main() {
     
     // Get customer name
     fmt.Print("Enter customer name: ")
-    name, _ := reader.ReadString('\n')
-    name = strings.TrimSpace(name)
+    name := readLine(reader)
     
     // Get coffee choice with validation
     fmt.Println("\nCoffee Menu:")
@@ -31,8 +31,7 @@ func main() {
     
     for {
         fmt.Print("\nEnter choice (1-4): ")
-        input, _ := reader.ReadString('\n')
-        input = strings.TrimSpace(input)
+        input := readLine(reader)
         
         var err error
         choice, err = strconv.Atoi(input)
@@ -61,8 +60,7 @@ func main() {
     var quantity int
     for {
         fmt.Print("Enter quantity: ")
-        input, _ := reader.ReadString('\n')
-        input = strings.TrimSpace(input)
+        input := readLine(reader)
         
         var err error
         quantity, err = strconv.Atoi(input)
@@ -88,8 +86,8 @@ func main() {
     
     // Get confirmation
     fmt.Print("\nConfirm order? (y/n): ")
-    confirm, _ := reader.ReadString('\n')
-    confirm = strings.ToLower(strings.TrimSpace(confirm))
+    confirm := readLine(reader)
+    confirm = strings.ToLower(confirm)
     
     if confirm == "y" || confirm == "yes" {
         fmt.Println("\n✅ Order placed successfully!")
@@ -104,8 +102,7 @@ func main() {
     
     // Phone number formatting
     fmt.Print("\nEnter phone (10 digits): ")
-    phone, _ := reader.ReadString('\n')
-    phone = strings.TrimSpace(phone)
+    phone := readLine(reader)
     
     // Remove non-digits
     digitsOnly := ""
@@ -125,10 +122,26 @@ func main() {
     
     // Credit card formatting (demo only - last 4 digits)
     fmt.Print("\nEnter last 4 digits of card: ")
-    card, _ := reader.ReadString('\n')
-    card = strings.TrimSpace(card)
+    card := readLine(reader)
     
     if len(card) == 4 {
         fmt.Printf("Card: •••• •••• •••• %s\n", card)
     }
-}
\ No newline at end of file
+}
+
+// readLine returns the next line without its newline. Ignoring the error
+// from ReadString meant a piped file or Ctrl-D gave "" forever and the
+// validation loops never ended - at EOF there is nothing left to retry.
+// See 15_order_entry.go for a version that can be scripted.
+func readLine(reader *bufio.Reader) string {
+    line, err := reader.ReadString('\n')
+    if err != nil && line == "" {
+        if err != io.EOF {
+            fmt.Printf("\n❌ Read failed: %v\n", err)
+            os.Exit(1)
+        }
+        fmt.Println("\n⚠️  Input ended - nothing more to read")
+        os.Exit(0)
+    }
+    return strings.TrimSpace(line)
+}
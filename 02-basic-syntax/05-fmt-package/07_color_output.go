@@ -2,7 +2,11 @@ package main
 
 import (
     "fmt"
+    "os"
     "strings"
+
+    "fmt-examples/style"
+    "fmt-examples/textlayout"
 )
 
 // ANSI color codes
@@ -26,84 +30,88 @@ const (
     Blink     = "\033[5m"
 )
 
+// out drops the codes above when stdout is a file or pipe, or NO_COLOR is
+// set, so "go run 07_color_output.go > menu.txt" gives clean text
+var out = style.NewWriter(os.Stdout)
+
 func main() {
-    fmt.Println("=== GoCoffee Colored Output ===\n")
+    fmt.Fprintln(out, "=== GoCoffee Colored Output ===\n")
     
     // Basic colors
-    fmt.Println("BASIC COLORS:")
-    fmt.Printf("%sRed text%s\n", Red, Reset)
-    fmt.Printf("%sGreen text%s\n", Green, Reset)
-    fmt.Printf("%sYellow text%s\n", Yellow, Reset)
-    fmt.Printf("%sBlue text%s\n", Blue, Reset)
-    fmt.Printf("%sPurple text%s\n", Purple, Reset)
-    fmt.Printf("%sCyan text%s\n", Cyan, Reset)
+    fmt.Fprintln(out, "BASIC COLORS:")
+    fmt.Fprintf(out, "%sRed text%s\n", Red, Reset)
+    fmt.Fprintf(out, "%sGreen text%s\n", Green, Reset)
+    fmt.Fprintf(out, "%sYellow text%s\n", Yellow, Reset)
+    fmt.Fprintf(out, "%sBlue text%s\n", Blue, Reset)
+    fmt.Fprintf(out, "%sPurple text%s\n", Purple, Reset)
+    fmt.Fprintf(out, "%sCyan text%s\n", Cyan, Reset)
     
     // Styled text
-    fmt.Println("\nSTYLED TEXT:")
-    fmt.Printf("%sBold text%s\n", Bold, Reset)
-    fmt.Printf("%sUnderlined text%s\n", Underline, Reset)
-    fmt.Printf("%s%sBold and Red%s\n", Bold+Red, Reset)
+    fmt.Fprintln(out, "\nSTYLED TEXT:")
+    fmt.Fprintf(out, "%sBold text%s\n", Bold, Reset)
+    fmt.Fprintf(out, "%sUnderlined text%s\n", Underline, Reset)
+    fmt.Fprintf(out, "%s%sBold and Red%s\n", Bold+Red, Reset)
     
     // Status messages
-    fmt.Println("\nSTATUS MESSAGES:")
+    fmt.Fprintln(out, "\nSTATUS MESSAGES:")
     printSuccess("✓ Order completed successfully!")
     printError("✗ Payment failed - please try again")
     printWarning("⚠ Low inventory on Espresso beans")
     printInfo("ℹ Store closes at 10 PM today")
     
     // Colored menu
-    fmt.Println("\nCOLORED MENU:")
+    fmt.Fprintln(out, "\nCOLORED MENU:")
     printColoredMenu()
     
     // Progress bar
-    fmt.Println("\nPROGRESS BAR:")
+    fmt.Fprintln(out, "\nPROGRESS BAR:")
     for i := 0; i <= 100; i += 10 {
         printProgress("Brewing coffee", i, 100)
         // Simulate work
         for j := 0; j < 50000000; j++ {}
     }
-    fmt.Println()
+    fmt.Fprintln(out)
     
     // Colored receipt
-    fmt.Println("\nCOLORED RECEIPT:")
+    fmt.Fprintln(out, "\nCOLORED RECEIPT:")
     printColoredReceipt()
 }
 
 func printSuccess(msg string) {
-    fmt.Printf("%s%s%s\n", Green, msg, Reset)
+    out.Println(style.Fg(style.Green), msg)
 }
 
 func printError(msg string) {
-    fmt.Printf("%s%s%s\n", Red, msg, Reset)
+    out.Println(style.Fg(style.Red), msg)
 }
 
 func printWarning(msg string) {
-    fmt.Printf("%s%s%s\n", Yellow, msg, Reset)
+    out.Println(style.Fg(style.Yellow), msg)
 }
 
 func printInfo(msg string) {
-    fmt.Printf("%s%s%s\n", Cyan, msg, Reset)
+    out.Println(style.Fg(style.Cyan), msg)
 }
 
 func printColoredMenu() {
-    fmt.Printf("\n%s%s╔════════════════════════════════╗%s\n", Bold, Blue, Reset)
-    fmt.Printf("%s%s║      ☕ GOCOFFEE MENU ☕       ║%s\n", Bold, Blue, Reset)
-    fmt.Printf("%s%s╚════════════════════════════════╝%s\n", Bold, Blue, Reset)
+    fmt.Fprintf(out, "\n%s%s╔════════════════════════════════╗%s\n", Bold, Blue, Reset)
+    fmt.Fprintf(out, "%s%s║      ☕ GOCOFFEE MENU ☕       ║%s\n", Bold, Blue, Reset)
+    fmt.Fprintf(out, "%s%s╚════════════════════════════════╝%s\n", Bold, Blue, Reset)
     
     // Hot drinks
-    fmt.Printf("\n%s%sHOT DRINKS:%s\n", Bold, Red, Reset)
-    fmt.Printf("  %sEspresso%s .............. $3.00\n", Yellow, Reset)
-    fmt.Printf("  %sLatte%s ................. $4.50\n", Yellow, Reset)
-    fmt.Printf("  %sCappuccino%s ............ $4.00\n", Yellow, Reset)
+    fmt.Fprintf(out, "\n%s%sHOT DRINKS:%s\n", Bold, Red, Reset)
+    fmt.Fprintf(out, "  %sEspresso%s .............. $3.00\n", Yellow, Reset)
+    fmt.Fprintf(out, "  %sLatte%s ................. $4.50\n", Yellow, Reset)
+    fmt.Fprintf(out, "  %sCappuccino%s ............ $4.00\n", Yellow, Reset)
     
     // Cold drinks
-    fmt.Printf("\n%s%sCOLD DRINKS:%s\n", Bold, Cyan, Reset)
-    fmt.Printf("  %sIced Coffee%s ........... $3.50\n", Cyan, Reset)
-    fmt.Printf("  %sCold Brew%s ............. $4.00\n", Cyan, Reset)
+    fmt.Fprintf(out, "\n%s%sCOLD DRINKS:%s\n", Bold, Cyan, Reset)
+    fmt.Fprintf(out, "  %sIced Coffee%s ........... $3.50\n", Cyan, Reset)
+    fmt.Fprintf(out, "  %sCold Brew%s ............. $4.00\n", Cyan, Reset)
     
     // Special
-    fmt.Printf("\n%s%s⭐ TODAY'S SPECIAL:%s\n", Bold+Blink, Green, Reset)
-    fmt.Printf("  %s%sCaramel Macchiato%s ..... %s$3.99%s %s(Save $1!)%s\n", 
+    fmt.Fprintf(out, "\n%s%s⭐ TODAY'S SPECIAL:%s\n", Bold+Blink, Green, Reset)
+    fmt.Fprintf(out, "  %s%sCaramel Macchiato%s ..... %s$3.99%s %s(Save $1!)%s\n", 
         Bold, Yellow, Reset, Green, Reset, Red, Reset)
 }
 
@@ -113,55 +121,55 @@ func printProgress(task string, current, total int) {
     filled := int(float64(barWidth) * float64(current) / float64(total))
     
     // Clear line
-    fmt.Printf("\r%s", strings.Repeat(" ", 60))
+    fmt.Fprintf(out, "\r%s", strings.Repeat(" ", 60))
     
     // Print progress
-    fmt.Printf("\r%s: [", task)
+    fmt.Fprintf(out, "\r%s: [", task)
     
     // Filled part
     if percent < 50 {
-        fmt.Printf("%s%s%s", Red, strings.Repeat("█", filled), Reset)
+        fmt.Fprintf(out, "%s%s%s", Red, strings.Repeat("█", filled), Reset)
     } else if percent < 80 {
-        fmt.Printf("%s%s%s", Yellow, strings.Repeat("█", filled), Reset)
+        fmt.Fprintf(out, "%s%s%s", Yellow, strings.Repeat("█", filled), Reset)
     } else {
-        fmt.Printf("%s%s%s", Green, strings.Repeat("█", filled), Reset)
+        fmt.Fprintf(out, "%s%s%s", Green, strings.Repeat("█", filled), Reset)
     }
     
     // Empty part
-    fmt.Printf("%s] %d%%", strings.Repeat("░", barWidth-filled), percent)
+    fmt.Fprintf(out, "%s] %d%%", strings.Repeat("░", barWidth-filled), percent)
 }
 
 func printColoredReceipt() {
     width := 35
     
     // Header
-    fmt.Printf("%s%s%s\n", BgBlue+White+Bold, 
+    fmt.Fprintf(out, "%s%s%s\n", BgBlue+White+Bold, 
         center("GOCOFFEE", width), Reset)
     
-    fmt.Println(strings.Repeat("─", width))
+    fmt.Fprintln(out, strings.Repeat("─", width))
     
     // Items
-    fmt.Printf("2 × %sLatte%s ............ %s$9.00%s\n", 
+    fmt.Fprintf(out, "2 × %sLatte%s ............ %s$9.00%s\n", 
         Yellow, Reset, Green, Reset)
-    fmt.Printf("1 × %sCroissant%s ........ %s$3.50%s\n", 
+    fmt.Fprintf(out, "1 × %sCroissant%s ........ %s$3.50%s\n", 
         Yellow, Reset, Green, Reset)
     
-    fmt.Println(strings.Repeat("─", width))
+    fmt.Fprintln(out, strings.Repeat("─", width))
     
     // Totals
-    fmt.Printf("Subtotal: ............ %s$12.50%s\n", Green, Reset)
-    fmt.Printf("Tax: ................. %s$1.06%s\n", Red, Reset)
+    fmt.Fprintf(out, "Subtotal: ............ %s$12.50%s\n", Green, Reset)
+    fmt.Fprintf(out, "Tax: ................. %s$1.06%s\n", Red, Reset)
     
-    fmt.Printf("%s%s%s\n", Bold, strings.Repeat("═", width), Reset)
+    fmt.Fprintf(out, "%s%s%s\n", Bold, strings.Repeat("═", width), Reset)
     
-    fmt.Printf("%s%sTOTAL: ............... $13.56%s\n", 
+    fmt.Fprintf(out, "%s%sTOTAL: ............... $13.56%s\n", 
         Bold+Green, Reset)
     
-    fmt.Printf("\n%s%sThank you for your visit!%s\n", 
+    fmt.Fprintf(out, "\n%s%sThank you for your visit!%s\n", 
         Bold+Cyan, Reset)
 }
 
+// center ignores color codes when measuring, so styled titles stay centered
 func center(s string, width int) string {
-    padding := (width - len(s)) / 2
-    return fmt.Sprintf("%*s%s%*s", padding, "", s, width-len(s)-padding, "")
+    return textlayout.Center(s, width)
 }
\ No newline at end of file
@@ -2,12 +2,20 @@ package main
 
 import (
     "fmt"
+    "os"
     "strings"
     "time"
+
+    "fmt-examples/chart"
+    "fmt-examples/style"
+    "fmt-examples/textlayout"
 )
 
+// out strips the color codes when the report goes to a file or pipe
+var out = style.NewWriter(os.Stdout)
+
 func main() {
-    fmt.Println("=== GoCoffee Real-World Formatting ===\n")
+    fmt.Fprintln(out, "=== GoCoffee Real-World Formatting ===\n")
     
     // Order tracking display
     printOrderTracking()
@@ -22,9 +30,11 @@ func main() {
     printInventoryAlert()
 }
 
+// printOrderTracking is a static view; 16_pickup_board.go is the live
+// pickup board
 func printOrderTracking() {
-    fmt.Println("ORDER TRACKING SYSTEM")
-    fmt.Println("====================\n")
+    fmt.Fprintln(out, "ORDER TRACKING SYSTEM")
+    fmt.Fprintln(out, "====================\n")
     
     orders := []struct {
         id       int
@@ -47,9 +57,9 @@ func printOrderTracking() {
         "Completed": Blue,
     }
     
-    fmt.Printf("%-6s %-15s %-6s %-12s %-10s\n", 
+    fmt.Fprintf(out, "%-6s %-15s %-6s %-12s %-10s\n", 
         "Order", "Customer", "Items", "Status", "Time")
-    fmt.Println(strings.Repeat("-", 50))
+    fmt.Fprintln(out, strings.Repeat("-", 50))
     
     for _, order := range orders {
         timeStr := "Just now"
@@ -58,7 +68,7 @@ func printOrderTracking() {
         }
         
         color := statusColor[order.status]
-        fmt.Printf("#%-5d %-15s %-6d %s%-12s%s %-10s\n",
+        fmt.Fprintf(out, "#%-5d %-15s %-6d %s%-12s%s %-10s\n",
             order.id,
             truncate(order.customer, 15),
             order.items,
@@ -66,14 +76,14 @@ func printOrderTracking() {
             timeStr)
     }
     
-    fmt.Printf("\n%s● Ready%s  %s● Preparing%s  %s● Completed%s\n\n",
+    fmt.Fprintf(out, "\n%s● Ready%s  %s● Preparing%s  %s● Completed%s\n\n",
         Green, Reset, Yellow, Reset, Blue, Reset)
 }
 
 func printDailyReport() {
-    fmt.Println("DAILY SALES REPORT")
-    fmt.Println("==================")
-    fmt.Printf("Date: %s\n\n", time.Now().Format("Monday, January 2, 2006"))
+    fmt.Fprintln(out, "DAILY SALES REPORT")
+    fmt.Fprintln(out, "==================")
+    fmt.Fprintf(out, "Date: %s\n\n", time.Now().Format("Monday, January 2, 2006"))
     
     // Hourly sales
     hours := []string{
@@ -93,12 +103,12 @@ func printDailyReport() {
         }
     }
     
-    fmt.Println("Hourly Sales:")
+    fmt.Fprintln(out, "Hourly Sales:")
     for i, hour := range hours {
         sale := sales[i]
         barLen := int(sale / maxSale * 30)
         
-        fmt.Printf("%-9s $%6.2f [%s%s]\n",
+        fmt.Fprintf(out, "%-9s $%6.2f [%s%s]\n",
             hour, sale,
             strings.Repeat("█", barLen),
             strings.Repeat("░", 30-barLen))
@@ -110,14 +120,14 @@ func printDailyReport() {
         total += sale
     }
     
-    fmt.Printf("\n%-9s $%6.2f\n", "TOTAL:", total)
-    fmt.Printf("%-9s $%6.2f\n", "Average:", total/float64(len(sales)))
-    fmt.Printf("%-9s $%6.2f (8-9 AM)\n", "Peak:", maxSale)
+    fmt.Fprintf(out, "\n%-9s $%6.2f\n", "TOTAL:", total)
+    fmt.Fprintf(out, "%-9s $%6.2f\n", "Average:", total/float64(len(sales)))
+    fmt.Fprintf(out, "%-9s $%6.2f (8-9 AM)\n", "Peak:", maxSale)
 }
 
 func printEmployeeSchedule() {
-    fmt.Println("\nEMPLOYEE SCHEDULE - TODAY")
-    fmt.Println("=========================\n")
+    fmt.Fprintln(out, "\nEMPLOYEE SCHEDULE - TODAY")
+    fmt.Fprintln(out, "=========================\n")
     
     schedule := []struct {
         name  string
@@ -133,66 +143,67 @@ func printEmployeeSchedule() {
     }
     
     // Group by shift
-    fmt.Printf("%sMORNING SHIFT%s\n", Bold, Reset)
+    fmt.Fprintf(out, "%sMORNING SHIFT%s\n", Bold, Reset)
     for _, emp := range schedule {
         if emp.shift == "Morning" {
-            fmt.Printf("  %-12s %-20s %s\n", emp.name, emp.hours, emp.role)
+            fmt.Fprintf(out, "  %-12s %-20s %s\n", emp.name, emp.hours, emp.role)
         }
     }
     
-    fmt.Printf("\n%sAFTERNOON SHIFT%s\n", Bold, Reset)
+    fmt.Fprintf(out, "\n%sAFTERNOON SHIFT%s\n", Bold, Reset)
     for _, emp := range schedule {
         if emp.shift == "Afternoon" {
-            fmt.Printf("  %-12s %-20s %s\n", emp.name, emp.hours, emp.role)
+            fmt.Fprintf(out, "  %-12s %-20s %s\n", emp.name, emp.hours, emp.role)
         }
     }
     
-    fmt.Printf("\n%sCLOSING SHIFT%s\n", Bold, Reset)
+    fmt.Fprintf(out, "\n%sCLOSING SHIFT%s\n", Bold, Reset)
     for _, emp := range schedule {
         if emp.shift == "Closing" {
-            fmt.Printf("  %-12s %-20s %s\n", emp.name, emp.hours, emp.role)
+            fmt.Fprintf(out, "  %-12s %-20s %s\n", emp.name, emp.hours, emp.role)
         }
     }
     
     // Coverage chart
-    fmt.Println("\nCOVERAGE:")
-    fmt.Println("6AM  8AM  10AM 12PM 2PM  4PM  6PM  8PM  10PM")
-    fmt.Println("├────┼────┼────┼────┼────┼────┼────┼────┼────┤")
-    
-    // Visual representation of shifts
-    shifts := []struct {
-        name  string
-        start int
-        end   int
-    }{
-        {"Sarah", 6, 14},
-        {"John", 7, 15},
-        {"Lisa", 12, 20},
-        {"Mike", 14, 22},
-        {"Emma", 15, 23},
+    fmt.Fprintln(out)
+    coverage := chart.Timeline{
+        Title: "COVERAGE:",
+        From:  6,
+        To:    23,
+        Tick:  2,
+        TickLabel: func(hour int) string {
+            return time.Date(0, 1, 1, hour, 0, 0, 0, time.UTC).Format("3PM")
+        },
+        Width: 72,
     }
-    
-    for _, shift := range shifts {
-        fmt.Printf("%-5s ", shift.name[:5])
-        
-        for hour := 6; hour < 23; hour++ {
-            if hour >= shift.start && hour < shift.end {
-                fmt.Print("████")
-            } else {
-                fmt.Print("    ")
-            }
-            
-            if hour < 22 {
-                fmt.Print(" ")
-            }
+    for _, emp := range schedule {
+        from, to, ok := shiftHours(emp.hours)
+        if ok {
+            coverage.Spans = append(coverage.Spans, chart.Span{Label: emp.name, From: from, To: to})
         }
-        fmt.Println()
+    }
+    if err := coverage.Render(out); err != nil {
+        fmt.Fprintf(out, "❌ %v\n", err)
     }
 }
 
+// shiftHours reads "6:00 AM - 2:00 PM" as hours 6 to 14
+func shiftHours(hours string) (from, to int, ok bool) {
+    start, end, found := strings.Cut(hours, " - ")
+    if !found {
+        return 0, 0, false
+    }
+    s, err1 := time.Parse("3:04 PM", start)
+    e, err2 := time.Parse("3:04 PM", end)
+    if err1 != nil || err2 != nil {
+        return 0, 0, false
+    }
+    return s.Hour(), e.Hour(), true
+}
+
 func printInventoryAlert() {
-    fmt.Println("\n⚠️  INVENTORY ALERTS")
-    fmt.Println("===================\n")
+    fmt.Fprintln(out, "\n⚠️  INVENTORY ALERTS")
+    fmt.Fprintln(out, "===================\n")
     
     inventory := []struct {
         item     string
@@ -224,7 +235,7 @@ func printInventoryAlert() {
             icon = "✗"
         }
         
-        fmt.Printf("%s%s %-20s: %.1f %s (min: %.1f)%s\n",
+        fmt.Fprintf(out, "%s%s %-20s: %.1f %s (min: %.1f)%s\n",
             color, icon, item.item, item.current, item.unit, 
             item.minimum, Reset)
         
@@ -236,21 +247,18 @@ func printInventoryAlert() {
             filled = barWidth
         }
         
-        fmt.Printf("   [%s%s%s%s] %.0f%%\n\n",
+        fmt.Fprintf(out, "   [%s%s%s%s] %.0f%%\n\n",
             color, strings.Repeat("█", filled), Reset,
             strings.Repeat("░", barWidth-filled),
             percent)
     }
     
-    fmt.Printf("%sAction Required:%s Order supplies marked as Critical immediately!\n",
+    fmt.Fprintf(out, "%sAction Required:%s Order supplies marked as Critical immediately!\n",
         Bold+Red, Reset)
 }
 
 func truncate(s string, max int) string {
-    if len(s) <= max {
-        return s
-    }
-    return s[:max-3] + "..."
+    return textlayout.Truncate(s, max)
 }
 
 // Color constants
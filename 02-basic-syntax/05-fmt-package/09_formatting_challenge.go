@@ -2,7 +2,13 @@ package main
 
 import (
     "fmt"
+    "os"
+    "sort"
     "time"
+
+    "fmt-examples/chart"
+    "fmt-examples/style"
+    "fmt-examples/table"
 )
 
 // Challenge: Create a comprehensive daily report system
@@ -44,15 +50,61 @@ func formatHeader(title string, width int) string {
     return ""
 }
 
+// formatTable uses the table package (see 12_table_builder.go)
 func formatTable(headers []string, rows [][]string) {
-    // TODO: Create an aligned table
+    columns := make([]table.Column, len(headers))
+    for i, header := range headers {
+        columns[i] = table.TextColumn(header)
+    }
+
+    t := table.New("", columns...)
+    for _, row := range rows {
+        values := make([]any, len(row))
+        for i, cell := range row {
+            values[i] = cell
+        }
+        if err := t.AddRow(values...); err != nil {
+            fmt.Println(err)
+            return
+        }
+    }
+
+    if err := t.Render(os.Stdout, table.BoxText); err != nil {
+        fmt.Println(err)
+    }
 }
 
+// createBarChart uses the chart package (see 13_terminal_charts.go)
 func createBarChart(data map[string]float64) {
-    // TODO: Create a horizontal bar chart
+    labels := make([]string, 0, len(data))
+    for label := range data {
+        labels = append(labels, label)
+    }
+    sort.Strings(labels) // Maps have no order; keep the chart stable
+
+    points := make([]chart.Point, len(labels))
+    for i, label := range labels {
+        points[i] = chart.Point{Label: label, Value: data[label]}
+    }
+
+    if err := (chart.BarChart{Points: points, Width: 60}).Horizontal(os.Stdout); err != nil {
+        fmt.Println(err)
+    }
 }
 
+// out decides whether colorByValue's codes can be shown at all
+var out = style.NewWriter(os.Stdout)
+
+// colorByValue returns the escape code for a value: green at or above the
+// threshold, yellow within 20% of it, red below that. It returns "" when
+// stdout can't show color, so callers can use it unconditionally.
 func colorByValue(value float64, threshold float64) string {
-    // TODO: Return color based on value vs threshold
-    return ""
+    switch {
+    case value >= threshold:
+        return out.Code(style.Fg(style.Green))
+    case value >= threshold*0.8:
+        return out.Code(style.Fg(style.Yellow))
+    default:
+        return out.Code(style.Fg(style.Red))
+    }
 }
\ No newline at end of file
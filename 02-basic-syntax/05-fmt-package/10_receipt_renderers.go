@@ -0,0 +1,79 @@
+package main
+
+import (
+    "bytes"
+    "encoding/hex"
+    "fmt"
+    "os"
+    "time"
+
+    "fmt-examples/receipt"
+)
+
+// printBasicReceipt, printFancyReceipt, printMinimalReceipt, Receipt.Generate
+// and GenerateReceipt all build AND print a receipt in one go. The receipt
+// package (in ./receipt) separates the two jobs: receipt.Build turns an
+// order into a Document, and a Renderer writes that document to any
+// io.Writer - plain text, HTML, JSON, or the raw bytes a thermal printer
+// understands. Its tests compare every renderer to a golden file.
+
+func main() {
+    fmt.Println("=== GoCoffee Receipt Renderers ===")
+    fmt.Println()
+
+    store := receipt.StoreInfo{
+        Name:     "GoCoffee",
+        Location: "Downtown Location",
+        Address:  "123 Coffee St, Seattle WA",
+    }
+
+    order := receipt.Order{
+        Number: 1047,
+        Items: []receipt.OrderItem{
+            {Name: "Cappuccino", Size: "Large", Price: 550, Quantity: 2, Mods: []string{"Oat Milk", "Extra Shot"}},
+            {Name: "Vanilla Latte", Size: "Medium", Price: 475, Quantity: 1, Mods: []string{"Decaf", "2 Pumps Vanilla"}},
+            {Name: "Chocolate Croissant", Price: 350, Quantity: 2},
+        },
+        TaxBasis:    850,
+        TipPercent:  18,
+        PaymentType: "Credit Card",
+        Cashier:     "Sarah M.",
+        PlacedAt:    time.Date(2024, time.March, 15, 8, 42, 0, 0, time.UTC),
+    }
+
+    doc := receipt.Build(store, order)
+
+    renderers := []struct {
+        name     string
+        renderer receipt.Renderer
+    }{
+        {"Plain text (40 columns)", receipt.TextRenderer{Width: 40}},
+        {"Plain text (32 columns)", receipt.TextRenderer{Width: 32}},
+        {"HTML", receipt.HTMLRenderer{}},
+        {"JSON", receipt.JSONRenderer{Indent: true}},
+    }
+
+    for _, r := range renderers {
+        fmt.Printf("--- %s ---\n", r.name)
+        if err := r.renderer.Render(os.Stdout, doc); err != nil {
+            fmt.Printf("❌ render failed: %v\n", err)
+        }
+        fmt.Println()
+    }
+
+    // Printer bytes aren't readable text, so show a hex dump instead
+    var printer bytes.Buffer
+    if err := (receipt.ESCPOSRenderer{}).Render(&printer, doc); err != nil {
+        fmt.Printf("❌ render failed: %v\n", err)
+        return
+    }
+    shown := min(96, printer.Len())
+    fmt.Printf("--- ESC/POS (%d bytes, first %d shown) ---\n", printer.Len(), shown)
+    fmt.Print(hex.Dump(printer.Bytes()[:shown]))
+}
+
+// Why separate the model from the renderers?
+// 1. One receipt.Build means every format shows the same numbers
+// 2. Renderers write to io.Writer: stdout, a file, a printer, an HTTP response
+// 3. No time.Now() inside a renderer, so output can be compared to a saved
+//    "golden" copy byte for byte
@@ -0,0 +1,101 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+
+    "fmt-examples/textlayout"
+)
+
+// Our menu now has "Café Crème", a Tokyo store with "抹茶ラテ", and emoji
+// everywhere. len(s) counts BYTES, so every helper that padded or cut by
+// len(s) drew crooked tables and sometimes cut a character in half.
+// The textlayout package (in ./textlayout) measures what the terminal
+// actually shows.
+
+const (
+    green = "\033[32m"
+    reset = "\033[0m"
+)
+
+// The old helpers, kept here to show what went wrong
+func oldTruncate(s string, maxLen int) string {
+    if len(s) <= maxLen {
+        return s
+    }
+    return s[:maxLen-3] + "..."
+}
+
+func oldPad(s string, width int) string {
+    return s + strings.Repeat(" ", max(0, width-len(s)))
+}
+
+func main() {
+    fmt.Println("=== GoCoffee Text Layout ===")
+    fmt.Println()
+
+    names := []string{
+        "Latte",
+        "Café Crème",
+        "Crème Brûlée Latte",
+        "抹茶ラテ",
+        "☕ Hot Chocolate",
+        green + "Oat Flat White" + reset,
+    }
+
+    // 1. Measuring
+    fmt.Println("1. Bytes vs runes vs columns:")
+    for _, name := range names {
+        fmt.Printf("   %s  len=%-3d runes=%-3d width=%d\n",
+            textlayout.PadRight(name, 20), len(name), len([]rune(name)), textlayout.Width(name))
+    }
+
+    // 2. Padding: the right border only lines up with display width
+    fmt.Println("\n2. Padding to 20 columns:")
+    fmt.Println("   Old (len)                 New (width)")
+    for _, name := range names {
+        fmt.Printf("   |%s|  |%s|\n", oldPad(name, 20), textlayout.PadRight(name, 20))
+    }
+
+    // 3. Truncating: byte slicing can cut a rune in half
+    fmt.Println("\n3. Truncating to 8 columns:")
+    for _, name := range names {
+        fmt.Printf("   %-22q → %s\n", oldTruncate(textlayout.StripANSI(name), 8), textlayout.Truncate(name, 8))
+    }
+
+    // 4. Columns
+    fmt.Println("\n4. Aligned columns:")
+    rows := [][]string{
+        {"Item", "Store", "Price"},
+        {"Café Crème", "Paris", "€3.80"},
+        {"抹茶ラテ", "東京", "¥580"},
+        {"☕ Americano", "Seattle", "$3.00"},
+        {"Crème Brûlée Latte", "Montréal", "$5.75"},
+    }
+    widths := textlayout.ColumnWidths(rows)
+    widths[0] = 14 // Force the long name to truncate
+    aligns := []textlayout.Align{textlayout.AlignLeft, textlayout.AlignCenter, textlayout.AlignRight}
+
+    for i, row := range rows {
+        fmt.Println("   " + textlayout.Row(row, widths, aligns, " │ "))
+        if i == 0 {
+            fmt.Println("   " + strings.Repeat("─", widths[0]+1) + "┼" +
+                strings.Repeat("─", widths[1]+2) + "┼" + strings.Repeat("─", widths[2]+1))
+        }
+    }
+
+    // 5. Receipt lines
+    fmt.Println("\n5. Receipt lines (32 columns):")
+    fmt.Println("   " + strings.Repeat("=", 32))
+    fmt.Println("   " + textlayout.Center("☕ GoCoffee Montréal ☕", 32))
+    fmt.Println("   " + strings.Repeat("=", 32))
+    fmt.Println("   " + textlayout.Justify("1× Café Crème", "$3.80", 32))
+    fmt.Println("   " + textlayout.Justify("2× 抹茶ラテ", "$11.60", 32))
+    fmt.Println("   " + textlayout.Justify("1× Crème Brûlée Latte with Extra Foam", "$5.75", 32))
+}
+
+// Rules of thumb:
+// 1. len(s) is bytes - use it for buffers, never for layout
+// 2. Wide characters (CJK, most emoji) take two columns
+// 3. Accents can be separate combining runes with zero width
+// 4. Color codes take bytes but no columns
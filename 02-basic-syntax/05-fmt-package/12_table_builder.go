@@ -0,0 +1,110 @@
+package main
+
+import (
+    "fmt"
+    "os"
+    "time"
+
+    "fmt-examples/table"
+)
+
+// Every table in 05_table_formatting.go hardcodes the SalesData columns.
+// The table package (in ./table) lets a report declare typed columns -
+// text, money, percent, date - and then sorts, groups, totals and renders
+// it as box-drawing text, Markdown, CSV or HTML.
+
+type Sale struct {
+    Date     time.Time
+    Product  string
+    Category string
+    Quantity int
+    Revenue  int64 // cents
+    Cost     int64 // cents
+}
+
+func weeklySales() []Sale {
+    monday := time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)
+    day := func(n int) time.Time { return monday.AddDate(0, 0, n) }
+
+    return []Sale{
+        {day(0), "Espresso", "Espresso Bar", 45, 13500, 6750},
+        {day(1), "Latte", "Espresso Bar", 62, 27900, 12400},
+        {day(2), "Cappuccino", "Espresso Bar", 38, 15200, 7600},
+        {day(3), "Cold Brew", "Cold Drinks", 41, 18450, 8200},
+        {day(4), "Iced Americano", "Cold Drinks", 29, 10150, 4350},
+        {day(5), "Croissant", "Bakery", 54, 17550, 8100},
+        {day(6), "Blueberry Muffin", "Bakery", 33, 9900, 4620},
+        {day(6), "Café Crème", "Espresso Bar", 18, 8100, 3960},
+    }
+}
+
+func main() {
+    fmt.Println("=== GoCoffee Table Builder ===")
+    fmt.Println()
+
+    report := table.New("Weekly Sales by Category",
+        table.DateColumn("Date", "Mon 01/02"),
+        table.TextColumn("Product"),
+        table.TextColumn("Category"),
+        table.NumberColumn("Qty"),
+        table.MoneyColumn("Revenue"),
+        table.MoneyColumn("Profit"),
+        table.PercentColumn("Margin"),
+    )
+
+    for _, s := range weeklySales() {
+        profit := s.Revenue - s.Cost
+        margin := float64(profit) / float64(s.Revenue)
+        if err := report.AddRow(s.Date, s.Product, s.Category, s.Quantity, s.Revenue, profit, margin); err != nil {
+            fmt.Printf("❌ %v\n", err)
+            return
+        }
+    }
+
+    // Rows are checked against the column kinds
+    if err := report.AddRow(time.Now(), "Mocha", "Espresso Bar", 18, 90.00, 36.00, 0.4); err != nil {
+        fmt.Printf("Rejected bad row: %v\n\n", err)
+    }
+
+    report.GroupBy("Category").SortBy(table.Desc("Revenue")).WithTotals()
+
+    formats := []struct {
+        name   string
+        format table.Format
+    }{
+        {"Box text", table.BoxText},
+        {"Markdown", table.Markdown},
+        {"CSV", table.CSV},
+        {"HTML", table.HTML},
+    }
+
+    for _, f := range formats {
+        fmt.Printf("--- %s ---\n", f.name)
+        if err := report.Render(os.Stdout, f.format); err != nil {
+            fmt.Printf("❌ %v\n", err)
+        }
+        fmt.Println()
+    }
+
+    // The same builder handles a quick ad-hoc table too
+    fmt.Println("--- Top sellers ---")
+    top := table.New("",
+        table.TextColumn("Product"),
+        table.NumberColumn("Qty"),
+    )
+    for _, s := range weeklySales() {
+        if err := top.AddRow(s.Product, s.Quantity); err != nil {
+            fmt.Printf("❌ %v\n", err)
+            return
+        }
+    }
+    if err := top.SortBy(table.Desc("Qty"), table.Asc("Product")).Render(os.Stdout, table.BoxText); err != nil {
+        fmt.Printf("❌ %v\n", err)
+    }
+}
+
+// What the builder gives us:
+// 1. Columns know their type, so money is always "$1,234.56" and right-aligned
+// 2. Sorting and grouping happen before rendering, in one place
+// 3. Subtotals and totals are computed from the data, never typed in
+// 4. One report, four formats
@@ -0,0 +1,140 @@
+package main
+
+import (
+    "fmt"
+    "math/rand"
+    "os"
+    "time"
+
+    "fmt-examples/chart"
+)
+
+// createBarChart in the formatting challenge was never written, and the
+// coverage bars in printEmployeeSchedule are drawn by hand. The chart
+// package (in ./chart) draws bar charts, sparklines and heatmaps that fit
+// inside a fixed width - handy for reports sent to an 80-column terminal.
+
+// Order is the same shape generateDailySummary works with
+type Order struct {
+    Time  time.Time
+    Total float64
+}
+
+// sampleWeek builds a week of orders with a morning rush and a lunch bump.
+// The seed is fixed so the charts look the same on every run.
+func sampleWeek(start time.Time) []Order {
+    rng := rand.New(rand.NewSource(42))
+    var orders []Order
+
+    for day := 0; day < 7; day++ {
+        weekend := day >= 5
+        for hour := 6; hour < 22; hour++ {
+            count := 4
+            switch {
+            case hour >= 7 && hour <= 9 && !weekend:
+                count = 18
+            case hour >= 9 && hour <= 12 && weekend:
+                count = 15
+            case hour >= 12 && hour <= 13:
+                count = 10
+            case hour >= 19:
+                count = 2
+            }
+            count += rng.Intn(4)
+
+            for i := 0; i < count; i++ {
+                when := start.AddDate(0, 0, day).Add(time.Duration(hour)*time.Hour +
+                    time.Duration(rng.Intn(60))*time.Minute)
+                orders = append(orders, Order{Time: when, Total: 3 + float64(rng.Intn(900))/100})
+            }
+        }
+    }
+    return orders
+}
+
+func money(v float64) string {
+    return fmt.Sprintf("$%.0f", v)
+}
+
+func main() {
+    fmt.Println("=== GoCoffee Terminal Charts ===")
+    fmt.Println()
+
+    monday := time.Date(2024, time.March, 11, 0, 0, 0, 0, time.UTC)
+    orders := sampleWeek(monday)
+
+    // Aggregate like generateDailySummary, but keep the detail
+    revenueByDay := make([]float64, 7)
+    revenueByHour := make([][]float64, 7)
+    countsByHour := make([][]float64, 7)
+    for day := range revenueByHour {
+        revenueByHour[day] = make([]float64, 24)
+        countsByHour[day] = make([]float64, 24)
+    }
+    for _, o := range orders {
+        day := int(o.Time.Sub(monday).Hours()) / 24
+        revenueByDay[day] += o.Total
+        revenueByHour[day][o.Time.Hour()] += o.Total
+        countsByHour[day][o.Time.Hour()]++
+    }
+
+    days := []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"}
+
+    // 1. Horizontal bars
+    var points []chart.Point
+    for i, day := range days {
+        points = append(points, chart.Point{Label: day, Value: revenueByDay[i]})
+    }
+    revenue := chart.BarChart{Title: "Revenue by day", Points: points, Width: 50, Format: money}
+    revenue.Horizontal(os.Stdout)
+    fmt.Println()
+
+    // 2. Vertical bars - same data, different shape
+    revenue.Title = "Revenue by day (vertical)"
+    revenue.Vertical(os.Stdout, 6)
+    fmt.Println()
+
+    // 3. Sparklines: one line per day of hourly revenue, 6 AM - 10 PM
+    fmt.Println("Hourly revenue, 6 AM → 10 PM")
+    for i, day := range days {
+        fmt.Printf("%s %s  %s\n", day, chart.Sparkline(revenueByHour[i][6:22], 16), money(revenueByDay[i]))
+    }
+    fmt.Println()
+
+    // A long series squeezed into fewer characters
+    var week []float64
+    for _, hours := range revenueByHour {
+        week = append(week, hours[6:22]...)
+    }
+    fmt.Printf("Whole week in 40 chars: %s\n\n", chart.Sparkline(week, 40))
+
+    // 4. Heatmap: weekday × hour order counts
+    var hours []string
+    for h := 6; h < 22; h++ {
+        hours = append(hours, fmt.Sprintf("%d", h))
+    }
+    counts := make([][]float64, 7)
+    for day := range counts {
+        counts[day] = countsByHour[day][6:22]
+    }
+    heatmap := chart.Heatmap{
+        Title:   "Orders by weekday and hour",
+        Rows:    days,
+        Columns: hours,
+        Values:  counts,
+        Width:   60,
+    }
+    heatmap.Render(os.Stdout)
+    fmt.Println()
+
+    // Charts refuse to draw instead of wrapping into a mess
+    heatmap.Width = 20
+    if err := heatmap.Render(os.Stdout); err != nil {
+        fmt.Printf("⚠️  %v\n", err)
+    }
+}
+
+// Chart tips:
+// 1. Scale bars to the largest value, not to a fixed maximum
+// 2. Eighth-blocks (▏▎▍▌▋▊▉) give 8× the resolution of whole blocks
+// 3. Decide on a width first - terminals don't scroll sideways
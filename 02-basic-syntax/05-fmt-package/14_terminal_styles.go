@@ -0,0 +1,110 @@
+package main
+
+import (
+    "bytes"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+
+    "fmt-examples/style"
+)
+
+// printSuccess and friends in 07_color_output.go used to write raw ANSI
+// codes everywhere, so "go run ... > report.txt" saved a file full of
+// "\033[32m". The style package (in ./style) checks where the output goes
+// and emits 256-color codes, 16-color codes or plain text to match.
+
+var (
+    title    = style.Fg(style.RGB(5, 3, 1)).Bold() // Caramel
+    money    = style.Fg(style.Green)
+    warning  = style.Fg(style.RGB(5, 2, 0)) // Orange - yellow on 16 colors
+    critical = style.Fg(style.BrightWhite).Bg(style.Red).Bold()
+    muted    = style.Fg(style.Gray(12))
+)
+
+// printReport is ordinary report code - it never checks the level itself
+func printReport(w *style.Writer) {
+    w.Println(title, "☕ GoCoffee Daily Report")
+    fmt.Fprintf(w, "Revenue:  %s\n", w.Sprint(money, "$2,847.50"))
+    fmt.Fprintf(w, "Milk:     %s\n", w.Sprint(warning, "low (8 gal)"))
+    fmt.Fprintf(w, "Cups:     %s\n", w.Sprint(critical, " OUT "))
+    w.Println(muted, "Generated 2024-03-15 22:00")
+}
+
+// envOf fakes an environment for DetectEnv
+func envOf(vars map[string]string) func(string) string {
+    return func(key string) string { return vars[key] }
+}
+
+func main() {
+    fmt.Println("=== GoCoffee Terminal Styles ===")
+    fmt.Println()
+
+    // 1. What does this run support?
+    stdout := style.NewWriter(os.Stdout)
+    fmt.Println("1. This run:")
+    fmt.Printf("   stdout is a terminal: %t\n", style.IsTerminal(os.Stdout))
+    fmt.Printf("   TERM=%q COLORTERM=%q NO_COLOR=%q FORCE_COLOR=%q\n",
+        os.Getenv("TERM"), os.Getenv("COLORTERM"), os.Getenv("NO_COLOR"), os.Getenv("FORCE_COLOR"))
+    fmt.Printf("   Detected: %s\n\n", stdout.Level())
+    printReport(stdout)
+    fmt.Println()
+
+    // 2. How detection decides
+    fmt.Println("2. Detection rules:")
+    cases := []struct {
+        name     string
+        terminal bool
+        env      map[string]string
+    }{
+        {"xterm-256color terminal", true, map[string]string{"TERM": "xterm-256color"}},
+        {"Basic xterm", true, map[string]string{"TERM": "xterm"}},
+        {"Truecolor terminal", true, map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"}},
+        {"Emacs shell (TERM=dumb)", true, map[string]string{"TERM": "dumb"}},
+        {"Piped to a file", false, map[string]string{"TERM": "xterm-256color"}},
+        {"NO_COLOR=1", true, map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"}},
+        {"CI log with FORCE_COLOR=1", false, map[string]string{"FORCE_COLOR": "1"}},
+        {"FORCE_COLOR=0", true, map[string]string{"TERM": "xterm-256color", "FORCE_COLOR": "0"}},
+        {"NO_COLOR beats FORCE_COLOR", true, map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "3"}},
+    }
+    for _, c := range cases {
+        fmt.Printf("   %-28s → %s\n", c.name, style.DetectEnv(c.terminal, envOf(c.env)))
+    }
+    fmt.Println()
+
+    // 3. The same report code at every level
+    fmt.Println("3. One report, three levels (first lines, escaped):")
+    for _, level := range []style.Level{style.Color256, style.Color16, style.NoColor} {
+        var buf bytes.Buffer
+        printReport(style.NewWriterLevel(&buf, level))
+
+        lines := strings.Split(buf.String(), "\n")
+        fmt.Printf("   %s:\n", level)
+        for _, line := range lines[:3] {
+            fmt.Printf("      %q\n", line)
+        }
+    }
+    fmt.Println()
+
+    // 4. Degrading 256 colors to the nearest basic color
+    fmt.Println("4. 256 → 16 colors:")
+    for _, c := range []style.Color{style.RGB(5, 3, 1), style.RGB(5, 2, 0), style.RGB(0, 2, 5), style.Gray(3), style.Gray(20)} {
+        fmt.Printf("   color %3d → basic %2d   %q → %q\n",
+            c, c.Basic(), style.Fg(c).Code(style.Color256), style.Fg(c).Code(style.Color16))
+    }
+    fmt.Println()
+
+    // 5. Old code with raw constants still comes out clean through Write
+    fmt.Println("5. Raw ANSI through a plain writer:")
+    var file bytes.Buffer
+    var w io.Writer = style.NewWriterLevel(&file, style.NoColor)
+    fmt.Fprintf(w, "%s✓ Order completed successfully!%s\n", "\033[32m", "\033[0m")
+    fmt.Printf("   %q\n", file.String())
+}
+
+// Color rules of thumb:
+// 1. Decide once per destination, not once per print
+// 2. Never color a file or pipe unless asked (FORCE_COLOR)
+// 3. Always honor NO_COLOR
+// 4. Put the newline after the reset so colors don't bleed
@@ -0,0 +1,406 @@
+package main
+
+import (
+    "bufio"
+    "errors"
+    "flag"
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "strings"
+
+    "fmt-examples/style"
+)
+
+// 06_input_formatting.go ignores the error from reader.ReadString, so at
+// the end of a piped file it gets "" forever and loops on "Invalid
+// choice". This order-entry command reads orders two ways:
+//
+//    go run 15_order_entry.go                     # prompts, Ctrl-D to finish
+//    go run 15_order_entry.go -batch orders.txt   # one order per line
+//    go run 15_order_entry.go < orders.txt        # piped stdin is batch too
+//    go run 15_order_entry.go -demo               # both modes, scripted
+//
+// Batch lines look like "Alice: 2 latte, espresso". Blank lines and
+// # comments are skipped, and every bad line is reported as file:line.
+
+type Cents int64
+
+func (c Cents) String() string {
+    return fmt.Sprintf("$%d.%02d", c/100, c%100)
+}
+
+type MenuItem struct {
+    Name  string
+    Price Cents
+}
+
+// The same menu as 06_input_formatting.go
+var menu = []MenuItem{
+    {"Espresso", 300},
+    {"Latte", 450},
+    {"Cappuccino", 400},
+    {"Americano", 350},
+}
+
+type OrderLine struct {
+    Item     MenuItem
+    Quantity int
+}
+
+type Order struct {
+    Customer string
+    Lines    []OrderLine
+}
+
+func (o Order) Total() Cents {
+    var total Cents
+    for _, line := range o.Lines {
+        total += line.Item.Price * Cents(line.Quantity)
+    }
+    return total
+}
+
+func (o Order) Summary() string {
+    var parts []string
+    for _, line := range o.Lines {
+        parts = append(parts, fmt.Sprintf("%d × %s", line.Quantity, line.Item.Name))
+    }
+    return strings.Join(parts, ", ")
+}
+
+const maxQuantity = 20
+
+func menuNames() string {
+    var names []string
+    for _, item := range menu {
+        names = append(names, strings.ToLower(item.Name))
+    }
+    return strings.Join(names, ", ")
+}
+
+// parseItem reads "2 latte", "latte" or "latte x2"
+func parseItem(text string) (OrderLine, error) {
+    fields := strings.Fields(strings.ToLower(text))
+    quantity := 1
+
+    switch {
+    case len(fields) == 0:
+        return OrderLine{}, errors.New("empty item")
+    case len(fields) >= 2 && isNumber(fields[0]):
+        quantity, _ = strconv.Atoi(fields[0])
+        fields = fields[1:]
+    case len(fields) >= 2 && strings.HasPrefix(fields[len(fields)-1], "x") && isNumber(fields[len(fields)-1][1:]):
+        quantity, _ = strconv.Atoi(fields[len(fields)-1][1:])
+        fields = fields[:len(fields)-1]
+    }
+
+    if quantity < 1 || quantity > maxQuantity {
+        return OrderLine{}, fmt.Errorf("quantity %d out of range 1-%d", quantity, maxQuantity)
+    }
+
+    name := strings.Join(fields, " ")
+    for _, item := range menu {
+        if strings.ToLower(item.Name) == name {
+            return OrderLine{Item: item, Quantity: quantity}, nil
+        }
+    }
+    return OrderLine{}, fmt.Errorf("unknown item %q (menu: %s)", name, menuNames())
+}
+
+func isNumber(s string) bool {
+    _, err := strconv.Atoi(s)
+    return err == nil
+}
+
+// parseOrder reads one batch line: "Customer: item, item, ..."
+func parseOrder(text string) (Order, error) {
+    customer, items, ok := strings.Cut(text, ":")
+    customer = strings.TrimSpace(customer)
+    if !ok || customer == "" {
+        return Order{}, errors.New(`expected "Customer: items"`)
+    }
+
+    order := Order{Customer: customer}
+    for _, part := range strings.Split(items, ",") {
+        if strings.TrimSpace(part) == "" {
+            continue
+        }
+        line, err := parseItem(part)
+        if err != nil {
+            return Order{}, err
+        }
+        order.Lines = append(order.Lines, line)
+    }
+
+    if len(order.Lines) == 0 {
+        return Order{}, fmt.Errorf("order for %s has no items", customer)
+    }
+    return order, nil
+}
+
+// === Batch mode ===
+
+// LineError is a batch problem with its position, printed like a compiler
+// error so editors can jump to it
+type LineError struct {
+    File string
+    Line int
+    Err  error
+}
+
+func (e *LineError) Error() string {
+    return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error { return e.Err }
+
+// runBatch places every valid order and returns the bad lines. Reading
+// stops cleanly at EOF; a read error stops it early and is returned too.
+func runBatch(name string, in io.Reader, out io.Writer) ([]Order, []error) {
+    var orders []Order
+    var problems []error
+
+    scanner := bufio.NewScanner(in)
+    lineNumber := 0
+    for scanner.Scan() {
+        lineNumber++
+        text := strings.TrimSpace(scanner.Text())
+        if text == "" || strings.HasPrefix(text, "#") {
+            continue
+        }
+
+        order, err := parseOrder(text)
+        if err != nil {
+            problems = append(problems, &LineError{File: name, Line: lineNumber, Err: err})
+            continue
+        }
+
+        orders = append(orders, order)
+        fmt.Fprintf(out, "  #%-3d %-8s %-36s %7s\n", len(orders), order.Customer, order.Summary(), order.Total())
+    }
+
+    if err := scanner.Err(); err != nil {
+        problems = append(problems, fmt.Errorf("%s: read failed after line %d: %w", name, lineNumber, err))
+    }
+    return orders, problems
+}
+
+// === Interactive mode ===
+
+// prompter prints a prompt and reads one line. ok is false at EOF (Ctrl-D
+// on a terminal, end of a piped file) or on a read error.
+type prompter struct {
+    scanner *bufio.Scanner
+    out     io.Writer
+    echo    bool // Print the answer, for scripted sessions
+}
+
+func (p *prompter) ask(prompt string) (string, bool) {
+    fmt.Fprint(p.out, prompt)
+    if !p.scanner.Scan() {
+        fmt.Fprintln(p.out) // Ctrl-D leaves the cursor after the prompt
+        return "", false
+    }
+    answer := strings.TrimSpace(p.scanner.Text())
+    if p.echo {
+        fmt.Fprintln(p.out, answer)
+    }
+    return answer, true
+}
+
+const maxAttempts = 3
+
+func runInteractive(in io.Reader, out io.Writer, echo bool) []Order {
+    p := &prompter{scanner: bufio.NewScanner(in), out: out, echo: echo}
+    var orders []Order
+
+    fmt.Fprintf(out, "Menu: %s\n", menuNames())
+    fmt.Fprintln(out, `Add items like "2 latte"; an empty line finishes the order.`)
+    fmt.Fprintln(out, "Press Ctrl-D at the name prompt when you're done.")
+
+    for {
+        fmt.Fprintln(out)
+        name, ok := p.ask("Customer name: ")
+        if !ok {
+            return orders
+        }
+        if name == "" {
+            continue
+        }
+
+        order, ok := readItems(p, name)
+        if !ok {
+            fmt.Fprintf(out, "⚠️  Input ended - order for %s discarded\n", name)
+            return orders
+        }
+        if len(order.Lines) == 0 {
+            fmt.Fprintf(out, "No items - order for %s skipped\n", name)
+            continue
+        }
+
+        answer, ok := p.ask(fmt.Sprintf("Confirm %s for %s? (y/n): ", order.Summary(), order.Total()))
+        if !ok {
+            fmt.Fprintf(out, "⚠️  Input ended - order for %s discarded\n", name)
+            return orders
+        }
+
+        switch strings.ToLower(answer) {
+        case "y", "yes":
+            orders = append(orders, order)
+            fmt.Fprintf(out, "✅ Order #%d placed for %s\n", len(orders), name)
+        default:
+            fmt.Fprintln(out, "❌ Order cancelled")
+        }
+    }
+}
+
+// readItems collects items until an empty line. ok is false at EOF.
+func readItems(p *prompter, customer string) (Order, bool) {
+    order := Order{Customer: customer}
+    failures := 0
+
+    for {
+        text, ok := p.ask("  item> ")
+        if !ok {
+            return Order{}, false
+        }
+        if text == "" {
+            return order, true
+        }
+
+        line, err := parseItem(text)
+        if err != nil {
+            failures++
+            fmt.Fprintf(p.out, "  ❌ %v\n", err)
+            // Don't loop forever on a broken script
+            if failures == maxAttempts {
+                fmt.Fprintf(p.out, "  Too many mistakes - finishing the order with what we have\n")
+                return order, true
+            }
+            continue
+        }
+
+        failures = 0
+        order.Lines = append(order.Lines, line)
+        fmt.Fprintf(p.out, "  ✓ %d × %s  %s\n", line.Quantity, line.Item.Name, line.Item.Price*Cents(line.Quantity))
+    }
+}
+
+func printSummary(out io.Writer, orders []Order) {
+    var total Cents
+    for _, order := range orders {
+        total += order.Total()
+    }
+    noun := "orders"
+    if len(orders) == 1 {
+        noun = "order"
+    }
+    fmt.Fprintf(out, "%d %s, %s\n", len(orders), noun, total)
+}
+
+// === Demo ===
+
+const sampleBatch = `# Morning pre-orders
+Alice: 2 latte, espresso
+Bob: cappuccino x2
+Carol: 1 mocha
+Dan: latte,, americano
+: 2 latte
+Eve: 25 espresso
+Frank
+Grace: americano
+`
+
+const sampleSession = `Alice
+2 latte
+espresso
+
+y
+Bob
+mocha
+cappuccino
+
+n
+Carol
+americano
+`
+
+func runDemo() {
+    fmt.Println("1. Batch mode (sample.txt):")
+    orders, problems := runBatch("sample.txt", strings.NewReader(sampleBatch), os.Stdout)
+    for _, problem := range problems {
+        fmt.Printf("  ❌ %v\n", problem)
+    }
+    fmt.Print("  ")
+    printSummary(os.Stdout, orders)
+
+    // Errors keep their line numbers for tools
+    var lineErr *LineError
+    if len(problems) > 0 && errors.As(problems[0], &lineErr) {
+        fmt.Printf("  First problem is on line %d\n", lineErr.Line)
+    }
+
+    fmt.Println("\n2. Interactive mode, scripted - the script ends mid-order:")
+    orders = runInteractive(strings.NewReader(sampleSession), os.Stdout, true)
+    printSummary(os.Stdout, orders)
+}
+
+// batchMain runs batch mode on a file, or stdin for "" and "-", and
+// returns the exit code: 1 if any line was rejected
+func batchMain(path string) int {
+    name, in := "stdin", io.Reader(os.Stdin)
+    if path != "" && path != "-" {
+        f, err := os.Open(path)
+        if err != nil {
+            fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+            return 1
+        }
+        defer f.Close()
+        name, in = path, f
+    }
+
+    orders, problems := runBatch(name, in, os.Stdout)
+    for _, problem := range problems {
+        fmt.Fprintln(os.Stderr, problem)
+    }
+    printSummary(os.Stdout, orders)
+
+    if len(problems) > 0 {
+        fmt.Fprintf(os.Stderr, "%d lines rejected\n", len(problems))
+        return 1
+    }
+    return 0
+}
+
+func main() {
+    batch := flag.String("batch", "", "read orders from `file` (- for stdin)")
+    demo := flag.Bool("demo", false, "run both modes on built-in input")
+    flag.Parse()
+
+    fmt.Println("=== GoCoffee Order Entry ===")
+    fmt.Println()
+
+    switch {
+    case *demo:
+        runDemo()
+
+    case *batch != "" || !style.IsTerminal(os.Stdin):
+        if code := batchMain(*batch); code != 0 {
+            os.Exit(code)
+        }
+
+    default:
+        orders := runInteractive(os.Stdin, os.Stdout, false)
+        fmt.Println()
+        printSummary(os.Stdout, orders)
+        fmt.Println("Goodbye! ☕")
+    }
+}
+
+// Input rules:
+// 1. Always check the error from a read - io.EOF means stop, not retry
+// 2. Batch input reports every bad line with its line number
+// 3. Give up after a few bad answers instead of looping forever
+// 4. Ctrl-D is just EOF on a terminal; treat it the same way
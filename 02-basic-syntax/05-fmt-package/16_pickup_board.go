@@ -0,0 +1,574 @@
+package main
+
+import (
+    "bufio"
+    "context"
+    "encoding/json"
+    "flag"
+    "fmt"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "os"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+
+    "fmt-examples/style"
+    "fmt-examples/textlayout"
+)
+
+// printOrderTracking in 08_real_world_examples.go prints five hardcoded
+// orders once. This is the board above the pickup counter: it follows
+// order status changes as they happen, shows "Preparing" and "Ready"
+// columns, and lets collected orders age off. The same board renders two
+// ways:
+//
+//    go run 16_pickup_board.go                 # scripted morning, in the terminal
+//    go run 16_pickup_board.go -serve :8080    # live board for the TV browser
+//
+// With -serve, open http://localhost:8080 on the TV: the page listens to
+// /events, a server-sent-events stream that pushes the board every time it
+// changes, so there's nothing to refresh.
+
+// === Orders ===
+
+type Stage int
+
+const (
+    Preparing Stage = iota
+    Ready
+    PickedUp
+    Cancelled
+)
+
+var stageNames = [...]string{"Preparing", "Ready", "Picked up", "Cancelled"}
+
+func (s Stage) String() string {
+    if s >= 0 && int(s) < len(stageNames) {
+        return stageNames[s]
+    }
+    return fmt.Sprintf("Stage(%d)", int(s))
+}
+
+// StatusChange is one order moving on, as the order system reports it
+type StatusChange struct {
+    Order    int
+    Customer string // Full name; the board shows only the first
+    Stage    Stage
+    At       time.Time
+}
+
+// === Board ===
+
+// Card is one order on the board
+type Card struct {
+    Order     int       `json:"order"`
+    Label     string    `json:"label"`
+    Since     time.Time `json:"since"`
+    Collected bool      `json:"collected,omitempty"`
+}
+
+// Snapshot is the whole board at one moment
+type Snapshot struct {
+    At        time.Time `json:"at"`
+    Preparing []Card    `json:"preparing"`
+    Ready     []Card    `json:"ready"`
+}
+
+type entry struct {
+    order    int
+    customer string
+    stage    Stage
+    since    time.Time
+}
+
+// Board keeps the current orders and tells subscribers when it changes.
+// It is safe to use from several goroutines.
+type Board struct {
+    // Linger is how long a collected order stays (ticked off) before it
+    // goes; Forgotten is how long an uncollected one stays in Ready
+    Linger    time.Duration
+    Forgotten time.Duration
+    // Numbers shows order numbers only, for shops that don't show names
+    Numbers bool
+
+    mu          sync.Mutex
+    entries     map[int]*entry
+    now         time.Time
+    subscribers map[chan Snapshot]bool
+}
+
+func NewBoard() *Board {
+    return &Board{
+        Linger:      30 * time.Second,
+        Forgotten:   20 * time.Minute,
+        entries:     map[int]*entry{},
+        subscribers: map[chan Snapshot]bool{},
+    }
+}
+
+// Apply records a status change and updates the board
+func (b *Board) Apply(c StatusChange) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    b.now = c.At
+    e, ok := b.entries[c.Order]
+    if !ok {
+        if c.Stage != Preparing && c.Stage != Ready {
+            return // Never shown, nothing to take down
+        }
+        e = &entry{order: c.Order, customer: c.Customer}
+        b.entries[c.Order] = e
+    }
+    if c.Stage == Cancelled {
+        delete(b.entries, c.Order)
+    } else {
+        e.stage, e.since = c.Stage, c.At
+    }
+    b.expire()
+    b.broadcast()
+}
+
+// Tick moves the board's clock on, aging off old entries
+func (b *Board) Tick(now time.Time) {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+
+    b.now = now
+    if b.expire() {
+        b.broadcast()
+    }
+}
+
+// expire removes collected and forgotten orders, reporting if any went
+func (b *Board) expire() bool {
+    removed := false
+    for id, e := range b.entries {
+        age := b.now.Sub(e.since)
+        if (e.stage == PickedUp && age >= b.Linger) || (e.stage == Ready && age >= b.Forgotten) {
+            delete(b.entries, id)
+            removed = true
+        }
+    }
+    return removed
+}
+
+// Follow applies changes from the order system until it closes the
+// channel or ctx ends, ticking every second in between
+func (b *Board) Follow(ctx context.Context, changes <-chan StatusChange, clock func() time.Time) {
+    ticker := time.NewTicker(time.Second)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case c, ok := <-changes:
+            if !ok {
+                return
+            }
+            b.Apply(c)
+        case <-ticker.C:
+            b.Tick(clock())
+        }
+    }
+}
+
+// Snapshot returns the board, each column oldest first
+func (b *Board) Snapshot() Snapshot {
+    b.mu.Lock()
+    defer b.mu.Unlock()
+    return b.snapshot()
+}
+
+func (b *Board) snapshot() Snapshot {
+    s := Snapshot{At: b.now, Preparing: []Card{}, Ready: []Card{}}
+    labels := b.labels()
+    for _, e := range b.entries {
+        card := Card{Order: e.order, Label: labels[e.order], Since: e.since, Collected: e.stage == PickedUp}
+        if e.stage == Preparing {
+            s.Preparing = append(s.Preparing, card)
+        } else {
+            s.Ready = append(s.Ready, card)
+        }
+    }
+    for _, column := range [][]Card{s.Preparing, s.Ready} {
+        sort.Slice(column, func(i, j int) bool {
+            if !column[i].Since.Equal(column[j].Since) {
+                return column[i].Since.Before(column[j].Since)
+            }
+            return column[i].Order < column[j].Order
+        })
+    }
+    return s
+}
+
+// labels picks what to show for each order: the first name, with the
+// last initial when two people on the board share one, or the number
+func (b *Board) labels() map[int]string {
+    first := map[string]int{}
+    for _, e := range b.entries {
+        if name := firstName(e.customer); name != "" {
+            first[name]++
+        }
+    }
+
+    labels := map[int]string{}
+    for id, e := range b.entries {
+        name := firstName(e.customer)
+        switch {
+        case b.Numbers || name == "":
+            labels[id] = fmt.Sprintf("#%d", id)
+        case first[name] > 1:
+            if fields := strings.Fields(e.customer); len(fields) > 1 {
+                name += " " + string([]rune(fields[len(fields)-1])[:1]) + "."
+            }
+            labels[id] = name
+        default:
+            labels[id] = name
+        }
+    }
+    return labels
+}
+
+func firstName(customer string) string {
+    if fields := strings.Fields(customer); len(fields) > 0 {
+        return fields[0]
+    }
+    return ""
+}
+
+// Subscribe returns a channel that gets the board now and after every
+// change, and a function to stop. A slow subscriber only misses
+// in-between boards, never the latest one.
+func (b *Board) Subscribe() (<-chan Snapshot, func()) {
+    ch := make(chan Snapshot, 1)
+    b.mu.Lock()
+    b.subscribers[ch] = true
+    ch <- b.snapshot()
+    b.mu.Unlock()
+
+    return ch, func() {
+        b.mu.Lock()
+        defer b.mu.Unlock()
+        if b.subscribers[ch] {
+            delete(b.subscribers, ch)
+            close(ch)
+        }
+    }
+}
+
+func (b *Board) broadcast() {
+    s := b.snapshot()
+    for ch := range b.subscribers {
+        select {
+        case <-ch: // Drop the board they haven't read yet
+        default:
+        }
+        ch <- s
+    }
+}
+
+// === Terminal view ===
+
+const columnWidth = 26
+
+func renderTerminal(w io.Writer, s Snapshot) {
+    sw := style.NewWriter(w)
+    heading := style.Fg(style.Yellow).Bold()
+    ready := style.Fg(style.Green).Bold()
+    faded := style.Fg(style.BrightBlack)
+
+    rule := "+" + strings.Repeat("-", columnWidth+2) + "+" + strings.Repeat("-", columnWidth+2) + "+"
+    cell := func(text string, s style.Style) string {
+        text = textlayout.PadRight(textlayout.Truncate(text, columnWidth), columnWidth)
+        return " " + sw.Sprint(s, text) + " "
+    }
+
+    fmt.Fprintf(w, "%s\n", rule)
+    fmt.Fprintf(w, "|%s|%s|  %s\n", cell("PREPARING", heading), cell("READY ☕", heading), s.At.Format("3:04:05 PM"))
+    fmt.Fprintf(w, "%s\n", rule)
+    for i := 0; i < max(len(s.Preparing), len(s.Ready), 1); i++ {
+        left, right := cell("", style.Style{}), cell("", style.Style{})
+        if i < len(s.Preparing) {
+            c := s.Preparing[i]
+            left = cell(fmt.Sprintf("%s (%s)", c.Label, waited(s.At, c.Since)), style.Style{})
+        }
+        if i < len(s.Ready) {
+            c := s.Ready[i]
+            if c.Collected {
+                right = cell("✓ "+c.Label, faded)
+            } else {
+                right = cell(c.Label, ready)
+            }
+        }
+        fmt.Fprintf(w, "|%s|%s|\n", left, right)
+    }
+    fmt.Fprintf(w, "%s\n", rule)
+}
+
+func waited(now, since time.Time) string {
+    d := now.Sub(since).Round(time.Second)
+    if d < time.Minute {
+        return fmt.Sprintf("%ds", int(d.Seconds()))
+    }
+    return fmt.Sprintf("%dm", int(d.Minutes()))
+}
+
+// === HTTP view ===
+
+// Server serves the TV page and its event stream
+type Server struct {
+    Board     *Board
+    Heartbeat time.Duration // Keeps proxies from closing an idle stream; 0 means 15s
+}
+
+func (s *Server) Handler() http.Handler {
+    mux := http.NewServeMux()
+    mux.HandleFunc("/", s.page)
+    mux.HandleFunc("/events", s.events)
+    return mux
+}
+
+func (s *Server) events(w http.ResponseWriter, r *http.Request) {
+    flusher, ok := w.(http.Flusher)
+    if !ok {
+        http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "text/event-stream")
+    w.Header().Set("Cache-Control", "no-cache")
+    w.Header().Set("Connection", "keep-alive")
+
+    boards, stop := s.Board.Subscribe()
+    defer stop()
+    every := s.Heartbeat
+    if every <= 0 {
+        every = 15 * time.Second
+    }
+    heartbeat := time.NewTicker(every)
+    defer heartbeat.Stop()
+
+    for {
+        select {
+        case <-r.Context().Done():
+            return
+        case <-heartbeat.C:
+            fmt.Fprint(w, ": heartbeat\n\n")
+        case snapshot := <-boards:
+            data, err := json.Marshal(snapshot)
+            if err != nil {
+                return
+            }
+            fmt.Fprintf(w, "event: board\ndata: %s\n\n", data)
+        }
+        flusher.Flush()
+    }
+}
+
+func (s *Server) page(w http.ResponseWriter, r *http.Request) {
+    if r.URL.Path != "/" {
+        http.NotFound(w, r)
+        return
+    }
+    w.Header().Set("Content-Type", "text/html; charset=utf-8")
+    io.WriteString(w, tvPage)
+}
+
+// tvPage is the whole TV display: two columns filled from /events
+const tvPage = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>GoCoffee Pickup</title>
+<style>
+  body { margin: 0; background: #1b1410; color: #f5ede4; font: 5vh system-ui, sans-serif; }
+  main { display: grid; grid-template-columns: 1fr 1fr; height: 100vh; }
+  section { padding: 4vh 4vw; }
+  h1 { font-size: 6vh; margin: 0 0 3vh; color: #d9a066; }
+  #ready { background: #24351f; }
+  #ready h1 { color: #9be07a; }
+  li { list-style: none; margin: 1.5vh 0; }
+  li.collected { opacity: .35; text-decoration: line-through; }
+  ul { padding: 0; }
+</style>
+</head>
+<body>
+<main>
+  <section><h1>Preparing</h1><ul id="preparing-list"></ul></section>
+  <section id="ready"><h1>Ready ☕</h1><ul id="ready-list"></ul></section>
+</main>
+<script>
+  function fill(id, cards) {
+    const list = document.getElementById(id);
+    list.replaceChildren(...cards.map(card => {
+      const li = document.createElement("li");
+      li.textContent = card.label;
+      if (card.collected) li.className = "collected";
+      return li;
+    }));
+  }
+  const events = new EventSource("/events");
+  events.addEventListener("board", e => {
+    const board = JSON.parse(e.data);
+    fill("preparing-list", board.preparing);
+    fill("ready-list", board.ready);
+  });
+</script>
+</body>
+</html>
+`
+
+// === Demo ===
+
+var opening = time.Date(2024, time.March, 11, 8, 0, 0, 0, time.Local)
+
+func at(minutes float64) time.Time {
+    return opening.Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+// morning is a few minutes of the order system's status changes
+func morning() []StatusChange {
+    return []StatusChange{
+        {1051, "Sarah Mills", Preparing, at(0)},
+        {1052, "John Davis", Preparing, at(0.5)},
+        {1053, "Lisa Kim", Preparing, at(1)},
+        {1051, "Sarah Mills", Ready, at(2)},
+        {1054, "Sarah Ortiz", Preparing, at(2.2)},
+        {1055, "", Preparing, at(2.5)}, // Walk-in, no name given
+        {1052, "John Davis", Ready, at(3)},
+        {1051, "Sarah Mills", PickedUp, at(3.5)},
+        {1053, "Lisa Kim", Cancelled, at(3.6)},
+        {1054, "Sarah Ortiz", Ready, at(4)},
+    }
+}
+
+func runDemo() {
+    board := NewBoard()
+
+    // 1. Replay the morning, showing the board at a few moments
+    fmt.Println("1. The board through the morning")
+    frames := map[int]string{
+        3: "Sarah's order is ready",
+        5: "A second Sarah, and a walk-in who gave no name",
+        7: "Sarah M. collects hers",
+        9: "30 seconds on she has aged off, Lisa cancelled and Sarah O. is ready",
+    }
+    for i, change := range morning() {
+        board.Apply(change)
+        if title, ok := frames[i]; ok {
+            fmt.Printf("\n  %s\n", title)
+            renderTerminal(os.Stdout, board.Snapshot())
+        }
+    }
+
+    board.Tick(at(24))
+    s := board.Snapshot()
+    fmt.Printf("\n  Twenty minutes on, uncollected orders go too: %d ready, %d preparing\n", len(s.Ready), len(s.Preparing))
+
+    // 2. Order numbers instead of names
+    numbered := NewBoard()
+    numbered.Numbers = true
+    for _, change := range morning()[:6] {
+        numbered.Apply(change)
+    }
+    fmt.Println("\n2. A shop that shows order numbers")
+    renderTerminal(os.Stdout, numbered.Snapshot())
+
+    // 3. The TV's event stream, read by a test client
+    fmt.Println("\n3. What the TV receives from /events")
+    live := NewBoard()
+    live.Tick(opening)
+    server := httptest.NewServer((&Server{Board: live, Heartbeat: time.Minute}).Handler())
+    defer server.Close()
+
+    resp, err := http.Get(server.URL + "/events")
+    if err != nil {
+        fmt.Printf("  ❌ %v\n", err)
+        return
+    }
+    defer resp.Body.Close()
+    fmt.Printf("  Content-Type: %s\n", resp.Header.Get("Content-Type"))
+
+    events := bufio.NewScanner(resp.Body)
+    next := func() string {
+        for events.Scan() {
+            if line := events.Text(); strings.HasPrefix(line, "data: ") {
+                return strings.TrimPrefix(line, "data: ")
+            }
+        }
+        return ""
+    }
+
+    fmt.Printf("  On connect: data: %s\n", next())
+    for _, change := range morning()[:4] {
+        live.Apply(change)
+        data := next()
+        var s Snapshot
+        if err := json.Unmarshal([]byte(data), &s); err != nil {
+            fmt.Printf("  ❌ %v\n", err)
+            return
+        }
+        fmt.Printf("  #%d %-9s → preparing %v, ready %v\n", change.Order, strings.ToLower(change.Stage.String()), labels(s.Preparing), labels(s.Ready))
+    }
+
+    page, err := http.Get(server.URL + "/")
+    if err == nil {
+        body, _ := io.ReadAll(page.Body)
+        page.Body.Close()
+        fmt.Printf("  The TV page is %d bytes and listens with EventSource: %t\n", len(body), strings.Contains(string(body), `new EventSource("/events")`))
+    }
+}
+
+func labels(cards []Card) []string {
+    names := []string{}
+    for _, c := range cards {
+        names = append(names, c.Label)
+    }
+    return names
+}
+
+// serve runs the live board with a stream of made-up orders, so the TV
+// has something to show
+func serve(addr string) error {
+    board := NewBoard()
+    changes := make(chan StatusChange)
+    go board.Follow(context.Background(), changes, time.Now)
+
+    go func() {
+        names := []string{"Sarah Mills", "John Davis", "Lisa Kim", "Mike Reed", "Emma Stone", "", "Sarah Ortiz"}
+        for id := 1; ; id++ {
+            name := names[id%len(names)]
+            changes <- StatusChange{id, name, Preparing, time.Now()}
+            go func(id int) {
+                time.Sleep(time.Duration(20+id%3*10) * time.Second)
+                changes <- StatusChange{id, name, Ready, time.Now()}
+                time.Sleep(time.Duration(15+id%4*10) * time.Second)
+                changes <- StatusChange{id, name, PickedUp, time.Now()}
+            }(id)
+            time.Sleep(12 * time.Second)
+        }
+    }()
+
+    fmt.Printf("Pickup board on http://%s (Ctrl-C to stop)\n", addr)
+    return http.ListenAndServe(addr, (&Server{Board: board, Heartbeat: 15 * time.Second}).Handler())
+}
+
+func main() {
+    addr := flag.String("serve", "", "serve the live board on `addr`, e.g. :8080")
+    flag.Parse()
+
+    fmt.Println("=== GoCoffee Pickup Board ===")
+    fmt.Println()
+
+    if *addr != "" {
+        if err := serve(*addr); err != nil {
+            fmt.Fprintln(os.Stderr, err)
+            os.Exit(1)
+        }
+        return
+    }
+    runDemo()
+}
@@ -0,0 +1,399 @@
+// Package chart draws small charts for terminal reports: horizontal and
+// vertical bar charts, timelines, sparklines and heatmaps. Every chart
+// fits inside a given number of columns, scaling bars and shortening
+// labels as needed.
+package chart
+
+import (
+    "fmt"
+    "io"
+    "math"
+    "strings"
+
+    "fmt-examples/textlayout"
+)
+
+// Point is one labeled value
+type Point struct {
+    Label string
+    Value float64
+}
+
+// eighths draws the fractional end of a horizontal bar
+var eighths = []string{"", "▏", "▎", "▍", "▌", "▋", "▊", "▉"}
+
+// levels draws sparklines and the top of vertical bars
+var levels = []rune("▁▂▃▄▅▆▇█")
+
+func defaultFormat(v float64) string {
+    if v == math.Trunc(v) {
+        return fmt.Sprintf("%.0f", v)
+    }
+    return fmt.Sprintf("%.2f", v)
+}
+
+// barValue is how much bar a value gets: NaN, ±Inf and negatives get
+// none, so one bad reading can't stretch or break the scale
+func barValue(v float64) float64 {
+    if math.IsNaN(v) || math.IsInf(v, 0) || v < 0 {
+        return 0
+    }
+    return v
+}
+
+func maxValue(points []Point) float64 {
+    top := 0.0
+    for _, p := range points {
+        top = math.Max(top, barValue(p.Value))
+    }
+    return top
+}
+
+// BarChart compares labeled values
+type BarChart struct {
+    Title  string
+    Points []Point
+    Width  int                  // Total columns available, default 60
+    Format func(float64) string // Value labels, default plain numbers
+}
+
+func (c BarChart) width() int {
+    if c.Width <= 0 {
+        return 60
+    }
+    return c.Width
+}
+
+func (c BarChart) format(v float64) string {
+    if c.Format != nil {
+        return c.Format(v)
+    }
+    return defaultFormat(v)
+}
+
+// Horizontal draws one bar per row:  Latte   ████████▌ 279
+// Values that can't be drawn (negative, NaN, ±Inf) get an empty bar but
+// still show their value.
+func (c BarChart) Horizontal(w io.Writer) error {
+    var b strings.Builder
+    width := c.width()
+
+    if c.Title != "" {
+        b.WriteString(c.Title + "\n")
+    }
+
+    labelWidth, valueWidth := 0, 0
+    for _, p := range c.Points {
+        labelWidth = max(labelWidth, textlayout.Width(p.Label))
+        valueWidth = max(valueWidth, textlayout.Width(c.format(p.Value)))
+    }
+    labelWidth = min(labelWidth, width/3)
+
+    barWidth := width - labelWidth - valueWidth - 3
+    if barWidth < 1 {
+        return fmt.Errorf("chart %q: %d columns is too narrow", c.Title, width)
+    }
+
+    top := maxValue(c.Points)
+    for _, p := range c.Points {
+        bar := ""
+        if v := barValue(p.Value); top > 0 && v > 0 {
+            cells := v / top * float64(barWidth) * 8
+            whole := int(cells) / 8
+            bar = strings.Repeat("█", whole) + eighths[int(cells)%8]
+        }
+        b.WriteString(textlayout.Fit(p.Label, labelWidth, textlayout.AlignLeft) + " │")
+        b.WriteString(textlayout.PadRight(bar, barWidth) + " ")
+        b.WriteString(textlayout.PadLeft(c.format(p.Value), valueWidth) + "\n")
+    }
+
+    _, err := io.WriteString(w, b.String())
+    return err
+}
+
+// Vertical draws one column per point, height rows tall. Like
+// Horizontal, it leaves out values that can't be drawn.
+func (c BarChart) Vertical(w io.Writer, height int) error {
+    if height < 1 {
+        height = 8
+    }
+    if len(c.Points) == 0 {
+        return nil
+    }
+
+    width := c.width()
+    axisWidth := textlayout.Width(c.format(maxValue(c.Points))) + 2
+    columnWidth := (width - axisWidth) / len(c.Points)
+    if columnWidth < 2 {
+        return fmt.Errorf("chart %q: %d bars don't fit in %d columns", c.Title, len(c.Points), width)
+    }
+    columnWidth = min(columnWidth, 8)
+    barWidth := max(1, columnWidth-1)
+
+    top := maxValue(c.Points)
+    var b strings.Builder
+    if c.Title != "" {
+        b.WriteString(c.Title + "\n")
+    }
+
+    for row := height; row >= 1; row-- {
+        axis := ""
+        if row == height {
+            axis = c.format(top)
+        }
+        b.WriteString(textlayout.PadLeft(axis, axisWidth-2) + " ┤")
+
+        for _, p := range c.Points {
+            cell := " "
+            if top > 0 {
+                // How many eighths of this row the bar fills
+                filled := barValue(p.Value)/top*float64(height*8) - float64((row-1)*8)
+                switch {
+                case filled >= 8:
+                    cell = "█"
+                case filled >= 1:
+                    cell = string(levels[int(filled)-1])
+                }
+            }
+            b.WriteString(strings.Repeat(cell, barWidth) + strings.Repeat(" ", columnWidth-barWidth))
+        }
+        b.WriteString("\n")
+    }
+
+    b.WriteString(strings.Repeat(" ", axisWidth-1) + "└" + strings.Repeat("─", columnWidth*len(c.Points)) + "\n")
+    b.WriteString(strings.Repeat(" ", axisWidth))
+    for _, p := range c.Points {
+        b.WriteString(textlayout.Fit(p.Label, columnWidth, textlayout.AlignLeft))
+    }
+    b.WriteString("\n")
+
+    _, err := io.WriteString(w, b.String())
+    return err
+}
+
+// Span is one labeled stretch of a timeline, From inclusive, To exclusive
+type Span struct {
+    Label    string
+    From, To int
+}
+
+// Timeline draws spans against a shared axis, such as shifts across the
+// hours of a day:
+//
+//    6AM   8AM   10AM
+//    ┼─────┼─────┼──
+//    Sarah ██████████
+type Timeline struct {
+    Title     string
+    Spans     []Span
+    From, To  int              // The axis, e.g. 6 to 23 for 6AM to 11PM
+    Tick      int              // Label every Tick units, default 1
+    TickLabel func(int) string // Default plain numbers
+    Width     int              // Total columns available, default 60
+}
+
+// Render gives every unit of the axis the same number of columns, as
+// many as fit, and labels each tick that has room for its label
+func (t Timeline) Render(w io.Writer) error {
+    width := t.Width
+    if width <= 0 {
+        width = 60
+    }
+    tick := max(t.Tick, 1)
+    label := t.TickLabel
+    if label == nil {
+        label = func(v int) string { return fmt.Sprint(v) }
+    }
+    units := t.To - t.From
+    if units < 1 {
+        return fmt.Errorf("timeline %q: axis %d-%d is empty", t.Title, t.From, t.To)
+    }
+
+    labelWidth := 0
+    for _, s := range t.Spans {
+        labelWidth = max(labelWidth, textlayout.Width(s.Label))
+    }
+    labelWidth = min(labelWidth, width/3)
+
+    cell := (width - labelWidth - 1) / units
+    if cell < 1 {
+        return fmt.Errorf("timeline %q: %d units don't fit in %d columns", t.Title, units, width)
+    }
+    axisWidth := cell * units
+
+    var b strings.Builder
+    if t.Title != "" {
+        b.WriteString(t.Title + "\n")
+    }
+
+    // Tick labels, skipping any that would overlap the previous one
+    indent := strings.Repeat(" ", labelWidth+1)
+    var header strings.Builder
+    used, next := 0, 0
+    for v := t.From; v <= t.To; v += tick {
+        pos := (v - t.From) * cell
+        text := label(v)
+        if pos < next || pos+textlayout.Width(text) > axisWidth {
+            continue
+        }
+        header.WriteString(strings.Repeat(" ", pos-used) + text)
+        used = pos + textlayout.Width(text)
+        next = used + 1
+    }
+    b.WriteString(indent + header.String() + "\n")
+
+    axis := []rune(strings.Repeat("─", axisWidth))
+    for v := t.From; v < t.To; v += tick {
+        axis[(v-t.From)*cell] = '┼'
+    }
+    b.WriteString(indent + string(axis) + "\n")
+
+    for _, s := range t.Spans {
+        row := textlayout.Fit(s.Label, labelWidth, textlayout.AlignLeft) + " "
+        for v := t.From; v < t.To; v++ {
+            fill := " "
+            if v >= s.From && v < s.To {
+                fill = "█"
+            }
+            row += strings.Repeat(fill, cell)
+        }
+        b.WriteString(strings.TrimRight(row, " ") + "\n")
+    }
+
+    _, err := io.WriteString(w, b.String())
+    return err
+}
+
+// Sparkline squeezes a series into at most width characters. Longer
+// series are averaged into buckets; the lowest value maps to ▁ and the
+// highest to █. NaN and ±Inf (a missing reading, a divide by zero) are
+// skipped.
+func Sparkline(values []float64, width int) string {
+    values = finite(values)
+    if len(values) == 0 || width <= 0 {
+        return ""
+    }
+
+    if len(values) > width {
+        buckets := make([]float64, width)
+        for i := range buckets {
+            from := i * len(values) / width
+            to := (i + 1) * len(values) / width
+            sum := 0.0
+            for _, v := range values[from:to] {
+                sum += v
+            }
+            buckets[i] = sum / float64(to-from)
+        }
+        values = buckets
+    }
+
+    low, high := values[0], values[0]
+    for _, v := range values {
+        low = math.Min(low, v)
+        high = math.Max(high, v)
+    }
+
+    var b strings.Builder
+    for _, v := range values {
+        level := len(levels) - 1
+        if high > low {
+            level = int((v - low) / (high - low) * float64(len(levels)-1))
+        }
+        b.WriteRune(levels[level])
+    }
+    return b.String()
+}
+
+func finite(values []float64) []float64 {
+    var out []float64
+    for _, v := range values {
+        if !math.IsNaN(v) && !math.IsInf(v, 0) {
+            out = append(out, v)
+        }
+    }
+    return out
+}
+
+// shades go from empty to full for heatmap cells
+var shades = []string{"  ", "░░", "▒▒", "▓▓", "██"}
+
+// Heatmap shows a grid of values, such as order counts by weekday × hour
+type Heatmap struct {
+    Title   string
+    Rows    []string
+    Columns []string
+    Values  [][]float64 // Values[row][column]
+    Width   int         // Total columns available, default 80
+}
+
+// Render draws the grid with one two-column shaded cell per value,
+// labeling every column that has room for its label
+func (h Heatmap) Render(w io.Writer) error {
+    width := h.Width
+    if width <= 0 {
+        width = 80
+    }
+
+    rowLabelWidth := 0
+    for _, label := range h.Rows {
+        rowLabelWidth = max(rowLabelWidth, textlayout.Width(label))
+    }
+
+    columns := len(h.Columns)
+    if rowLabelWidth+1+columns*2 > width {
+        return fmt.Errorf("heatmap %q: %d columns need %d characters, only %d available",
+            h.Title, columns, rowLabelWidth+1+columns*2, width)
+    }
+
+    top := 0.0
+    for _, row := range h.Values {
+        for _, v := range finite(row) {
+            top = math.Max(top, v)
+        }
+    }
+
+    var b strings.Builder
+    if h.Title != "" {
+        b.WriteString(h.Title + "\n")
+    }
+
+    // Column labels, skipping any that would overlap the previous one.
+    // Positions are display columns, so "午前" takes four, not two.
+    var header strings.Builder
+    used, next := 0, 0
+    for i, label := range h.Columns {
+        pos := rowLabelWidth + 1 + i*2
+        labelWidth := textlayout.Width(label)
+        if pos < next || pos+labelWidth > rowLabelWidth+1+columns*2 {
+            continue
+        }
+        header.WriteString(strings.Repeat(" ", pos-used) + label)
+        used = pos + labelWidth
+        next = used + 1
+    }
+    b.WriteString(header.String() + "\n")
+
+    for r, label := range h.Rows {
+        b.WriteString(textlayout.PadRight(label, rowLabelWidth) + " ")
+        for c := 0; c < columns; c++ {
+            v := 0.0
+            if r < len(h.Values) && c < len(h.Values[r]) {
+                v = h.Values[r][c]
+            }
+            shade := 0
+            if top > 0 && v > 0 && !math.IsInf(v, 1) {
+                shade = 1 + int(v/top*float64(len(shades)-2)+0.5)
+                shade = min(shade, len(shades)-1)
+            }
+            b.WriteString(shades[shade])
+        }
+        b.WriteString("\n")
+    }
+
+    b.WriteString(fmt.Sprintf("%s low %s high (max %s)\n",
+        strings.Repeat(" ", rowLabelWidth), strings.Join(shades[1:], ""), defaultFormat(top)))
+
+    _, err := io.WriteString(w, b.String())
+    return err
+}
@@ -0,0 +1,190 @@
+package chart
+
+import (
+    "fmt"
+    "math"
+    "strings"
+    "testing"
+
+    "fmt-examples/textlayout"
+)
+
+func TestHorizontal(t *testing.T) {
+    c := BarChart{
+        Points: []Point{
+            {"Latte", 4}, {"Mocha", 2}, {"Espresso", 1},
+            {"Tea", math.Inf(1)}, {"Chai", -3}, {"Cold", math.NaN()},
+        },
+        Width: 20,
+    }
+    var out strings.Builder
+    if err := c.Horizontal(&out); err != nil {
+        t.Fatal(err)
+    }
+    // Espresso is cut to the label column (a third of the width); the
+    // bars that can't be drawn are empty and don't move the scale
+    want := strings.Join([]string{
+        "Latte  │███████    4",
+        "Mocha  │███▌       2",
+        "Espre… │█▊         1",
+        "Tea    │        +Inf",
+        "Chai   │          -3",
+        "Cold   │         NaN",
+    }, "\n") + "\n"
+    if got := out.String(); got != want {
+        t.Errorf("Horizontal =\n%s\nwant\n%s", got, want)
+    }
+}
+
+func TestHorizontalEighths(t *testing.T) {
+    c := BarChart{Points: []Point{{"a", 8}, {"b", 1}}, Width: 8}
+    var out strings.Builder
+    if err := c.Horizontal(&out); err != nil {
+        t.Fatal(err)
+    }
+    // The bar is 3 columns, 24 eighths; b is 1/8 of that, three eighths
+    if !strings.Contains(out.String(), "b │▍ ") {
+        t.Errorf("Horizontal =\n%s\nwant b drawn as ▍", out.String())
+    }
+}
+
+func TestHorizontalTooNarrow(t *testing.T) {
+    c := BarChart{Points: []Point{{"Latte", 1000}}, Width: 6}
+    if err := c.Horizontal(&strings.Builder{}); err == nil {
+        t.Error("Horizontal drew a bar in 6 columns")
+    }
+}
+
+func TestVertical(t *testing.T) {
+    c := BarChart{
+        Points: []Point{{"L", 4}, {"M", 2}, {"T", math.Inf(1)}, {"C", -3}, {"N", math.NaN()}},
+        Width:  23,
+    }
+    var out strings.Builder
+    if err := c.Vertical(&out, 2); err != nil {
+        t.Fatal(err)
+    }
+    want := strings.Join([]string{
+        "4 ┤███                 ",
+        "  ┤███ ███             ",
+        "  └────────────────────",
+        "   L   M   T   C   N   ",
+    }, "\n") + "\n"
+    if got := out.String(); got != want {
+        t.Errorf("Vertical =\n%s\nwant\n%s", got, want)
+    }
+}
+
+func TestVerticalPartialRow(t *testing.T) {
+    c := BarChart{Points: []Point{{"a", 16}, {"b", 3}}, Width: 12}
+    var out strings.Builder
+    if err := c.Vertical(&out, 2); err != nil {
+        t.Fatal(err)
+    }
+    // 3/16 of two rows is three eighths of the bottom one
+    lines := strings.Split(out.String(), "\n")
+    if !strings.Contains(lines[1], "▃") {
+        t.Errorf("Vertical =\n%s\nwant b's top drawn as ▃", out.String())
+    }
+}
+
+func TestVerticalTooNarrow(t *testing.T) {
+    c := BarChart{Points: make([]Point, 10), Width: 12}
+    if err := c.Vertical(&strings.Builder{}, 4); err == nil {
+        t.Error("Vertical fit 10 bars in 12 columns")
+    }
+}
+
+func TestTimeline(t *testing.T) {
+    tl := Timeline{
+        Spans: []Span{{"Al", 1, 3}, {"Bobby", 0, 4}, {"Cy", 5, 9}},
+        From:  0, To: 4, Tick: 2,
+        TickLabel: func(v int) string { return fmt.Sprintf("%dh", v) },
+        Width: 13,
+    }
+    var out strings.Builder
+    if err := tl.Render(&out); err != nil {
+        t.Fatal(err)
+    }
+    // Labels get a third of the width; a span off the axis draws nothing
+    want := strings.Join([]string{
+        "     0h  2h",
+        "     ┼───┼───",
+        "Al     ████",
+        "Bob… ████████",
+        "Cy",
+    }, "\n") + "\n"
+    if got := out.String(); got != want {
+        t.Errorf("Render =\n%s\nwant\n%s", got, want)
+    }
+}
+
+func TestTimelineErrors(t *testing.T) {
+    for _, tl := range []Timeline{
+        {From: 5, To: 5},
+        {Spans: []Span{{"Sarah", 6, 14}}, From: 0, To: 24, Width: 20},
+    } {
+        if err := tl.Render(&strings.Builder{}); err == nil {
+            t.Errorf("Render(%+v) succeeded", tl)
+        }
+    }
+}
+
+func TestSparkline(t *testing.T) {
+    nan, inf := math.NaN(), math.Inf(1)
+    tests := []struct {
+        name   string
+        values []float64
+        width  int
+        want   string
+    }{
+        {"empty", nil, 10, ""},
+        {"rising", []float64{0, 1, 2, 3, 4, 5, 6, 7}, 10, "▁▂▃▄▅▆▇█"},
+        {"flat", []float64{3, 3, 3}, 10, "███"},
+        {"bucketed", []float64{0, 0, 7, 7}, 2, "▁█"},
+        {"NaN skipped", []float64{0, nan, 7}, 10, "▁█"},
+        {"Inf skipped", []float64{0, inf, 7, math.Inf(-1)}, 10, "▁█"},
+        {"only NaN", []float64{nan, nan}, 10, ""},
+        {"NaN while bucketing", []float64{0, nan, 0, 7, nan, 7}, 2, "▁█"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := Sparkline(tt.values, tt.width); got != tt.want {
+                t.Errorf("Sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestHeatmapHeaderUsesDisplayWidth(t *testing.T) {
+    h := Heatmap{
+        Rows:    []string{"Mon", "Tue"},
+        Columns: []string{"午前", "", "", "午後", "", ""},
+        Values:  [][]float64{{1, 2, 3, 4, 5, 6}, {6, 5, 4, 3, 2, math.NaN()}},
+    }
+
+    var out strings.Builder
+    if err := h.Render(&out); err != nil {
+        t.Fatal(err)
+    }
+    lines := strings.Split(out.String(), "\n")
+
+    // Each label starts over its own column: row label, a space, 2 per cell
+    header := lines[0]
+    if got, want := textlayout.Width(header[:strings.Index(header, "午前")]), 4; got != want {
+        t.Errorf("午前 starts at column %d, want %d\n%s", got, want, header)
+    }
+    if got, want := textlayout.Width(header[:strings.Index(header, "午後")]), 4+3*2; got != want {
+        t.Errorf("午後 starts at column %d, want %d\n%s", got, want, header)
+    }
+    if got, want := textlayout.Width(header), textlayout.Width(lines[1]); got > want {
+        t.Errorf("header is %d columns, wider than the %d-column grid", got, want)
+    }
+}
+
+func TestHeatmapTooNarrow(t *testing.T) {
+    h := Heatmap{Rows: []string{"Mon"}, Columns: make([]string, 24), Width: 20}
+    if err := h.Render(&strings.Builder{}); err == nil {
+        t.Error("Render fit 24 columns into 20 characters")
+    }
+}
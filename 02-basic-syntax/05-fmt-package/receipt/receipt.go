@@ -0,0 +1,327 @@
+// Package receipt separates what a receipt says from how it looks.
+//
+// printBasicReceipt, printFancyReceipt, printMinimalReceipt, Receipt.Generate
+// and GenerateReceipt all build AND print a receipt in one go. Here the two
+// jobs are separated: Build turns an order into a Document, and a
+// Renderer writes that document to any io.Writer - plain text, HTML, JSON,
+// or the raw bytes a thermal printer understands. Renderers never call
+// time.Now(), so the same document always renders to the same bytes.
+package receipt
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "html/template"
+    "io"
+    "strings"
+    "time"
+    "unicode/utf8"
+)
+
+// Cents keeps money as whole cents
+type Cents int64
+
+func (c Cents) String() string {
+    sign := ""
+    if c < 0 {
+        sign = "-"
+        c = -c
+    }
+    return fmt.Sprintf("%s$%d.%02d", sign, c/100, c%100)
+}
+
+// === Order (input) ===
+
+type OrderItem struct {
+    Name     string
+    Size     string
+    Price    Cents
+    Quantity int
+    Mods     []string
+}
+
+type Order struct {
+    Number      int
+    Items       []OrderItem
+    TaxBasis    int // 850 = 8.50%
+    TipPercent  int
+    PaymentType string
+    Cashier     string
+    PlacedAt    time.Time
+}
+
+// === Receipt Document (model) ===
+
+type StoreInfo struct {
+    Name     string `json:"name"`
+    Location string `json:"location"`
+    Address  string `json:"address"`
+}
+
+type Line struct {
+    Quantity    int      `json:"quantity"`
+    Description string   `json:"description"`
+    Mods        []string `json:"mods,omitempty"`
+    Amount      Cents    `json:"amount_cents"`
+}
+
+type TotalLine struct {
+    Label  string `json:"label"`
+    Amount Cents  `json:"amount_cents"`
+    Grand  bool   `json:"grand,omitempty"`
+}
+
+// Document holds everything a receipt shows and nothing about how it looks
+type Document struct {
+    Store       StoreInfo   `json:"store"`
+    OrderNumber int         `json:"order_number"`
+    Date        time.Time   `json:"date"`
+    Cashier     string      `json:"cashier"`
+    Lines       []Line      `json:"lines"`
+    Totals      []TotalLine `json:"totals"`
+    PaymentType string      `json:"payment_type"`
+    Footer      []string    `json:"footer"`
+}
+
+func Build(store StoreInfo, order Order) Document {
+    doc := Document{
+        Store:       store,
+        OrderNumber: order.Number,
+        Date:        order.PlacedAt,
+        Cashier:     order.Cashier,
+        PaymentType: order.PaymentType,
+        Footer:      []string{"Thank you for visiting!", "gocoffee.com/feedback"},
+    }
+
+    var subtotal Cents
+    for _, item := range order.Items {
+        description := item.Name
+        if item.Size != "" {
+            description = item.Size + " " + item.Name
+        }
+
+        amount := item.Price * Cents(item.Quantity)
+        subtotal += amount
+
+        doc.Lines = append(doc.Lines, Line{
+            Quantity:    item.Quantity,
+            Description: description,
+            Mods:        item.Mods,
+            Amount:      amount,
+        })
+    }
+
+    tax := (subtotal*Cents(order.TaxBasis) + 5000) / 10000
+    tip := (subtotal*Cents(order.TipPercent) + 50) / 100
+
+    doc.Totals = []TotalLine{
+        {Label: "Subtotal", Amount: subtotal},
+        {Label: fmt.Sprintf("Tax (%d.%02d%%)", order.TaxBasis/100, order.TaxBasis%100), Amount: tax},
+    }
+    if tip > 0 {
+        doc.Totals = append(doc.Totals, TotalLine{Label: fmt.Sprintf("Tip (%d%%)", order.TipPercent), Amount: tip})
+    }
+    doc.Totals = append(doc.Totals, TotalLine{Label: "TOTAL", Amount: subtotal + tax + tip, Grand: true})
+
+    return doc
+}
+
+// === Renderers ===
+
+// Renderer writes a receipt document in one output format
+type Renderer interface {
+    Render(w io.Writer, doc Document) error
+}
+
+// --- Plain text ---
+
+type TextRenderer struct {
+    Width int
+}
+
+func (r TextRenderer) Render(w io.Writer, doc Document) error {
+    var b strings.Builder
+    width := r.Width
+    if width < 24 {
+        width = 24
+    }
+
+    line := func(ch string) { b.WriteString(strings.Repeat(ch, width) + "\n") }
+    center := func(text string) {
+        pad := (width - utf8.RuneCountInString(text)) / 2
+        if pad < 0 {
+            pad = 0
+        }
+        b.WriteString(strings.Repeat(" ", pad) + text + "\n")
+    }
+    row := func(left, right string) {
+        gap := width - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+        if gap < 1 {
+            gap = 1
+        }
+        b.WriteString(left + strings.Repeat(" ", gap) + right + "\n")
+    }
+
+    line("=")
+    center(strings.ToUpper(doc.Store.Name))
+    center(doc.Store.Location)
+    center(doc.Store.Address)
+    line("=")
+    row(fmt.Sprintf("Order #%d", doc.OrderNumber), doc.Date.Format("Jan 2, 2006 3:04 PM"))
+    b.WriteString("Cashier: " + doc.Cashier + "\n")
+    line("-")
+
+    for _, l := range doc.Lines {
+        row(fmt.Sprintf("%d %s", l.Quantity, l.Description), l.Amount.String())
+        for _, mod := range l.Mods {
+            b.WriteString("  + " + mod + "\n")
+        }
+    }
+
+    line("-")
+    for _, t := range doc.Totals {
+        if t.Grand {
+            line("=")
+        }
+        row(t.Label+":", t.Amount.String())
+    }
+    line("=")
+    row("Paid by:", doc.PaymentType)
+    b.WriteString("\n")
+    for _, text := range doc.Footer {
+        center(text)
+    }
+
+    _, err := io.WriteString(w, b.String())
+    return err
+}
+
+// --- HTML ---
+
+var receiptHTML = template.Must(template.New("receipt").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Store.Name}} - Order #{{.OrderNumber}}</title></head>
+<body>
+<div class="receipt">
+  <header>
+    <h1>{{.Store.Name}}</h1>
+    <p>{{.Store.Location}}<br>{{.Store.Address}}</p>
+    <p>Order #{{.OrderNumber}} &middot; {{.Date.Format "Jan 2, 2006 3:04 PM"}} &middot; Cashier: {{.Cashier}}</p>
+  </header>
+  <table class="items">
+{{- range .Lines}}
+    <tr><td>{{.Quantity}}</td><td>{{.Description}}{{range .Mods}}<br><small>+ {{.}}</small>{{end}}</td><td class="amount">{{.Amount}}</td></tr>
+{{- end}}
+  </table>
+  <table class="totals">
+{{- range .Totals}}
+    <tr{{if .Grand}} class="grand"{{end}}><td>{{.Label}}</td><td class="amount">{{.Amount}}</td></tr>
+{{- end}}
+  </table>
+  <p>Paid by {{.PaymentType}}</p>
+  <footer>{{range .Footer}}<p>{{.}}</p>{{end}}</footer>
+</div>
+</body>
+</html>
+`))
+
+type HTMLRenderer struct{}
+
+func (HTMLRenderer) Render(w io.Writer, doc Document) error {
+    return receiptHTML.Execute(w, doc)
+}
+
+// --- JSON ---
+
+type JSONRenderer struct {
+    Indent bool
+}
+
+func (r JSONRenderer) Render(w io.Writer, doc Document) error {
+    encoder := json.NewEncoder(w)
+    if r.Indent {
+        encoder.SetIndent("", "  ")
+    }
+    return encoder.Encode(doc)
+}
+
+// --- ESC/POS thermal printer ---
+
+// ESC/POS command bytes understood by most receipt printers
+var (
+    escInit       = []byte{0x1B, '@'}                  // Reset printer
+    escAlignLeft  = []byte{0x1B, 'a', 0}               // Left align
+    escAlignCtr   = []byte{0x1B, 'a', 1}               // Center align
+    escBoldOn     = []byte{0x1B, 'E', 1}               // Bold on
+    escBoldOff    = []byte{0x1B, 'E', 0}               // Bold off
+    escDoubleSize = []byte{0x1D, '!', 0x11}            // Double width + height
+    escNormalSize = []byte{0x1D, '!', 0x00}            // Normal size
+    escFeedCut    = []byte{0x1B, 'd', 3, 0x1D, 'V', 0} // Feed 3 lines, full cut
+)
+
+// ESCPOSRenderer emits a byte stream for an 80mm printer (42 columns)
+type ESCPOSRenderer struct {
+    Columns int
+}
+
+func (r ESCPOSRenderer) Render(w io.Writer, doc Document) error {
+    columns := r.Columns
+    if columns == 0 {
+        columns = 42
+    }
+
+    var buf bytes.Buffer
+    text := func(s string) { buf.WriteString(s + "\n") }
+    row := func(left, right string) {
+        gap := columns - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+        if gap < 1 {
+            gap = 1
+        }
+        text(left + strings.Repeat(" ", gap) + right)
+    }
+
+    buf.Write(escInit)
+    buf.Write(escAlignCtr)
+    buf.Write(escDoubleSize)
+    text(strings.ToUpper(doc.Store.Name))
+    buf.Write(escNormalSize)
+    text(doc.Store.Location)
+    text(doc.Store.Address)
+
+    buf.Write(escAlignLeft)
+    text(strings.Repeat("-", columns))
+    row(fmt.Sprintf("Order #%d", doc.OrderNumber), doc.Date.Format("01/02/06 15:04"))
+    text("Cashier: " + doc.Cashier)
+    text(strings.Repeat("-", columns))
+
+    for _, l := range doc.Lines {
+        row(fmt.Sprintf("%d %s", l.Quantity, l.Description), l.Amount.String())
+        for _, mod := range l.Mods {
+            text("  + " + mod)
+        }
+    }
+
+    text(strings.Repeat("-", columns))
+    for _, t := range doc.Totals {
+        if t.Grand {
+            buf.Write(escBoldOn)
+            row(t.Label, t.Amount.String())
+            buf.Write(escBoldOff)
+            continue
+        }
+        row(t.Label, t.Amount.String())
+    }
+    text(strings.Repeat("-", columns))
+    row("Paid by", doc.PaymentType)
+    text("")
+
+    buf.Write(escAlignCtr)
+    for _, footer := range doc.Footer {
+        text(footer)
+    }
+    buf.Write(escFeedCut)
+
+    _, err := w.Write(buf.Bytes())
+    return err
+}
@@ -0,0 +1,107 @@
+package receipt
+
+import (
+    "bytes"
+    "flag"
+    "os"
+    "path/filepath"
+    "testing"
+    "time"
+)
+
+// Run `go test ./receipt -update` after an intended change to a renderer,
+// then review the diff of testdata/ before committing it
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/")
+
+func testDocument() Document {
+    store := StoreInfo{
+        Name:     "GoCoffee",
+        Location: "Downtown Location",
+        Address:  "123 Coffee St, Seattle WA",
+    }
+    order := Order{
+        Number: 1047,
+        Items: []OrderItem{
+            {Name: "Cappuccino", Size: "Large", Price: 550, Quantity: 2, Mods: []string{"Oat Milk", "Extra Shot"}},
+            {Name: "Vanilla Latte", Size: "Medium", Price: 475, Quantity: 1, Mods: []string{"Decaf", "2 Pumps Vanilla"}},
+            {Name: "Chocolate Croissant", Price: 350, Quantity: 2},
+        },
+        TaxBasis:    850,
+        TipPercent:  18,
+        PaymentType: "Credit Card",
+        Cashier:     "Sarah M.",
+        PlacedAt:    time.Date(2024, time.March, 15, 8, 42, 0, 0, time.UTC),
+    }
+    return Build(store, order)
+}
+
+func TestRenderersGolden(t *testing.T) {
+    tests := []struct {
+        golden   string
+        renderer Renderer
+    }{
+        {"text_40.golden", TextRenderer{Width: 40}},
+        {"text_32.golden", TextRenderer{Width: 32}},
+        {"html.golden", HTMLRenderer{}},
+        {"json.golden", JSONRenderer{Indent: true}},
+        {"escpos.golden", ESCPOSRenderer{}},
+    }
+
+    doc := testDocument()
+    for _, tt := range tests {
+        t.Run(tt.golden, func(t *testing.T) {
+            var got bytes.Buffer
+            if err := tt.renderer.Render(&got, doc); err != nil {
+                t.Fatalf("Render: %v", err)
+            }
+
+            path := filepath.Join("testdata", tt.golden)
+            if *update {
+                if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            want, err := os.ReadFile(path)
+            if err != nil {
+                t.Fatalf("%v (run with -update to create it)", err)
+            }
+            if !bytes.Equal(got.Bytes(), want) {
+                t.Errorf("output differs from %s\ngot:\n%s\nwant:\n%s", path, got.Bytes(), want)
+            }
+        })
+    }
+}
+
+// Every format has to show who served the customer and how they paid
+func TestRenderersShowCashierAndPayment(t *testing.T) {
+    doc := testDocument()
+    for _, r := range []Renderer{TextRenderer{Width: 40}, HTMLRenderer{}, JSONRenderer{}, ESCPOSRenderer{}} {
+        var out bytes.Buffer
+        if err := r.Render(&out, doc); err != nil {
+            t.Fatalf("%T: Render: %v", r, err)
+        }
+        for _, want := range []string{doc.Cashier, doc.PaymentType} {
+            if !bytes.Contains(out.Bytes(), []byte(want)) {
+                t.Errorf("%T output is missing %q", r, want)
+            }
+        }
+    }
+}
+
+func TestCentsString(t *testing.T) {
+    tests := []struct {
+        in   Cents
+        want string
+    }{
+        {0, "$0.00"},
+        {5, "$0.05"},
+        {1234, "$12.34"},
+        {-5, "-$0.05"},
+        {-1234, "-$12.34"},
+    }
+    for _, tt := range tests {
+        if got := tt.in.String(); got != tt.want {
+            t.Errorf("Cents(%d).String() = %q, want %q", tt.in, got, tt.want)
+        }
+    }
+}
@@ -17,6 +17,13 @@ examples=(
     "07_color_output.go"
     "08_real_world_examples.go"
     "09_formatting_challenge.go"
+    "10_receipt_renderers.go"
+    "11_text_layout.go"
+    "12_table_builder.go"
+    "13_terminal_charts.go"
+    "14_terminal_styles.go"
+    "15_order_entry.go"
+    "16_pickup_board.go"
 )
 
 # Run each example
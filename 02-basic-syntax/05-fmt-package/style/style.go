@@ -0,0 +1,408 @@
+// Package style writes colored terminal output that degrades gracefully.
+//
+// The color helpers in this chapter print raw ANSI codes no matter where
+// the output goes, so a report piped into a file is full of "\033[32m".
+// A Writer detects what its destination can show - 256 colors, the basic
+// 16, or none at all - and emits only the codes that will work there.
+// NO_COLOR (https://no-color.org) and FORCE_COLOR are respected.
+package style
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "strings"
+
+    "fmt-examples/textlayout"
+)
+
+// Level is how many colors a destination can show
+type Level int
+
+const (
+    NoColor  Level = iota // Plain text: files, pipes, NO_COLOR
+    Color16               // The basic 8 colors plus their bright versions
+    Color256              // xterm's 256-color palette
+)
+
+func (l Level) String() string {
+    switch l {
+    case Color16:
+        return "16 colors"
+    case Color256:
+        return "256 colors"
+    }
+    return "no color"
+}
+
+// Color is an index into the 256-color palette. The first 16 entries are
+// the basic colors every color terminal supports.
+type Color uint8
+
+const (
+    Black Color = iota
+    Red
+    Green
+    Yellow
+    Blue
+    Magenta
+    Cyan
+    White
+    BrightBlack
+    BrightRed
+    BrightGreen
+    BrightYellow
+    BrightBlue
+    BrightMagenta
+    BrightCyan
+    BrightWhite
+)
+
+// RGB picks the closest color from the 6×6×6 cube of the 256-color
+// palette; each component is 0-5
+func RGB(r, g, b int) Color {
+    clamp := func(v int) int { return max(0, min(5, v)) }
+    return Color(16 + 36*clamp(r) + 6*clamp(g) + clamp(b))
+}
+
+// Gray picks one of the 24 grays of the 256-color palette, 0 (dark) to 23
+func Gray(n int) Color {
+    return Color(232 + max(0, min(23, n)))
+}
+
+// palette16 is the usual xterm rendering of the 16 basic colors
+var palette16 = [16][3]int{
+    {0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+    {0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+    {127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+    {92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+func (c Color) rgb() [3]int {
+    switch {
+    case c < 16:
+        return palette16[c]
+    case c < 232:
+        level := func(v int) int {
+            if v == 0 {
+                return 0
+            }
+            return 55 + 40*v
+        }
+        i := int(c) - 16
+        return [3]int{level(i / 36), level(i / 6 % 6), level(i % 6)}
+    default:
+        gray := 8 + 10*(int(c)-232)
+        return [3]int{gray, gray, gray}
+    }
+}
+
+// Basic returns the nearest of the 16 basic colors
+func (c Color) Basic() Color {
+    if c < 16 {
+        return c
+    }
+
+    return nearestBasic(c.rgb())
+}
+
+// nearestBasic returns the basic color closest to an RGB value
+func nearestBasic(target [3]int) Color {
+    best, bestDist := Black, -1
+    for i, p := range palette16 {
+        dist := 0
+        for k := range p {
+            d := p[k] - target[k]
+            dist += d * d
+        }
+        if bestDist < 0 || dist < bestDist {
+            best, bestDist = Color(i), dist
+        }
+    }
+    return best
+}
+
+// params returns the SGR parameters for c as a foreground (base 30) or
+// background (base 40) color
+func (c Color) params(level Level, base int) string {
+    if level == Color256 && c >= 16 {
+        return fmt.Sprintf("%d;5;%d", base+8, c)
+    }
+    c = c.Basic()
+    if c < 8 {
+        return strconv.Itoa(base + int(c))
+    }
+    return strconv.Itoa(base + 60 + int(c) - 8)
+}
+
+// Style is a combination of colors and attributes. The zero Style changes
+// nothing; build one with Fg or Bg and chain the methods:
+//
+//    style.Fg(style.Red).Bold()
+type Style struct {
+    fg, bg       Color
+    hasFg, hasBg bool
+    bold         bool
+    underline    bool
+}
+
+// Fg starts a style with a foreground color
+func Fg(c Color) Style {
+    return Style{}.Fg(c)
+}
+
+// Bg starts a style with a background color
+func Bg(c Color) Style {
+    return Style{}.Bg(c)
+}
+
+func (s Style) Fg(c Color) Style {
+    s.fg, s.hasFg = c, true
+    return s
+}
+
+func (s Style) Bg(c Color) Style {
+    s.bg, s.hasBg = c, true
+    return s
+}
+
+func (s Style) Bold() Style {
+    s.bold = true
+    return s
+}
+
+func (s Style) Underline() Style {
+    s.underline = true
+    return s
+}
+
+// Code returns the escape sequence that turns s on at the given level,
+// or "" when there is nothing to turn on
+func (s Style) Code(level Level) string {
+    if level == NoColor {
+        return ""
+    }
+
+    var params []string
+    if s.bold {
+        params = append(params, "1")
+    }
+    if s.underline {
+        params = append(params, "4")
+    }
+    if s.hasFg {
+        params = append(params, s.fg.params(level, 30))
+    }
+    if s.hasBg {
+        params = append(params, s.bg.params(level, 40))
+    }
+
+    if len(params) == 0 {
+        return ""
+    }
+    return "\033[" + strings.Join(params, ";") + "m"
+}
+
+// Reset turns every style off
+const Reset = "\033[0m"
+
+// === Detection ===
+
+// IsTerminal reports whether w is a terminal rather than a file or pipe
+func IsTerminal(w io.Writer) bool {
+    f, ok := w.(*os.File)
+    if !ok {
+        return false
+    }
+    info, err := f.Stat()
+    if err != nil {
+        return false
+    }
+    return info.Mode()&os.ModeCharDevice != 0
+}
+
+// Detect works out the color level for w from the environment
+func Detect(w io.Writer) Level {
+    return DetectEnv(IsTerminal(w), os.Getenv)
+}
+
+// DetectEnv decides the color level from whether the output is a terminal
+// and the environment variables returned by getenv. In order:
+//
+//  1. NO_COLOR set to anything turns color off
+//  2. FORCE_COLOR overrides detection: 0 is off, 1 is 16 colors, 2 or 3
+//     is 256 colors
+//  3. Pipes, files and TERM=dumb get no color
+//  4. TERM=*256color* or COLORTERM=truecolor/24bit get 256 colors
+//  5. Any other terminal gets 16 colors
+func DetectEnv(isTerminal bool, getenv func(string) string) Level {
+    if getenv("NO_COLOR") != "" {
+        return NoColor
+    }
+
+    switch strings.ToLower(getenv("FORCE_COLOR")) {
+    case "":
+        // Not forced - detect below
+    case "0", "false":
+        return NoColor
+    case "2", "3":
+        return Color256
+    default:
+        return Color16
+    }
+
+    term := getenv("TERM")
+    if !isTerminal || term == "dumb" {
+        return NoColor
+    }
+
+    colorterm := getenv("COLORTERM")
+    if strings.Contains(term, "256color") || colorterm == "truecolor" || colorterm == "24bit" {
+        return Color256
+    }
+    return Color16
+}
+
+// === Writer ===
+
+// Writer prints styled text at the level its destination supports
+type Writer struct {
+    w     io.Writer
+    level Level
+}
+
+// NewWriter wraps w and detects its color level
+func NewWriter(w io.Writer) *Writer {
+    return &Writer{w: w, level: Detect(w)}
+}
+
+// NewWriterLevel wraps w with a fixed color level
+func NewWriterLevel(w io.Writer, level Level) *Writer {
+    return &Writer{w: w, level: level}
+}
+
+func (w *Writer) Level() Level {
+    return w.level
+}
+
+// Write passes p through at the destination's level: escape codes are
+// removed when it has no color, and 256-color and 24-bit codes become
+// their nearest basic color when it has 16. This lets code that still
+// builds strings with raw ANSI constants write through a Writer and
+// produce clean files. Each call should carry whole escape sequences, as
+// fmt.Fprintf does.
+func (w *Writer) Write(p []byte) (int, error) {
+    var out string
+    switch w.level {
+    case Color256:
+        return w.w.Write(p)
+    case Color16:
+        out = degrade(string(p))
+    default:
+        out = textlayout.StripANSI(string(p))
+    }
+    if _, err := io.WriteString(w.w, out); err != nil {
+        return 0, err
+    }
+    return len(p), nil
+}
+
+// degrade rewrites the extended colors in every SGR sequence of s
+// ("\033[...m") as basic ones, leaving everything else alone
+func degrade(s string) string {
+    var b strings.Builder
+    for {
+        start := strings.Index(s, "\033[")
+        if start < 0 {
+            b.WriteString(s)
+            return b.String()
+        }
+        end := start + 2
+        for end < len(s) && (s[end] < 0x40 || s[end] > 0x7e) {
+            end++
+        }
+        if end == len(s) { // Unterminated; nothing to rewrite
+            b.WriteString(s)
+            return b.String()
+        }
+        b.WriteString(s[:start])
+        if s[end] == 'm' {
+            b.WriteString("\033[" + degradeParams(s[start+2:end]) + "m")
+        } else {
+            b.WriteString(s[start : end+1])
+        }
+        s = s[end+1:]
+    }
+}
+
+// degradeParams turns 38;5;n and 38;2;r;g;b (48 for backgrounds) into the
+// nearest of the 16 basic colors
+func degradeParams(params string) string {
+    in := strings.Split(params, ";")
+    var out []string
+    for i := 0; i < len(in); i++ {
+        base := 0
+        switch in[i] {
+        case "38":
+            base = 30
+        case "48":
+            base = 40
+        }
+        if base == 0 || i+1 >= len(in) {
+            out = append(out, in[i])
+            continue
+        }
+
+        switch n := atoiAll(in[i+2 : min(i+5, len(in))]); {
+        case in[i+1] == "5" && len(n) >= 1:
+            out = append(out, Color(min(n[0], 255)).params(Color16, base))
+            i += 2
+        case in[i+1] == "2" && len(n) >= 3:
+            out = append(out, nearestBasic([3]int{n[0], n[1], n[2]}).params(Color16, base))
+            i += 4
+        default:
+            out = append(out, in[i])
+        }
+    }
+    return strings.Join(out, ";")
+}
+
+// atoiAll parses the leading run of numeric parameters, up to r;g;b
+func atoiAll(params []string) []int {
+    var n []int
+    for _, p := range params {
+        v, err := strconv.Atoi(p)
+        if err != nil || v < 0 {
+            break
+        }
+        n = append(n, v)
+    }
+    return n
+}
+
+// Code returns the escape sequence for s at this writer's level
+func (w *Writer) Code(s Style) string {
+    return s.Code(w.level)
+}
+
+// Sprint formats a like fmt.Sprint and wraps it in s
+func (w *Writer) Sprint(s Style, a ...any) string {
+    text := fmt.Sprint(a...)
+    code := w.Code(s)
+    if code == "" {
+        return text
+    }
+    return code + text + Reset
+}
+
+// Printf formats like fmt.Printf and writes the text in s
+func (w *Writer) Printf(s Style, format string, a ...any) (int, error) {
+    return io.WriteString(w.w, w.Sprint(s, fmt.Sprintf(format, a...)))
+}
+
+// Println writes a in s followed by a newline. The newline comes after
+// the reset so the style never bleeds into the next line.
+func (w *Writer) Println(s Style, a ...any) (int, error) {
+    return io.WriteString(w.w, w.Sprint(s, strings.TrimSuffix(fmt.Sprintln(a...), "\n"))+"\n")
+}
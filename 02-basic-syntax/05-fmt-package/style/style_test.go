@@ -0,0 +1,84 @@
+package style
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestDetectEnv(t *testing.T) {
+    tests := []struct {
+        name     string
+        terminal bool
+        env      map[string]string
+        want     Level
+    }{
+        {"plain terminal", true, map[string]string{"TERM": "xterm"}, Color16},
+        {"256color terminal", true, map[string]string{"TERM": "xterm-256color"}, Color256},
+        {"screen 256color", true, map[string]string{"TERM": "screen-256color"}, Color256},
+        {"truecolor", true, map[string]string{"TERM": "xterm", "COLORTERM": "truecolor"}, Color256},
+        {"dumb terminal", true, map[string]string{"TERM": "dumb"}, NoColor},
+        {"pipe", false, map[string]string{"TERM": "xterm-256color"}, NoColor},
+        {"NO_COLOR", true, map[string]string{"TERM": "xterm-256color", "NO_COLOR": "1"}, NoColor},
+        {"NO_COLOR beats FORCE_COLOR", true, map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "3"}, NoColor},
+        {"FORCE_COLOR on a pipe", false, map[string]string{"FORCE_COLOR": "1"}, Color16},
+        {"FORCE_COLOR=2", false, map[string]string{"FORCE_COLOR": "2"}, Color256},
+        {"FORCE_COLOR=3 on dumb", true, map[string]string{"TERM": "dumb", "FORCE_COLOR": "3"}, Color256},
+        {"FORCE_COLOR=0", true, map[string]string{"TERM": "xterm-256color", "FORCE_COLOR": "0"}, NoColor},
+        {"FORCE_COLOR=false", true, map[string]string{"TERM": "xterm", "FORCE_COLOR": "FALSE"}, NoColor},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            getenv := func(key string) string { return tt.env[key] }
+            if got := DetectEnv(tt.terminal, getenv); got != tt.want {
+                t.Errorf("DetectEnv(%t, %v) = %s, want %s", tt.terminal, tt.env, got, tt.want)
+            }
+        })
+    }
+}
+
+func TestWriterLevels(t *testing.T) {
+    input := "\033[1;38;5;196mSOLD OUT\033[0m \033[48;2;0;0;255mblue\033[0m \033[32mok\033[0m\n"
+    tests := []struct {
+        level Level
+        want  string
+    }{
+        {Color256, input},
+        {Color16, "\033[1;91mSOLD OUT\033[0m \033[44mblue\033[0m \033[32mok\033[0m\n"},
+        {NoColor, "SOLD OUT blue ok\n"},
+    }
+    for _, tt := range tests {
+        var out strings.Builder
+        n, err := NewWriterLevel(&out, tt.level).Write([]byte(input))
+        if err != nil || n != len(input) {
+            t.Errorf("%s: Write = %d, %v; want %d, nil", tt.level, n, err, len(input))
+        }
+        if got := out.String(); got != tt.want {
+            t.Errorf("%s: wrote %q, want %q", tt.level, got, tt.want)
+        }
+    }
+}
+
+func TestDegradeLeavesOtherSequences(t *testing.T) {
+    for _, s := range []string{
+        "\033[2J\033[H",   // Clear and home aren't colors
+        "\033[38mx\033[0m", // 38 with nothing after it
+        "half \033[38;5",   // Cut off mid-sequence
+    } {
+        if got := degrade(s); got != s {
+            t.Errorf("degrade(%q) = %q, want it unchanged", s, got)
+        }
+    }
+}
+
+func TestStyleCode(t *testing.T) {
+    s := Fg(RGB(5, 0, 0)).Bold()
+    if got, want := s.Code(Color256), "\033[1;38;5;196m"; got != want {
+        t.Errorf("Color256 = %q, want %q", got, want)
+    }
+    if got, want := s.Code(Color16), "\033[1;91m"; got != want {
+        t.Errorf("Color16 = %q, want %q", got, want)
+    }
+    if got := s.Code(NoColor); got != "" {
+        t.Errorf("NoColor = %q, want none", got)
+    }
+}
@@ -0,0 +1,221 @@
+package table
+
+import (
+    "encoding/csv"
+    "html"
+    "io"
+    "strings"
+
+    "fmt-examples/textlayout"
+)
+
+func (t *Table) aligns() []textlayout.Align {
+    aligns := make([]textlayout.Align, len(t.columns))
+    for i, col := range t.columns {
+        aligns[i] = col.alignment()
+    }
+    return aligns
+}
+
+func (t *Table) titles() []string {
+    titles := make([]string, len(t.columns))
+    for i, col := range t.columns {
+        titles[i] = col.Title
+    }
+    return titles
+}
+
+// renderBox draws the table with Unicode box-drawing characters
+func (t *Table) renderBox(w io.Writer, lines []line) error {
+    widths := t.widths(lines)
+    aligns := t.aligns()
+
+    border := func(left, mid, right, fill string) string {
+        parts := make([]string, len(widths))
+        for i, width := range widths {
+            parts[i] = strings.Repeat(fill, width+2)
+        }
+        return left + strings.Join(parts, mid) + right + "\n"
+    }
+    row := func(cells []string) string {
+        return "│ " + textlayout.Row(cells, widths, aligns, " │ ") + " │\n"
+    }
+
+    // A title wider than the columns widens them, spread evenly, so the
+    // box still closes
+    if extra := textlayout.Width(t.Title) + 2 - t.inner(widths); t.Title != "" && extra > 0 && len(widths) > 0 {
+        for i := range widths {
+            widths[i] += extra / len(widths)
+            if i < extra%len(widths) {
+                widths[i]++
+            }
+        }
+    }
+    total := t.inner(widths) + 2
+
+    var b strings.Builder
+    if t.Title != "" {
+        b.WriteString(border("┌", "─", "┐", "─"))
+        b.WriteString("│" + textlayout.Center(t.Title, total-2) + "│\n")
+        b.WriteString(border("├", "┬", "┤", "─"))
+    } else {
+        b.WriteString(border("┌", "┬", "┐", "─"))
+    }
+
+    headerAligns := make([]textlayout.Align, len(widths))
+    for i := range headerAligns {
+        headerAligns[i] = textlayout.AlignCenter
+    }
+    b.WriteString("│ " + textlayout.Row(t.titles(), widths, headerAligns, " │ ") + " │\n")
+    b.WriteString(border("├", "┼", "┤", "─"))
+
+    previous := dataLine
+    for _, l := range lines {
+        switch l.kind {
+        case subtotalLine:
+            b.WriteString(border("├", "┼", "┤", "┄"))
+        case totalLine:
+            b.WriteString(border("╞", "╪", "╡", "═"))
+        default:
+            if previous == subtotalLine {
+                b.WriteString(border("├", "┼", "┤", "─"))
+            }
+        }
+        b.WriteString(row(l.cells))
+        previous = l.kind
+    }
+    b.WriteString(border("└", "┴", "┘", "─"))
+
+    _, err := io.WriteString(w, b.String())
+    return err
+}
+
+// inner is how many columns the box has between its outer borders
+func (t *Table) inner(widths []int) int {
+    inner := len(widths)*3 - 1
+    for _, width := range widths {
+        inner += width
+    }
+    return inner
+}
+
+// renderMarkdown writes a GitHub-flavored Markdown table
+func (t *Table) renderMarkdown(w io.Writer, lines []line) error {
+    aligns := t.aligns()
+
+    rows := [][]string{t.titles()}
+    for _, l := range lines {
+        cells := make([]string, len(l.cells))
+        for i, cell := range l.cells {
+            cells[i] = strings.ReplaceAll(cell, "|", `\|`)
+            if l.kind != dataLine && cell != "" {
+                cells[i] = "**" + cells[i] + "**"
+            }
+        }
+        rows = append(rows, cells)
+    }
+
+    widths := textlayout.ColumnWidths(rows)
+    rules := make([]string, len(widths))
+    for i := range widths {
+        widths[i] = max(widths[i], 3)
+        dashes := strings.Repeat("-", widths[i])
+        switch aligns[i] {
+        case textlayout.AlignRight:
+            rules[i] = dashes[1:] + ":"
+        case textlayout.AlignCenter:
+            rules[i] = ":" + dashes[2:] + ":"
+        default:
+            rules[i] = dashes
+        }
+    }
+
+    var b strings.Builder
+    if t.Title != "" {
+        b.WriteString("### " + t.Title + "\n\n")
+    }
+    for i, row := range rows {
+        b.WriteString("| " + textlayout.Row(row, widths, aligns, " | ") + " |\n")
+        if i == 0 {
+            b.WriteString("| " + strings.Join(rules, " | ") + " |\n")
+        }
+    }
+
+    _, err := io.WriteString(w, b.String())
+    return err
+}
+
+// renderCSV writes plain values (no currency symbols) for spreadsheets
+func (t *Table) renderCSV(w io.Writer, lines []line) error {
+    writer := csv.NewWriter(w)
+    if err := writer.Write(t.titles()); err != nil {
+        return err
+    }
+    for _, l := range lines {
+        if err := writer.Write(l.raw); err != nil {
+            return err
+        }
+    }
+    writer.Flush()
+    return writer.Error()
+}
+
+// renderHTML writes a <table> with subtotal and total rows marked by class
+func (t *Table) renderHTML(w io.Writer, lines []line) error {
+    aligns := t.aligns()
+    style := func(i int) string {
+        switch aligns[i] {
+        case textlayout.AlignRight:
+            return ` style="text-align:right"`
+        case textlayout.AlignCenter:
+            return ` style="text-align:center"`
+        }
+        return ""
+    }
+
+    var b strings.Builder
+    b.WriteString("<table>\n")
+    if t.Title != "" {
+        b.WriteString("  <caption>" + html.EscapeString(t.Title) + "</caption>\n")
+    }
+
+    b.WriteString("  <thead>\n    <tr>")
+    for _, title := range t.titles() {
+        b.WriteString("<th>" + html.EscapeString(title) + "</th>")
+    }
+    b.WriteString("</tr>\n  </thead>\n  <tbody>\n")
+
+    var footer []line
+    for _, l := range lines {
+        if l.kind == totalLine {
+            footer = append(footer, l)
+            continue
+        }
+        class := ""
+        if l.kind == subtotalLine {
+            class = ` class="subtotal"`
+        }
+        b.WriteString("    <tr" + class + ">")
+        for i, cell := range l.cells {
+            b.WriteString("<td" + style(i) + ">" + html.EscapeString(cell) + "</td>")
+        }
+        b.WriteString("</tr>\n")
+    }
+    b.WriteString("  </tbody>\n")
+
+    if len(footer) > 0 {
+        b.WriteString("  <tfoot>\n")
+        for _, l := range footer {
+            b.WriteString("    <tr>")
+            for i, cell := range l.cells {
+                b.WriteString("<th" + style(i) + ">" + html.EscapeString(cell) + "</th>")
+            }
+            b.WriteString("</tr>\n")
+        }
+        b.WriteString("  </tfoot>\n")
+    }
+    b.WriteString("</table>\n")
+
+    _, err := io.WriteString(w, b.String())
+    return err
+}
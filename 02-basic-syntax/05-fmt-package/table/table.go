@@ -0,0 +1,420 @@
+// Package table builds reports with typed columns and renders them as
+// box-drawing text, Markdown, CSV or HTML.
+//
+// printBasicTable, printAlignedTable, printSummaryReport and
+// printASCIIArtTable each hardcode the SalesData columns. With this package
+// a report only declares its columns and rows; sorting, group subtotals,
+// the grand total and every output format come for free.
+package table
+
+import (
+    "fmt"
+    "io"
+    "sort"
+    "strings"
+    "time"
+
+    "fmt-examples/textlayout"
+)
+
+// Kind decides how a column's values are checked, formatted and totaled
+type Kind int
+
+const (
+    Text    Kind = iota // string
+    Number              // int
+    Money               // int64 cents
+    Percent             // float64, 0.125 means 12.5%
+    Date                // time.Time
+)
+
+// Column describes one column of the table
+type Column struct {
+    Title  string
+    Kind   Kind
+    Align  textlayout.Align // Text and dates default to left, numbers to right
+    Layout string           // Date layout, defaults to "Jan 02"
+
+    alignSet bool
+}
+
+func TextColumn(title string) Column    { return Column{Title: title, Kind: Text} }
+func NumberColumn(title string) Column  { return Column{Title: title, Kind: Number} }
+func MoneyColumn(title string) Column   { return Column{Title: title, Kind: Money} }
+func PercentColumn(title string) Column { return Column{Title: title, Kind: Percent} }
+
+func DateColumn(title, layout string) Column {
+    return Column{Title: title, Kind: Date, Layout: layout}
+}
+
+// Aligned overrides the default alignment
+func (c Column) Aligned(align textlayout.Align) Column {
+    c.Align = align
+    c.alignSet = true
+    return c
+}
+
+func (c Column) alignment() textlayout.Align {
+    if c.alignSet {
+        return c.Align
+    }
+    if c.Kind == Text || c.Kind == Date {
+        return textlayout.AlignLeft
+    }
+    return textlayout.AlignRight
+}
+
+func (c Column) summable() bool {
+    return c.Kind == Number || c.Kind == Money
+}
+
+// SortKey orders rows by one column
+type SortKey struct {
+    Column string
+    Desc   bool
+}
+
+func Asc(column string) SortKey  { return SortKey{Column: column} }
+func Desc(column string) SortKey { return SortKey{Column: column, Desc: true} }
+
+// Table is a report under construction
+type Table struct {
+    Title   string
+    columns []Column
+    rows    [][]any
+    sortBy  []SortKey
+    groupBy string
+    totals  bool
+}
+
+func New(title string, columns ...Column) *Table {
+    return &Table{Title: title, columns: columns}
+}
+
+// AddRow appends one row; values must match the column kinds
+func (t *Table) AddRow(values ...any) error {
+    if len(values) != len(t.columns) {
+        return fmt.Errorf("table %q: row has %d values, want %d", t.Title, len(values), len(t.columns))
+    }
+
+    for i, v := range values {
+        col := t.columns[i]
+        ok := false
+        switch col.Kind {
+        case Text:
+            _, ok = v.(string)
+        case Number:
+            _, ok = v.(int)
+        case Money:
+            _, ok = v.(int64)
+        case Percent:
+            _, ok = v.(float64)
+        case Date:
+            _, ok = v.(time.Time)
+        }
+        if !ok {
+            return fmt.Errorf("table %q: column %q got %T", t.Title, col.Title, v)
+        }
+    }
+
+    t.rows = append(t.rows, values)
+    return nil
+}
+
+// SortBy sets the row order; later keys break ties in earlier ones
+func (t *Table) SortBy(keys ...SortKey) *Table {
+    t.sortBy = keys
+    return t
+}
+
+// GroupBy puts rows with the same value together and adds a subtotal
+// row after each group
+func (t *Table) GroupBy(column string) *Table {
+    t.groupBy = column
+    return t
+}
+
+// WithTotals adds a grand-total footer
+func (t *Table) WithTotals() *Table {
+    t.totals = true
+    return t
+}
+
+func (t *Table) columnIndex(title string) (int, error) {
+    for i, col := range t.columns {
+        if col.Title == title {
+            return i, nil
+        }
+    }
+    return -1, fmt.Errorf("table %q has no column %q", t.Title, title)
+}
+
+// === Layout ===
+
+type lineKind int
+
+const (
+    dataLine lineKind = iota
+    subtotalLine
+    totalLine
+)
+
+// line is one formatted row, ready for any renderer
+type line struct {
+    kind  lineKind
+    cells []string // Display text
+    raw   []string // Plain values for CSV
+}
+
+func compare(a, b any) int {
+    switch x := a.(type) {
+    case string:
+        return strings.Compare(x, b.(string))
+    case int:
+        return x - b.(int)
+    case int64:
+        y := b.(int64)
+        switch {
+        case x < y:
+            return -1
+        case x > y:
+            return 1
+        }
+        return 0
+    case float64:
+        y := b.(float64)
+        switch {
+        case x < y:
+            return -1
+        case x > y:
+            return 1
+        }
+        return 0
+    case time.Time:
+        return x.Compare(b.(time.Time))
+    }
+    return 0
+}
+
+func formatMoney(cents int64) string {
+    sign := ""
+    if cents < 0 {
+        sign = "-"
+        cents = -cents
+    }
+    dollars := fmt.Sprintf("%d", cents/100)
+    for i := len(dollars) - 3; i > 0; i -= 3 {
+        dollars = dollars[:i] + "," + dollars[i:]
+    }
+    return fmt.Sprintf("%s$%s.%02d", sign, dollars, cents%100)
+}
+
+func (t *Table) format(col Column, v any) (display, raw string) {
+    switch col.Kind {
+    case Number:
+        n := v.(int)
+        return fmt.Sprintf("%d", n), fmt.Sprintf("%d", n)
+    case Money:
+        cents := v.(int64)
+        return formatMoney(cents), rawMoney(cents)
+    case Percent:
+        p := v.(float64) * 100
+        return fmt.Sprintf("%.1f%%", p), fmt.Sprintf("%.2f", p)
+    case Date:
+        layout := col.Layout
+        if layout == "" {
+            layout = "Jan 02"
+        }
+        d := v.(time.Time)
+        return d.Format(layout), d.Format("2006-01-02")
+    }
+    s := v.(string)
+    return s, s
+}
+
+// rawMoney is formatMoney without "$" and commas. The sign is written
+// separately: -50 cents is "-0.50", and cents/100 alone would give "0.50".
+func rawMoney(cents int64) string {
+    sign := ""
+    if cents < 0 {
+        sign = "-"
+        cents = -cents
+    }
+    return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// sumLine builds a subtotal or total row for the summable columns
+func (t *Table) sumLine(kind lineKind, label string, rows [][]any) line {
+    l := line{kind: kind, cells: make([]string, len(t.columns)), raw: make([]string, len(t.columns))}
+
+    for i, col := range t.columns {
+        if !col.summable() {
+            continue
+        }
+        var sumInt int
+        var sumCents int64
+        for _, row := range rows {
+            switch v := row[i].(type) {
+            case int:
+                sumInt += v
+            case int64:
+                sumCents += v
+            }
+        }
+        if col.Kind == Number {
+            l.cells[i], l.raw[i] = t.format(col, sumInt)
+        } else {
+            l.cells[i], l.raw[i] = t.format(col, sumCents)
+        }
+    }
+
+    // The label goes in the first column that isn't holding a sum
+    for i, col := range t.columns {
+        if !col.summable() {
+            l.cells[i], l.raw[i] = label, label
+            break
+        }
+    }
+    return l
+}
+
+func (t *Table) lines() ([]line, error) {
+    rows := make([][]any, len(t.rows))
+    copy(rows, t.rows)
+
+    type key struct {
+        index int
+        desc  bool
+    }
+    var keys []key
+
+    group := -1
+    if t.groupBy != "" {
+        idx, err := t.columnIndex(t.groupBy)
+        if err != nil {
+            return nil, err
+        }
+        group = idx
+        keys = append(keys, key{index: idx})
+    }
+    for _, k := range t.sortBy {
+        idx, err := t.columnIndex(k.Column)
+        if err != nil {
+            return nil, err
+        }
+        keys = append(keys, key{index: idx, desc: k.Desc})
+    }
+
+    sort.SliceStable(rows, func(a, b int) bool {
+        for _, k := range keys {
+            c := compare(rows[a][k.index], rows[b][k.index])
+            if c == 0 {
+                continue
+            }
+            if k.desc {
+                return c > 0
+            }
+            return c < 0
+        }
+        return false
+    })
+
+    var out []line
+    var groupRows [][]any
+
+    flush := func() {
+        if group >= 0 && len(groupRows) > 0 {
+            label, _ := t.format(t.columns[group], groupRows[0][group])
+            out = append(out, t.sumLine(subtotalLine, "Subtotal "+label, groupRows))
+        }
+        groupRows = nil
+    }
+
+    for i, row := range rows {
+        if group >= 0 && i > 0 && compare(row[group], rows[i-1][group]) != 0 {
+            flush()
+        }
+
+        l := line{kind: dataLine, cells: make([]string, len(row)), raw: make([]string, len(row))}
+        for c, v := range row {
+            l.cells[c], l.raw[c] = t.format(t.columns[c], v)
+        }
+        out = append(out, l)
+        groupRows = append(groupRows, row)
+    }
+    flush()
+
+    if t.totals {
+        out = append(out, t.sumLine(totalLine, "Total", rows))
+    }
+    return out, nil
+}
+
+func (t *Table) widths(lines []line) []int {
+    widths := make([]int, len(t.columns))
+    for i, col := range t.columns {
+        widths[i] = textlayout.Width(col.Title)
+    }
+    for _, l := range lines {
+        for i, cell := range l.cells {
+            if w := textlayout.Width(cell); w > widths[i] {
+                widths[i] = w
+            }
+        }
+    }
+    return widths
+}
+
+// === Rendering ===
+
+// Format selects an output format for Render
+type Format int
+
+const (
+    BoxText Format = iota
+    Markdown
+    CSV
+    HTML
+)
+
+// labeled returns t with an untitled text column in front, to hold the
+// subtotal and total labels of a table whose columns all hold sums
+func (t *Table) labeled() *Table {
+    if !t.totals && t.groupBy == "" {
+        return t
+    }
+    for _, col := range t.columns {
+        if !col.summable() {
+            return t
+        }
+    }
+
+    labeled := *t
+    labeled.columns = append([]Column{TextColumn("")}, t.columns...)
+    labeled.rows = make([][]any, len(t.rows))
+    for i, row := range t.rows {
+        labeled.rows[i] = append([]any{""}, row...)
+    }
+    return &labeled
+}
+
+// Render writes the table in the requested format
+func (t *Table) Render(w io.Writer, format Format) error {
+    t = t.labeled()
+    lines, err := t.lines()
+    if err != nil {
+        return err
+    }
+
+    switch format {
+    case BoxText:
+        return t.renderBox(w, lines)
+    case Markdown:
+        return t.renderMarkdown(w, lines)
+    case CSV:
+        return t.renderCSV(w, lines)
+    case HTML:
+        return t.renderHTML(w, lines)
+    }
+    return fmt.Errorf("unknown table format %d", format)
+}
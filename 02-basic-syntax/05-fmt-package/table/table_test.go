@@ -0,0 +1,227 @@
+package table
+
+import (
+    "bytes"
+    "flag"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+
+    "fmt-examples/textlayout"
+)
+
+// Run `go test ./table -update` after an intended change to a renderer,
+// then review the diff of testdata/ before committing it
+var update = flag.Bool("update", false, "rewrite the golden files in testdata/")
+
+// sales has two categories and a tie on Qty that only Product breaks
+func sales(t *testing.T) *Table {
+    t.Helper()
+    day := func(d int) time.Time { return time.Date(2024, time.March, d, 0, 0, 0, 0, time.UTC) }
+    report := New("Sales",
+        DateColumn("Date", ""),
+        TextColumn("Product"),
+        TextColumn("Category"),
+        NumberColumn("Qty"),
+        MoneyColumn("Revenue"),
+        PercentColumn("Margin"),
+    )
+    for _, row := range [][]any{
+        {day(11), "Latte", "Coffee", 40, int64(18000), 0.62},
+        {day(11), "Croissant", "Bakery", 25, int64(8750), 0.41},
+        {day(12), "Mocha", "Coffee", 40, int64(20000), 0.58},
+        {day(12), "Muffin", "Bakery", 12, int64(3600), 0.45},
+        {day(13), "Espresso", "Coffee", 55, int64(16500), 0.7},
+    } {
+        if err := report.AddRow(row...); err != nil {
+            t.Fatal(err)
+        }
+    }
+    return report
+}
+
+// csvLines renders CSV, which keeps every line's cells easy to compare
+func csvLines(t *testing.T, report *Table) []string {
+    t.Helper()
+    var out strings.Builder
+    if err := report.Render(&out, CSV); err != nil {
+        t.Fatal(err)
+    }
+    return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+}
+
+func TestSortBy(t *testing.T) {
+    got := csvLines(t, sales(t).SortBy(Desc("Qty"), Asc("Product")))
+    want := []string{
+        "Date,Product,Category,Qty,Revenue,Margin",
+        "2024-03-13,Espresso,Coffee,55,165.00,70.00",
+        "2024-03-11,Latte,Coffee,40,180.00,62.00",
+        "2024-03-12,Mocha,Coffee,40,200.00,58.00",
+        "2024-03-11,Croissant,Bakery,25,87.50,41.00",
+        "2024-03-12,Muffin,Bakery,12,36.00,45.00",
+    }
+    if strings.Join(got, "\n") != strings.Join(want, "\n") {
+        t.Errorf("SortBy(Desc Qty, Asc Product) =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+    }
+
+    if err := sales(t).SortBy(Asc("Price")).Render(&strings.Builder{}, CSV); err == nil {
+        t.Error("SortBy an unknown column rendered")
+    }
+}
+
+func TestGroupBySubtotals(t *testing.T) {
+    got := csvLines(t, sales(t).GroupBy("Category").SortBy(Desc("Revenue")))
+    want := []string{
+        "Date,Product,Category,Qty,Revenue,Margin",
+        "2024-03-11,Croissant,Bakery,25,87.50,41.00",
+        "2024-03-12,Muffin,Bakery,12,36.00,45.00",
+        "Subtotal Bakery,,,37,123.50,",
+        "2024-03-12,Mocha,Coffee,40,200.00,58.00",
+        "2024-03-11,Latte,Coffee,40,180.00,62.00",
+        "2024-03-13,Espresso,Coffee,55,165.00,70.00",
+        "Subtotal Coffee,,,135,545.00,",
+    }
+    if strings.Join(got, "\n") != strings.Join(want, "\n") {
+        t.Errorf("GroupBy(Category) =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+    }
+}
+
+func TestWithTotals(t *testing.T) {
+    got := csvLines(t, sales(t).WithTotals())
+    if last, want := got[len(got)-1], "Total,,,172,668.50,"; last != want {
+        t.Errorf("total line = %q, want %q", last, want)
+    }
+    if got := csvLines(t, sales(t)); strings.HasPrefix(got[len(got)-1], "Total") {
+        t.Error("a table without WithTotals has a total line")
+    }
+}
+
+// With no column free for the label, a blank column in front holds it
+func TestTotalsWhenEveryColumnSums(t *testing.T) {
+    report := New("", NumberColumn("Cups"), MoneyColumn("Revenue"))
+    for _, row := range [][]any{{40, int64(18000)}, {25, int64(8750)}} {
+        if err := report.AddRow(row...); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    got := csvLines(t, report.WithTotals())
+    want := []string{",Cups,Revenue", ",40,180.00", ",25,87.50", "Total,65,267.50"}
+    if strings.Join(got, "\n") != strings.Join(want, "\n") {
+        t.Errorf("CSV =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+    }
+    if len(report.columns) != 2 {
+        t.Errorf("Render added a column to the table itself: %d columns", len(report.columns))
+    }
+}
+
+func TestBoxTitleWiderThanColumns(t *testing.T) {
+    report := New("GoCoffee Downtown — Weekly Sales", TextColumn("Item"), NumberColumn("Qty"))
+    if err := report.AddRow("Latte", 40); err != nil {
+        t.Fatal(err)
+    }
+
+    var out strings.Builder
+    if err := report.Render(&out, BoxText); err != nil {
+        t.Fatal(err)
+    }
+    lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+    for _, l := range lines {
+        if textlayout.Width(l) != textlayout.Width(lines[0]) {
+            t.Fatalf("box lines differ in width:\n%s", out.String())
+        }
+    }
+    if !strings.Contains(lines[1], report.Title) {
+        t.Errorf("title row %q doesn't hold the whole title", lines[1])
+    }
+}
+
+func TestRenderersGolden(t *testing.T) {
+    tests := []struct {
+        golden string
+        format Format
+    }{
+        {"box.golden", BoxText},
+        {"markdown.golden", Markdown},
+        {"html.golden", HTML},
+    }
+
+    for _, tt := range tests {
+        t.Run(tt.golden, func(t *testing.T) {
+            report := sales(t).GroupBy("Category").SortBy(Desc("Revenue")).WithTotals()
+            var got bytes.Buffer
+            if err := report.Render(&got, tt.format); err != nil {
+                t.Fatalf("Render: %v", err)
+            }
+
+            path := filepath.Join("testdata", tt.golden)
+            if *update {
+                if err := os.WriteFile(path, got.Bytes(), 0o644); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            want, err := os.ReadFile(path)
+            if err != nil {
+                t.Fatalf("%v (run with -update to create it)", err)
+            }
+            if !bytes.Equal(got.Bytes(), want) {
+                t.Errorf("output differs from %s\ngot:\n%s\nwant:\n%s", path, got.Bytes(), want)
+            }
+        })
+    }
+}
+
+func TestMoneyFormats(t *testing.T) {
+    tests := []struct {
+        cents   int64
+        display string
+        raw     string
+    }{
+        {0, "$0.00", "0.00"},
+        {5, "$0.05", "0.05"},
+        {123456, "$1,234.56", "1234.56"},
+        {-1, "-$0.01", "-0.01"},
+        {-99, "-$0.99", "-0.99"},
+        {-100, "-$1.00", "-1.00"},
+        {-123456, "-$1,234.56", "-1234.56"},
+    }
+    for _, tt := range tests {
+        if got := formatMoney(tt.cents); got != tt.display {
+            t.Errorf("formatMoney(%d) = %q, want %q", tt.cents, got, tt.display)
+        }
+        if got := rawMoney(tt.cents); got != tt.raw {
+            t.Errorf("rawMoney(%d) = %q, want %q", tt.cents, got, tt.raw)
+        }
+    }
+}
+
+func TestCSVKeepsSmallRefundsNegative(t *testing.T) {
+    refunds := New("Refunds", TextColumn("Order"), MoneyColumn("Amount"))
+    for _, row := range [][]any{{"ORD-1", int64(-50)}, {"ORD-2", int64(-1250)}} {
+        if err := refunds.AddRow(row...); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    var out strings.Builder
+    if err := refunds.Render(&out, CSV); err != nil {
+        t.Fatal(err)
+    }
+    for _, want := range []string{"ORD-1,-0.50", "ORD-2,-12.50"} {
+        if !strings.Contains(out.String(), want) {
+            t.Errorf("CSV is missing %q:\n%s", want, out.String())
+        }
+    }
+}
+
+func TestAddRowChecksKinds(t *testing.T) {
+    report := New("Sales", TextColumn("Product"), MoneyColumn("Revenue"))
+    if err := report.AddRow("Mocha", 90.00); err == nil {
+        t.Error("AddRow accepted a float64 for a Money column")
+    }
+    if err := report.AddRow("Mocha"); err == nil {
+        t.Error("AddRow accepted a short row")
+    }
+}
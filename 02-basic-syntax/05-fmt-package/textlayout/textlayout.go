@@ -0,0 +1,314 @@
+// Package textlayout measures and pads text by how wide it looks in a
+// terminal, not by how many bytes it takes.
+//
+// len("Café Crème") is 12 but it only fills 10 columns, "拿铁" fills 4 columns
+// in 6 bytes, and "\033[32mOK\033[0m" fills 2 columns in 11 bytes. Every
+// table, receipt and menu printer in this chapter should use these helpers
+// instead of len(s) and s[:n].
+package textlayout
+
+import (
+    "strings"
+    "unicode"
+    "unicode/utf8"
+)
+
+// Ellipsis is appended to truncated text
+const Ellipsis = "…"
+
+// Align says where text sits inside a padded column
+type Align int
+
+const (
+    AlignLeft Align = iota
+    AlignRight
+    AlignCenter
+)
+
+// wideRanges are the East Asian Wide/Fullwidth blocks plus the emoji
+// blocks that terminals draw two columns wide
+var wideRanges = []struct{ lo, hi rune }{
+    {0x1100, 0x115F},   // Hangul Jamo
+    {0x231A, 0x231B},   // Watch, hourglass
+    {0x23E9, 0x23EC},   // Media buttons
+    {0x23F0, 0x23F3},   // Alarm clock, timers
+    {0x25FD, 0x25FE},   // Small squares
+    {0x2614, 0x2615},   // Umbrella, hot beverage ☕
+    {0x2648, 0x2653},   // Zodiac
+    {0x267F, 0x267F},   // Wheelchair
+    {0x2693, 0x2693},   // Anchor
+    {0x26A1, 0x26A1},   // High voltage
+    {0x26AA, 0x26AB},   // Circles
+    {0x26BD, 0x26BE},   // Soccer, baseball
+    {0x26C4, 0x26C5},   // Snowman, sun
+    {0x26CE, 0x26CE},   // Ophiuchus
+    {0x26D4, 0x26D4},   // No entry
+    {0x26EA, 0x26EA},   // Church
+    {0x26F2, 0x26F5},   // Fountain .. sailboat
+    {0x26FA, 0x26FA},   // Tent
+    {0x26FD, 0x26FD},   // Fuel pump
+    {0x2705, 0x2705},   // Check mark ✅
+    {0x270A, 0x270B},   // Fists
+    {0x2728, 0x2728},   // Sparkles
+    {0x274C, 0x274C},   // Cross mark ❌
+    {0x274E, 0x274E},   // Cross mark button
+    {0x2753, 0x2755},   // Question marks
+    {0x2757, 0x2757},   // Exclamation
+    {0x2795, 0x2797},   // Plus, minus, divide
+    {0x27B0, 0x27B0},   // Curly loop
+    {0x27BF, 0x27BF},   // Double curly loop
+    {0x2B1B, 0x2B1C},   // Large squares
+    {0x2B50, 0x2B50},   // Star ⭐
+    {0x2B55, 0x2B55},   // Circle
+    {0x2E80, 0x303E},   // CJK radicals, punctuation
+    {0x3041, 0x33FF},   // Hiragana, Katakana, CJK compatibility
+    {0x3400, 0x4DBF},   // CJK extension A
+    {0x4E00, 0x9FFF},   // CJK unified ideographs
+    {0xA000, 0xA4CF},   // Yi
+    {0xAC00, 0xD7A3},   // Hangul syllables
+    {0xF900, 0xFAFF},   // CJK compatibility ideographs
+    {0xFE30, 0xFE4F},   // CJK compatibility forms
+    {0xFF00, 0xFF60},   // Fullwidth forms
+    {0xFFE0, 0xFFE6},   // Fullwidth signs
+    {0x1F004, 0x1F004}, // Mahjong tile
+    {0x1F0CF, 0x1F0CF}, // Joker
+    {0x1F18E, 0x1F18E}, // AB button
+    {0x1F191, 0x1F19A}, // Squared words
+    {0x1F200, 0x1F2FF}, // Enclosed ideographic supplement
+    {0x1F300, 0x1F64F}, // Misc symbols and pictographs, emoticons
+    {0x1F680, 0x1F6FF}, // Transport and map
+    {0x1F7E0, 0x1F7EB}, // Colored circles and squares
+    {0x1F90C, 0x1F9FF}, // Supplemental symbols and pictographs
+    {0x1FA70, 0x1FAFF}, // Symbols and pictographs extended-A
+    {0x20000, 0x3FFFD}, // CJK extensions B and beyond
+}
+
+// RuneWidth returns how many columns a single rune occupies: 0 for
+// combining marks and control characters, 2 for wide runes, 1 otherwise
+func RuneWidth(r rune) int {
+    switch {
+    case r == 0:
+        return 0
+    case r < 0x20 || (r >= 0x7F && r < 0xA0):
+        return 0
+    case r == 0x200D: // Zero width joiner
+        return 0
+    case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+        return 0
+    case r >= 0xFE00 && r <= 0xFE0F: // Variation selectors
+        return 0
+    }
+
+    if r < 0x1100 {
+        return 1
+    }
+    for _, wr := range wideRanges {
+        if r < wr.lo {
+            break
+        }
+        if r <= wr.hi {
+            return 2
+        }
+    }
+    return 1
+}
+
+// cluster is one visible unit of a string: an escape sequence, or a rune
+// together with the combining marks, joiners and variation selectors after it
+type cluster struct {
+    text   string
+    width  int
+    escape bool
+}
+
+// clusters splits s into things that must never be cut apart
+func clusters(s string) []cluster {
+    var out []cluster
+
+    for i := 0; i < len(s); {
+        // ANSI escape: ESC [ parameters final-byte
+        if s[i] == 0x1B && i+1 < len(s) && s[i+1] == '[' {
+            j := i + 2
+            for j < len(s) && (s[j] < 0x40 || s[j] > 0x7E) {
+                j++
+            }
+            if j < len(s) {
+                j++
+            }
+            out = append(out, cluster{text: s[i:j], escape: true})
+            i = j
+            continue
+        }
+
+        r, size := utf8.DecodeRuneInString(s[i:])
+        start := i
+        width := RuneWidth(r)
+        i += size
+
+        // Glue zero-width followers onto the base rune
+        for i < len(s) {
+            next, nextSize := utf8.DecodeRuneInString(s[i:])
+            if next == 0xFE0F && width == 1 {
+                width = 2 // Emoji presentation: ⚠ becomes ⚠️
+            } else if next == 0x200D {
+                // Joined emoji sequence: the next pictograph shares this cell
+                i += nextSize
+                if i < len(s) {
+                    _, joined := utf8.DecodeRuneInString(s[i:])
+                    i += joined
+                }
+                continue
+            } else if RuneWidth(next) != 0 || next < 0x20 || next == 0x1B {
+                break
+            }
+            i += nextSize
+        }
+
+        out = append(out, cluster{text: s[start:i], width: width})
+    }
+
+    return out
+}
+
+// Width returns the number of terminal columns s occupies
+func Width(s string) int {
+    width := 0
+    for _, c := range clusters(s) {
+        width += c.width
+    }
+    return width
+}
+
+// StripANSI removes color and style escape codes
+func StripANSI(s string) string {
+    var b strings.Builder
+    for _, c := range clusters(s) {
+        if !c.escape {
+            b.WriteString(c.text)
+        }
+    }
+    return b.String()
+}
+
+// Truncate shortens s to at most width columns, ending with an ellipsis.
+// It never splits a multi-byte rune, a wide character or an accent from
+// its letter, and keeps escape codes (resetting style if it cut any).
+func Truncate(s string, width int) string {
+    if Width(s) <= width {
+        return s
+    }
+    if width <= 0 {
+        return ""
+    }
+
+    var b strings.Builder
+    used := 0
+    styled := false
+    limit := width - Width(Ellipsis)
+
+    for _, c := range clusters(s) {
+        if c.escape {
+            b.WriteString(c.text)
+            styled = true
+            continue
+        }
+        if used+c.width > limit {
+            break
+        }
+        b.WriteString(c.text)
+        used += c.width
+    }
+
+    b.WriteString(Ellipsis)
+    if styled {
+        b.WriteString("\033[0m")
+    }
+    return b.String()
+}
+
+// Pad fills s with spaces up to width columns. Text that is already wider
+// is returned unchanged; combine with Truncate for fixed-width columns.
+func Pad(s string, width int, align Align) string {
+    gap := width - Width(s)
+    if gap <= 0 {
+        return s
+    }
+
+    switch align {
+    case AlignRight:
+        return strings.Repeat(" ", gap) + s
+    case AlignCenter:
+        left := gap / 2
+        return strings.Repeat(" ", left) + s + strings.Repeat(" ", gap-left)
+    default:
+        return s + strings.Repeat(" ", gap)
+    }
+}
+
+// PadRight left-aligns s in a column of width
+func PadRight(s string, width int) string {
+    return Pad(s, width, AlignLeft)
+}
+
+// PadLeft right-aligns s in a column of width
+func PadLeft(s string, width int) string {
+    return Pad(s, width, AlignRight)
+}
+
+// Center centers s in a column of width
+func Center(s string, width int) string {
+    return Pad(s, width, AlignCenter)
+}
+
+// Fit truncates and pads so s fills exactly width columns
+func Fit(s string, width int, align Align) string {
+    return Pad(Truncate(s, width), width, align)
+}
+
+// Justify puts left and right at opposite ends of a width-column line,
+// like "Latte ........ $4.50" without the dots
+func Justify(left, right string, width int) string {
+    gap := width - Width(left) - Width(right)
+    if gap < 1 {
+        left = Truncate(left, width-Width(right)-1)
+        gap = width - Width(left) - Width(right)
+    }
+    if gap < 1 {
+        gap = 1
+    }
+    return left + strings.Repeat(" ", gap) + right
+}
+
+// ColumnWidths returns the widest cell in each column
+func ColumnWidths(rows [][]string) []int {
+    var widths []int
+    for _, row := range rows {
+        for i, cell := range row {
+            if i >= len(widths) {
+                widths = append(widths, 0)
+            }
+            if w := Width(cell); w > widths[i] {
+                widths[i] = w
+            }
+        }
+    }
+    return widths
+}
+
+// Row lays out cells in fixed-width columns joined by sep. Missing aligns
+// default to AlignLeft; cells wider than their column are truncated.
+func Row(cells []string, widths []int, aligns []Align, sep string) string {
+    parts := make([]string, len(cells))
+    for i, cell := range cells {
+        align := AlignLeft
+        if i < len(aligns) {
+            align = aligns[i]
+        }
+        width := Width(cell)
+        if i < len(widths) {
+            width = widths[i]
+        }
+        parts[i] = Fit(cell, width, align)
+    }
+    return strings.Join(parts, sep)
+}
@@ -0,0 +1,132 @@
+package textlayout
+
+import "testing"
+
+func TestRuneWidth(t *testing.T) {
+    tests := []struct {
+        r    rune
+        want int
+    }{
+        {'a', 1},
+        {'é', 1},
+        {'\u0301', 0}, // Combining acute accent
+        {'\u200d', 0}, // Zero width joiner
+        {'\ufe0f', 0}, // Variation selector
+        {'\t', 0},
+        {'拿', 2},
+        {'한', 2},
+        {'Ａ', 2}, // Fullwidth A
+        {'☕', 2},
+        {'✅', 2},
+        {'😀', 2},
+        {'⚠', 1}, // Text presentation unless followed by FE0F
+        {'│', 1},
+    }
+    for _, tt := range tests {
+        if got := RuneWidth(tt.r); got != tt.want {
+            t.Errorf("RuneWidth(%U %q) = %d, want %d", tt.r, tt.r, got, tt.want)
+        }
+    }
+}
+
+func TestWidth(t *testing.T) {
+    tests := []struct {
+        s    string
+        want int
+    }{
+        {"", 0},
+        {"Latte", 5},
+        {"Café Crème", 10},
+        {"Cafe\u0301", 4}, // e + combining accent
+        {"拿铁", 4},
+        {"☕ Mocha", 8},
+        {"⚠️", 2},
+        {"👩‍🍳", 2}, // Woman cook: one cell pair, three code points
+        {"\033[32mOK\033[0m", 2},
+    }
+    for _, tt := range tests {
+        if got := Width(tt.s); got != tt.want {
+            t.Errorf("Width(%q) = %d, want %d", tt.s, got, tt.want)
+        }
+    }
+}
+
+func TestTruncate(t *testing.T) {
+    tests := []struct {
+        s     string
+        width int
+        want  string
+    }{
+        {"Sarah", 5, "Sarah"},
+        {"Sarah M.", 5, "Sara…"},
+        {"Latte", 0, ""},
+        {"Latte", 1, "…"},
+        {"Café Crème", 5, "Café…"},
+        {"Cafe\u0301 Crème", 5, "Cafe\u0301…"}, // The accent stays with its e
+        {"拿铁咖啡", 4, "拿…"},                      // 铁 would need 2 more columns
+        {"拿铁咖啡", 5, "拿铁…"},
+        {"☕☕☕", 4, "☕…"},
+        {"👩‍🍳 Chef", 3, "👩‍🍳…"},
+        {"\033[1mEspresso\033[0m", 4, "\033[1mEsp…\033[0m"},
+    }
+    for _, tt := range tests {
+        got := Truncate(tt.s, tt.width)
+        if got != tt.want {
+            t.Errorf("Truncate(%q, %d) = %q, want %q", tt.s, tt.width, got, tt.want)
+        }
+        if w := Width(got); w > tt.width {
+            t.Errorf("Truncate(%q, %d) is %d columns wide", tt.s, tt.width, w)
+        }
+    }
+}
+
+func TestPad(t *testing.T) {
+    tests := []struct {
+        s     string
+        width int
+        align Align
+        want  string
+    }{
+        {"Latte", 8, AlignLeft, "Latte   "},
+        {"Latte", 8, AlignRight, "   Latte"},
+        {"Latte", 8, AlignCenter, " Latte  "},
+        {"Latte", 3, AlignLeft, "Latte"}, // Too wide: unchanged
+        {"Crème", 7, AlignLeft, "Crème  "},
+        {"Café", 6, AlignRight, "  Café"},
+        {"拿铁", 6, AlignLeft, "拿铁  "},
+        {"☕", 4, AlignCenter, " ☕ "},
+        {"\033[32mOK\033[0m", 4, AlignLeft, "\033[32mOK\033[0m  "},
+    }
+    for _, tt := range tests {
+        if got := Pad(tt.s, tt.width, tt.align); got != tt.want {
+            t.Errorf("Pad(%q, %d, %d) = %q, want %q", tt.s, tt.width, tt.align, got, tt.want)
+        }
+    }
+}
+
+func TestFitIsExactWidth(t *testing.T) {
+    for _, s := range []string{"", "Sarah", "Sarah M.", "拿铁咖啡", "☕ Mocha", "Café Crème", "👩‍🍳👩‍🍳👩‍🍳"} {
+        for width := 1; width <= 9; width++ {
+            if got := Width(Fit(s, width, AlignLeft)); got != width {
+                t.Errorf("Fit(%q, %d) is %d columns wide", s, width, got)
+            }
+        }
+    }
+}
+
+func TestColumnWidthsAndRow(t *testing.T) {
+    rows := [][]string{
+        {"Item", "Price"},
+        {"拿铁", "$4.50"},
+        {"Café Crème", "$3.80"},
+    }
+    widths := ColumnWidths(rows)
+    if len(widths) != 2 || widths[0] != 10 || widths[1] != 5 {
+        t.Fatalf("ColumnWidths = %v, want [10 5]", widths)
+    }
+
+    got := Row(rows[1], widths, []Align{AlignLeft, AlignRight}, " | ")
+    if want := "拿铁       | $4.50"; got != want {
+        t.Errorf("Row = %q, want %q", got, want)
+    }
+}
@@ -2,8 +2,12 @@ package main
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
+
+	"switch-examples/catalog"
+	"switch-examples/payment"
 )
 
 func main() {
@@ -33,20 +37,19 @@ func processPayment(method string, amount float64) {
 	fmt.Printf("Method: %s\n", method)
 	fmt.Println(strings.Repeat("-", 30))
 	
-	var success bool
-	var message string
+	// The payment package decides; this walkthrough shows the steps
+	cents := catalog.Cents(math.Round(amount * 100))
+	result := payment.Process(payment.Method(method), cents)
 	
-	switch method {
-	case "cash":
+	switch payment.Method(method) {
+	case payment.Cash:
 		// Cash payment processing
 		fmt.Println("💵 Cash payment")
 		fmt.Println("1. Count bills")
 		fmt.Println("2. Open register")
 		fmt.Println("3. Make change")
-		success = true
-		message = "Cash accepted"
 		
-	case "credit":
+	case payment.Credit:
 		// Credit card processing
 		fmt.Println("💳 Credit card payment")
 		fmt.Println("1. Swipe/Insert/Tap card")
@@ -56,75 +59,48 @@ func processPayment(method string, amount float64) {
 		// Simulate processing delay
 		time.Sleep(1 * time.Second)
 		
-		// Simulate 95% success rate
-		if amount < 500 {
-			success = true
-			message = "Credit card approved"
-		} else {
-			success = false
-			message = "Credit limit exceeded"
-		}
-		
-	case "debit":
+	case payment.Debit:
 		// Debit card processing
 		fmt.Println("💳 Debit card payment")
 		fmt.Println("1. Insert card")
 		fmt.Println("2. Enter PIN")
 		fmt.Println("3. Check account balance")
 		
-		// Always succeeds for demo
-		success = true
-		message = "Debit card approved"
-		
-	case "mobile":
+	case payment.Mobile:
 		// Mobile payment (Apple Pay, Google Pay)
 		fmt.Println("📱 Mobile payment")
 		fmt.Println("1. Tap phone on reader")
 		fmt.Println("2. Authenticate with biometrics")
 		fmt.Println("3. Process payment")
 		
-		success = true
-		message = "Mobile payment successful"
-		
-	case "gift_card":
+	case payment.GiftCard:
 		// Gift card processing
 		fmt.Println("🎁 Gift card payment")
 		fmt.Println("1. Scan gift card")
 		fmt.Println("2. Check balance")
 		
-		// Simulate balance check
-		balance := 50.00
-		if amount <= balance {
-			fmt.Printf("3. Deduct $%.2f from card\n", amount)
-			fmt.Printf("4. Remaining balance: $%.2f\n", balance-amount)
-			success = true
-			message = "Gift card accepted"
-		} else {
-			success = false
-			message = fmt.Sprintf("Insufficient balance. Card has $%.2f", balance)
+		if result.Approved {
+			fmt.Printf("3. Deduct %s from card\n", cents)
+			fmt.Printf("4. Remaining balance: %s\n", payment.GiftCardBalance-cents)
 		}
 		
 	default:
 		// Unsupported payment method
 		fmt.Println("❌ Unsupported payment method")
-		success = false
-		message = "Payment method not accepted"
 		
 		fmt.Println("\nAccepted payment methods:")
-		fmt.Println("• Cash")
-		fmt.Println("• Credit card")
-		fmt.Println("• Debit card")
-		fmt.Println("• Mobile payment")
-		fmt.Println("• Gift card")
+		for _, m := range payment.Methods {
+			fmt.Printf("• %s\n", m)
+		}
 	}
 	
 	// Print result
 	fmt.Println()
-	if success {
-		fmt.Printf("✅ Success: %s\n", message)
+	if result.Approved {
+		fmt.Printf("✅ Success: %s\n", result.Message)
 		printReceipt(method, amount)
 	} else {
-		fmt.Printf("❌ Failed: %s\n", message)
+		fmt.Printf("❌ Failed: %s\n", result.Message)
 	}
 }
 
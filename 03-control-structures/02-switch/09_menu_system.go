@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"switch-examples/catalog"
 )
 
 // Menu state constants
@@ -15,6 +17,31 @@ const (
 	StateExit
 )
 
+// The menu comes from the shared catalog (see 10_menu_catalog.go)
+const store = "seattle"
+
+var menu *catalog.Catalog
+
+type menuEntry struct {
+	name  string
+	price float64
+}
+
+// menuEntries lists what can be ordered right now in the given categories
+func menuEntries(categories ...string) []menuEntry {
+	now := time.Now()
+	var entries []menuEntry
+
+	for _, section := range menu.Menu(store, now, categories...) {
+		for _, item := range section.Items {
+			if menu.Status(item, store, now) == catalog.OnSale {
+				entries = append(entries, menuEntry{item.Name, float64(item.BasePrice()) / 100})
+			}
+		}
+	}
+	return entries
+}
+
 // Shopping cart
 type Cart struct {
 	items  []string
@@ -25,6 +52,13 @@ type Cart struct {
 func main() {
 	fmt.Println("=== GoCoffee Interactive Menu System ===\n")
 	
+	// Load the menu
+	var err error
+	if menu, err = catalog.Load("data/menu.json"); err != nil {
+		fmt.Printf("❌ Cannot load menu: %v\n", err)
+		return
+	}
+	
 	// Initialize cart
 	cart := &Cart{
 		items:  []string{},
@@ -96,17 +130,7 @@ func showDrinksMenu(cart *Cart) int {
 	fmt.Println("=============")
 	fmt.Println()
 	
-	drinks := []struct {
-		name  string
-		price float64
-	}{
-		{"Espresso", 2.50},
-		{"Americano", 3.00},
-		{"Latte", 4.50},
-		{"Cappuccino", 4.00},
-		{"Mocha", 5.00},
-		{"Macchiato", 4.25},
-	}
+	drinks := menuEntries("coffee", "cold")
 	
 	// Display drinks
 	for i, drink := range drinks {
@@ -142,17 +166,7 @@ func showFoodMenu(cart *Cart) int {
 	fmt.Println("===========")
 	fmt.Println()
 	
-	foods := []struct {
-		name  string
-		price float64
-	}{
-		{"Croissant", 3.50},
-		{"Muffin", 3.00},
-		{"Bagel", 4.00},
-		{"Sandwich", 8.50},
-		{"Salad", 9.00},
-		{"Cookie", 2.50},
-	}
+	foods := menuEntries("bakery", "food")
 	
 	// Display food items
 	for i, food := range foods {
@@ -313,11 +327,13 @@ func processPayment(total float64) {
 	fmt.Println("---------------")
 }
 
+// choiceIndex remembers how far through the scripted choices we are
+var choiceIndex int
+
 func getMenuChoice(min, max int) int {
 	// Simulate user input
 	// In a real program, you'd read from stdin
 	choices := []int{1, 1, 2, 3, 1, 3, 1, 4}
-	static choiceIndex int
 	
 	if choiceIndex < len(choices) {
 		choice := choices[choiceIndex]
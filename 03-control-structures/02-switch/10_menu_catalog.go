@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"switch-examples/catalog"
+)
+
+// The drinks and food in 09_menu_system.go, createMenu, the menu display
+// and getItemsForCategory each had their own hardcoded slice, and they
+// disagreed about prices. The catalog package (in ./catalog) loads the
+// menu from data/menu.json or data/menu.yaml, validates it, and answers
+// "can this store sell this item right now?" for printers and orders.
+
+func printMenu(menu *catalog.Catalog, store string, at time.Time) {
+	fmt.Printf("📋 %s - %s, %s\n", menu.Name, strings.ToUpper(store[:1])+store[1:], at.Format("Mon 3:04 PM"))
+
+	for _, section := range menu.Menu(store, at) {
+		fmt.Printf("\n  %s\n", strings.ToUpper(section.Category.Name))
+
+		for _, item := range section.Items {
+			var prices []string
+			for _, size := range item.Sizes() {
+				prices = append(prices, fmt.Sprintf("%s %s", size, item.Prices[size]))
+			}
+
+			status := menu.Status(item, store, at)
+			switch status {
+			case catalog.OnSale:
+				fmt.Printf("    %-20s %s\n", item.Name, strings.Join(prices, "  "))
+			case catalog.SoldOut, catalog.Disabled:
+				fmt.Printf("    %-20s ✗ %s\n", item.Name, status)
+			case catalog.OutOfHours:
+				fmt.Printf("    %-20s ⏰ %s\n", item.Name, windowsOf(item))
+			}
+		}
+	}
+	fmt.Println()
+}
+
+func windowsOf(item catalog.MenuItem) string {
+	var windows []string
+	for _, w := range item.Windows {
+		windows = append(windows, w.String())
+	}
+	return "served " + strings.Join(windows, ", ")
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Menu Catalog ===")
+	fmt.Println()
+
+	// 1. Load the same catalog from both formats
+	fromJSON, err := catalog.Load("data/menu.json")
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	fromYAML, err := catalog.Load("data/menu.yaml")
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	a, _ := json.Marshal(fromJSON)
+	b, _ := json.Marshal(fromYAML)
+	fmt.Printf("Loaded %d categories and %d items (schema v%d)\n",
+		len(fromJSON.Categories), len(fromJSON.Items), fromJSON.Version)
+	fmt.Printf("JSON and YAML catalogs identical: %t\n\n", bytes.Equal(a, b))
+
+	menu := fromYAML
+
+	// 2. Stores and times decide what shows up
+	monday := time.Date(2024, time.March, 11, 8, 30, 0, 0, time.Local)
+	saturday := time.Date(2024, time.March, 16, 12, 30, 0, 0, time.Local)
+
+	printMenu(menu, "seattle", monday)
+
+	// 3. Sold-out toggles are per store
+	menu.SetSoldOut("portland", "cold-brew", true)
+	menu.SetSoldOut("portland", "croissant", true)
+	printMenu(menu, "portland", saturday)
+
+	// 4. Orders are checked against the same data
+	fmt.Println("🧾 Checking orders at Portland, Saturday 12:30 PM")
+	lines := []struct {
+		item string
+		size string
+	}{
+		{"latte", "Large"},
+		{"espresso", ""},
+		{"breakfast-sandwich", ""},
+		{"sandwich", ""},
+		{"cold-brew", "Medium"},
+		{"pumpkin-latte", "Medium"},
+		{"latte", "Venti"},
+		{"unicorn-frappe", "Large"},
+	}
+
+	for _, line := range lines {
+		price, err := menu.Price("portland", saturday, line.item, line.size)
+
+		switch {
+		case err == nil:
+			fmt.Printf("  ✓ %-20s %-7s %s\n", line.item, line.size, price)
+		case errors.Is(err, catalog.ErrUnavailable):
+			fmt.Printf("  ⏸ %-20s %v\n", line.item, err)
+		case errors.Is(err, catalog.ErrUnknownSize):
+			fmt.Printf("  ? %-20s %v\n", line.item, err)
+		default:
+			fmt.Printf("  ✗ %-20s %v\n", line.item, err)
+		}
+	}
+	fmt.Println()
+
+	// 5. Broken catalogs are rejected with every problem at once
+	fmt.Println("🚫 Loading broken catalogs:")
+	broken := []struct {
+		name   string
+		format catalog.Format
+		data   string
+	}{
+		{"bad references", catalog.JSON, `{
+			"version": 1,
+			"categories": [{"id": "coffee", "name": "Coffee"}],
+			"items": [
+				{"id": "latte", "name": "Latte", "category": "coffee", "prices": {"Small": 4.50}},
+				{"id": "latte", "name": "Oat Latte", "category": "coffee", "prices": {"Small": 5.00}},
+				{"id": "tea", "name": "Tea", "category": "drinks", "prices": {"Small": 0}},
+				{"id": "toast", "name": "Toast", "category": "coffee", "prices": {"Regular": 3.00},
+				 "windows": [{"days": ["funday"], "from": "14:00", "to": "09:00"}]}
+			]
+		}`},
+		{"typo in a field", catalog.JSON, `{"version": 1, "items": [{"id": "latte", "prise": 4.50}]}`},
+		{"price with too many decimals", catalog.JSON, `{"version": 1, "items": [{"id": "latte", "prices": {"Small": 4.505}}]}`},
+		{"old schema", catalog.YAML, "version: 0\ncategories: []\nitems: []\n"},
+		{"bad indentation", catalog.YAML, "version: 1\nitems:\n  - id: latte\n      name: Latte\n"},
+	}
+
+	for _, test := range broken {
+		_, err := catalog.Parse([]byte(test.data), test.format)
+
+		var invalid *catalog.ValidationError
+		switch {
+		case err == nil:
+			fmt.Printf("  %s: loaded?!\n", test.name)
+		case errors.As(err, &invalid):
+			fmt.Printf("  %s:\n", test.name)
+			for _, problem := range invalid.Problems {
+				fmt.Printf("    - %s\n", problem)
+			}
+		default:
+			fmt.Printf("  %s: %v\n", test.name, err)
+		}
+	}
+}
+
+// Catalog rules:
+// 1. One file feeds every menu printer and every order check
+// 2. Validate everything on load and report all problems together
+// 3. Availability is data (stores, windows, sold out), not if statements
+// 4. Keep prices in cents, even while parsing
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"switch-examples/catalog"
+)
+
+// OrderItem.Price copies the price when an order is placed, but nothing
+// said which menu that price came from. A catalog.History keeps every
+// revision of the menu with the moment it takes effect: orders are priced
+// from the revision active when they were placed, each line remembers its
+// revision, and any two revisions can be diffed.
+
+type Order struct {
+	ID     string
+	Placed time.Time
+	Lines  []catalog.OrderLine
+}
+
+func (o Order) Total() catalog.Cents {
+	var total catalog.Cents
+	for _, line := range o.Lines {
+		total += line.Total()
+	}
+	return total
+}
+
+func placeOrder(history *catalog.History, id string, placed time.Time, items ...[2]string) (Order, error) {
+	order := Order{ID: id, Placed: placed}
+	for _, item := range items {
+		line, err := history.Line("seattle", placed, item[0], item[1], 1)
+		if err != nil {
+			return Order{}, fmt.Errorf("order %s: %w", id, err)
+		}
+		order.Lines = append(order.Lines, line)
+	}
+	return order, nil
+}
+
+func printOrder(order Order) {
+	fmt.Printf("  %s placed %s\n", order.ID, order.Placed.Format("Jan 2 15:04"))
+	for _, line := range order.Lines {
+		fmt.Printf("    %-16s %-7s %6s  (menu rev %d)\n", line.Name, line.Size, line.UnitPrice, line.Revision)
+	}
+	fmt.Printf("    %-24s %6s\n", "Total", order.Total())
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Menu Versions ===")
+	fmt.Println()
+
+	base, err := catalog.Load("data/menu.json")
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	base.EffectiveFrom = time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	history := catalog.NewHistory(base)
+
+	// 1. Schedule a price rise for 9:00 on Monday and a spring menu
+	priceRise := time.Date(2024, time.March, 11, 9, 0, 0, 0, time.UTC)
+	spring := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	rev2, err := base.Next(priceRise,
+		catalog.SetPrice("latte", "Small", 475),
+		catalog.SetPrice("latte", "Medium", 550),
+		catalog.SetPrice("latte", "Large", 625),
+		catalog.SetPrice("cold-brew", "Small", 400),
+	)
+	if err == nil {
+		err = history.Schedule(rev2)
+	}
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	rev3, err := rev2.Next(spring,
+		catalog.Rename("cold-brew", "Nitro Cold Brew"),
+		catalog.RemoveSize("macchiato", "Medium"),
+		catalog.RemoveItem("bagel"),
+		catalog.SetStores("salad"), // Every store from spring
+		catalog.SetWindows("breakfast-sandwich", catalog.Window{From: "06:00", To: "11:30"}),
+		catalog.AddItem(catalog.MenuItem{
+			ID: "lavender-latte", Name: "Lavender Latte", Category: "coffee",
+			Prices: map[string]catalog.Cents{"Medium": 575, "Large": 650}, Available: true,
+		}),
+	)
+	if err == nil {
+		err = history.Schedule(rev3)
+	}
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	fmt.Println("📅 Menu revisions:")
+	for _, rev := range history.Revisions() {
+		fmt.Printf("  rev %d from %s - %d items\n",
+			rev.Revision, rev.EffectiveFrom.Format("Mon Jan 2 15:04"), len(rev.Items))
+	}
+
+	now := time.Date(2024, time.March, 11, 10, 0, 0, 0, time.UTC)
+	for _, rev := range history.Pending(now) {
+		fmt.Printf("  ⏳ rev %d is scheduled for %s\n", rev.Revision, rev.EffectiveFrom.Format("Jan 2"))
+	}
+	fmt.Println()
+
+	// 2. Orders either side of the price rise
+	fmt.Println("🧾 Orders around the 9:00 price rise:")
+	early, _ := placeOrder(history, "A-101", priceRise.Add(-5*time.Minute), [2]string{"latte", "Large"}, [2]string{"croissant", ""})
+	late, _ := placeOrder(history, "A-102", priceRise.Add(5*time.Minute), [2]string{"latte", "Large"}, [2]string{"croissant", ""})
+
+	// A-101 is still being made at 9:05 - it keeps the price it was placed at
+	printOrder(early)
+	printOrder(late)
+	fmt.Println()
+
+	// 3. Ordering something the revision doesn't have
+	if _, err := placeOrder(history, "A-103", spring.Add(time.Hour), [2]string{"bagel", ""}); err != nil {
+		fmt.Printf("  ✗ %v\n", err)
+	}
+	if order, err := placeOrder(history, "A-104", spring.Add(time.Hour), [2]string{"lavender-latte", "Large"}); err == nil {
+		printOrder(order)
+	}
+	fmt.Println()
+
+	// 4. Reports read old orders back and check them against their revision
+	fmt.Println("🔍 Checking stored order lines:")
+	edited := early.Lines[0]
+	edited.UnitPrice = 625 // Someone "fixed" the old order to today's price
+
+	for _, line := range []catalog.OrderLine{early.Lines[0], late.Lines[0], edited} {
+		err := history.Check(line)
+		switch {
+		case err == nil:
+			fmt.Printf("  ✓ %s %s %s matches rev %d\n", line.Size, line.Name, line.UnitPrice, line.Revision)
+		case errors.Is(err, catalog.ErrPriceMismatch):
+			fmt.Printf("  ✗ %v\n", err)
+		default:
+			fmt.Printf("  ? %v\n", err)
+		}
+	}
+	fmt.Println()
+
+	// 5. What changed between revisions
+	fmt.Println("📝 Diff reports:")
+	fmt.Print(catalog.DiffReport(base, rev2))
+	fmt.Print(catalog.DiffReport(rev2, rev3))
+	fmt.Print(catalog.DiffReport(base, rev3))
+	fmt.Println()
+
+	// 6. Revisions can only be scheduled forwards
+	backdated, _ := rev3.Next(priceRise, catalog.SetPrice("espresso", "Solo", 275))
+	if err := history.Schedule(backdated); err != nil {
+		fmt.Printf("🚫 %v\n", err)
+	}
+}
+
+// Versioning rules:
+// 1. Never edit a published revision - schedule a new one
+// 2. Price an order from the revision in effect when it was placed
+// 3. Store the revision with every line so reports can explain prices
+// 4. Items keep their ID across revisions; names and prices can change
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"switch-examples/catalog"
+	"switch-examples/pos"
+)
+
+// 09_menu_system.go walks through StateMain/StateDrinks/... with scripted
+// getMenuChoice numbers, a Cart made of parallel slices, and a
+// clearScreen that only prints newlines. The pos package (in ./pos) keeps
+// the same state-and-switch design but reads real keys (arrows, Enter,
+// Esc, +/-), keeps quantities and customizations on each cart line, and
+// checks out through processPayment's rules.
+//
+// By default this demo drives the App with a FakeTerminal and prints a few
+// of its frames. Run "go run 12_pos_terminal.go live" in a terminal to use
+// the keyboard.
+
+func main() {
+	menu, err := catalog.Load("data/menu.json")
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	if len(os.Args) > 1 && os.Args[1] == "live" {
+		runLive(menu)
+		return
+	}
+
+	fmt.Println("=== GoCoffee POS Terminal (scripted) ===")
+	fmt.Println()
+
+	// A fixed clock keeps the breakfast window open and the output stable
+	monday := time.Date(2024, time.March, 11, 8, 30, 0, 0, time.Local)
+	term := &pos.FakeTerminal{}
+	app := pos.NewApp(term, menu, "seattle", func() time.Time { return monday })
+
+	steps := []struct {
+		what   string
+		script string
+	}{
+		{"Large latte with an extra shot and vanilla", "1 down down enter down down enter enter down down enter"},
+		{"Back to food, add a croissant and a muffin", "esc esc 2 enter down enter"},
+		{"Cart: five more lattes, remove the croissant", "esc 3 +*5 down d"},
+		{"Pay with the gift card - not enough on it", "esc 4 5"},
+		{"Pay by credit card instead", "up*3 enter"},
+		{"Next order, then quit", "enter ctrl-c"},
+	}
+
+	for i, step := range steps {
+		term.Feed(step.script)
+		if err := app.Run(); err != nil {
+			fmt.Printf("❌ %v\n", err)
+			return
+		}
+		printScreen(fmt.Sprintf("%d. %s", i+1, step.what), step.script, term.Screen())
+	}
+
+	fmt.Printf("The App drew %d frames and never touched a real terminal.\n", len(term.Frames))
+}
+
+func printScreen(title, script, screen string) {
+	fmt.Printf("%s\n   keys: %s\n", title, script)
+	fmt.Println("┌" + strings.Repeat("─", pos.Width+2) + "┐")
+	for _, line := range strings.Split(screen, "\n") {
+		fmt.Printf("│ %s │\n", padTo(line, pos.Width))
+	}
+	fmt.Println("└" + strings.Repeat("─", pos.Width+2) + "┘")
+	fmt.Println()
+}
+
+// padTo pads by rune count; emoji titles may still sit one column off
+func padTo(s string, width int) string {
+	n := len([]rune(s))
+	if n >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-n)
+}
+
+func runLive(menu *catalog.Catalog) {
+	term, err := pos.OpenTerminal()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+
+	app := pos.NewApp(term, menu, "seattle", time.Now)
+	runErr := app.Run()
+	term.Close()
+
+	if runErr != nil {
+		fmt.Printf("❌ %v\n", runErr)
+	}
+	if p := app.Payment(); p.Approved {
+		fmt.Printf("Last sale: %s by %s\n", p.Amount, p.Method)
+	}
+}
+
+// POS design notes:
+// 1. One state, one switch to draw it, one switch to handle keys
+// 2. The App only sees a Terminal, so a fake one can script and inspect it
+// 3. Raw mode means handling Enter, arrows and Ctrl-C yourself
+// 4. Always restore the terminal, even when something fails
@@ -0,0 +1,552 @@
+// Package catalog loads the GoCoffee menu from a JSON or YAML file so that
+// every menu printer and order check works from the same data instead of
+// its own hardcoded slice.
+//
+// A catalog file has a schema version, a list of categories and a list of
+// items. Each item can be limited to some stores and to time windows
+// (breakfast until 11:00, weekends only, ...), and can be switched off
+// everywhere with "available: false". Stores can also mark an item sold
+// out while the program runs.
+package catalog
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SchemaVersion is the catalog file version this package understands
+const SchemaVersion = 1
+
+// Cents keeps prices exact
+type Cents int64
+
+func (c Cents) String() string {
+	if c < 0 {
+		return "-" + (-c).String()
+	}
+	return fmt.Sprintf("$%d.%02d", c/100, c%100)
+}
+
+// UnmarshalJSON reads a price written as dollars (4.5, 4.50 or "4.50")
+// without going through float64, so 4.35 never becomes 434 cents
+func (c *Cents) UnmarshalJSON(data []byte) error {
+	text := strings.Trim(string(data), `"`)
+	dollars, fraction, _ := strings.Cut(text, ".")
+	if len(fraction) > 2 || dollars == "" {
+		return fmt.Errorf("invalid price %s", data)
+	}
+	fraction += strings.Repeat("0", 2-len(fraction))
+
+	d, err := strconv.ParseInt(dollars, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid price %s", data)
+	}
+	f, err := strconv.ParseInt(fraction, 10, 64)
+	if err != nil || f < 0 {
+		return fmt.Errorf("invalid price %s", data)
+	}
+
+	if strings.HasPrefix(dollars, "-") {
+		f = -f
+	}
+	*c = Cents(d*100 + f)
+	return nil
+}
+
+// MarshalJSON writes dollars, the way UnmarshalJSON reads them, so a
+// catalog saved with json.Marshal loads back with the same prices
+func (c Cents) MarshalJSON() ([]byte, error) {
+	sign := ""
+	if c < 0 {
+		sign = "-"
+		c = -c
+	}
+	return []byte(fmt.Sprintf("%s%d.%02d", sign, c/100, c%100)), nil
+}
+
+type Category struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Window is a time of day, optionally on some days only, when an item is
+// served. From and To are "HH:MM" in the store's local time.
+type Window struct {
+	Days []string `json:"days,omitempty"` // "mon" ... "sun"; empty means every day
+	From string   `json:"from"`
+	To   string   `json:"to"`
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func clockMinutes(hhmm string) (int, error) {
+	t, err := time.Parse("15:04", hhmm)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, want HH:MM", hhmm)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether at falls inside the window
+func (w Window) Contains(at time.Time) bool {
+	if len(w.Days) > 0 {
+		onDay := false
+		for _, day := range w.Days {
+			if weekdays[day] == at.Weekday() {
+				onDay = true
+				break
+			}
+		}
+		if !onDay {
+			return false
+		}
+	}
+
+	from, _ := clockMinutes(w.From)
+	to, _ := clockMinutes(w.To)
+	now := at.Hour()*60 + at.Minute()
+	return now >= from && now < to
+}
+
+func (w Window) String() string {
+	if len(w.Days) == 0 {
+		return w.From + "-" + w.To
+	}
+	return strings.Join(w.Days, ",") + " " + w.From + "-" + w.To
+}
+
+type MenuItem struct {
+	ID          string           `json:"id"`
+	Name        string           `json:"name"`
+	Category    string           `json:"category"`
+	Description string           `json:"description,omitempty"`
+	Prices      map[string]Cents `json:"prices"`            // size -> price
+	Available   bool             `json:"available"`         // false switches the item off everywhere
+	Stores      []string         `json:"stores,omitempty"`  // empty means every store
+	Windows     []Window         `json:"windows,omitempty"` // empty means all day
+}
+
+// Sizes lists the item's sizes from cheapest to most expensive
+func (m MenuItem) Sizes() []string {
+	sizes := make([]string, 0, len(m.Prices))
+	for size := range m.Prices {
+		sizes = append(sizes, size)
+	}
+	sort.Slice(sizes, func(i, j int) bool {
+		if m.Prices[sizes[i]] != m.Prices[sizes[j]] {
+			return m.Prices[sizes[i]] < m.Prices[sizes[j]]
+		}
+		return sizes[i] < sizes[j]
+	})
+	return sizes
+}
+
+// BasePrice is the price of the smallest size
+func (m MenuItem) BasePrice() Cents {
+	sizes := m.Sizes()
+	if len(sizes) == 0 {
+		return 0
+	}
+	return m.Prices[sizes[0]]
+}
+
+// Catalog is a loaded, validated menu. Version is the file's schema
+// version; Revision and EffectiveFrom place it in a History of menus.
+type Catalog struct {
+	Version       int        `json:"version"`
+	Revision      int        `json:"revision,omitempty"`
+	EffectiveFrom time.Time  `json:"effective_from"` // zero means "always"
+	Name          string     `json:"name"`
+	Categories    []Category `json:"categories"`
+	Items         []MenuItem `json:"items"`
+
+	index   map[string]int
+	soldOut map[string]map[string]bool // store -> item ID
+}
+
+// Format is a catalog file format
+type Format int
+
+const (
+	JSON Format = iota
+	YAML
+)
+
+// Load reads a catalog file, choosing the format by extension
+func Load(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var format Format
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		format = JSON
+	case ".yaml", ".yml":
+		format = YAML
+	default:
+		return nil, fmt.Errorf("%s: unknown catalog format", path)
+	}
+
+	c, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Parse decodes and validates a catalog. YAML is converted to JSON first,
+// so both formats share the same field names and the same checks.
+func Parse(data []byte, format Format) (*Catalog, error) {
+	if format == YAML {
+		doc, err := parseYAML(data)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, err
+		}
+	}
+
+	// Items are available unless the file says otherwise
+	var raw struct {
+		Catalog
+		Items []struct {
+			MenuItem
+			Available *bool `json:"available"`
+		} `json:"items"`
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields() // A typo like "prise" should fail loudly
+	if err := dec.Decode(&raw); err != nil {
+		return nil, decodeError(err)
+	}
+
+	c := raw.Catalog
+	for _, item := range raw.Items {
+		item.MenuItem.Available = item.Available == nil || *item.Available
+		c.Items = append(c.Items, item.MenuItem)
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	c.reindex()
+	return &c, nil
+}
+
+// decodeError describes a decoding failure by the file's field names.
+// encoding/json names the Go types, and the anonymous struct Parse
+// decodes into would otherwise show up in the message.
+func decodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return fmt.Errorf("decoding catalog: %s", strings.TrimPrefix(err.Error(), "json: "))
+	}
+	if typeErr.Field == "" {
+		return fmt.Errorf("decoding catalog: want an object, got %s", typeErr.Value)
+	}
+
+	want := "an object"
+	switch typeErr.Type.Kind() {
+	case reflect.String:
+		want = "a string"
+	case reflect.Bool:
+		want = "true or false"
+	case reflect.Int, reflect.Int64:
+		want = "a number"
+	case reflect.Slice:
+		want = "a list"
+	}
+	return fmt.Errorf("decoding catalog: %s: want %s, got %s", typeErr.Field, want, typeErr.Value)
+}
+
+// ValidationError lists every problem found in a catalog, so a broken
+// file can be fixed in one pass
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid catalog (%d problems):\n  - %s",
+		len(e.Problems), strings.Join(e.Problems, "\n  - "))
+}
+
+// Validate checks the schema version, unique IDs, prices, category
+// references, stores and time windows
+func (c *Catalog) Validate() error {
+	var problems []string
+	report := func(format string, args ...any) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if c.Version != SchemaVersion {
+		report("schema version %d is not supported (want %d)", c.Version, SchemaVersion)
+	}
+	if c.Revision < 0 {
+		report("revision must not be negative, got %d", c.Revision)
+	}
+
+	categories := make(map[string]bool)
+	for i, cat := range c.Categories {
+		switch {
+		case cat.ID == "":
+			report("category #%d has no id", i+1)
+		case categories[cat.ID]:
+			report("duplicate category id %q", cat.ID)
+		}
+		categories[cat.ID] = true
+	}
+
+	ids := make(map[string]bool)
+	for i, item := range c.Items {
+		name := item.ID
+		if name == "" {
+			name = fmt.Sprintf("item #%d", i+1)
+			report("%s (%s) has no id", name, item.Name)
+		} else if ids[item.ID] {
+			report("duplicate item id %q", item.ID)
+		}
+		ids[item.ID] = true
+
+		if item.Name == "" {
+			report("%s has no name", name)
+		}
+		if !categories[item.Category] {
+			report("%s refers to unknown category %q", name, item.Category)
+		}
+
+		if len(item.Prices) == 0 {
+			report("%s has no prices", name)
+		}
+		for _, size := range item.Sizes() {
+			if price := item.Prices[size]; price <= 0 {
+				report("%s: %s price must be positive, got %s", name, size, price)
+			}
+		}
+
+		for _, store := range item.Stores {
+			if store == "" {
+				report("%s has an empty store name", name)
+			}
+		}
+
+		for _, w := range item.Windows {
+			from, err1 := clockMinutes(w.From)
+			to, err2 := clockMinutes(w.To)
+			switch {
+			case err1 != nil:
+				report("%s: window %s: %v", name, w, err1)
+			case err2 != nil:
+				report("%s: window %s: %v", name, w, err2)
+			case from >= to:
+				report("%s: window %s ends before it starts", name, w)
+			}
+			for _, day := range w.Days {
+				if _, ok := weekdays[day]; !ok {
+					report("%s: window %s: unknown day %q", name, w, day)
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (c *Catalog) reindex() {
+	c.index = make(map[string]int, len(c.Items))
+	for i, item := range c.Items {
+		c.index[item.ID] = i
+	}
+	if c.soldOut == nil {
+		c.soldOut = make(map[string]map[string]bool)
+	}
+}
+
+// Lookup finds an item by ID
+func (c *Catalog) Lookup(id string) (MenuItem, bool) {
+	i, ok := c.index[id]
+	if !ok {
+		return MenuItem{}, false
+	}
+	return c.Items[i], true
+}
+
+// Category finds a category by ID
+func (c *Catalog) Category(id string) (Category, bool) {
+	for _, cat := range c.Categories {
+		if cat.ID == id {
+			return cat, true
+		}
+	}
+	return Category{}, false
+}
+
+// SetAvailable switches an item on or off at every store, like the
+// Unavailable() menu option does for a single MenuItem
+func (c *Catalog) SetAvailable(id string, available bool) error {
+	i, ok := c.index[id]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownItem, id)
+	}
+	c.Items[i].Available = available
+	return nil
+}
+
+// SetSoldOut marks an item sold out (or back in stock) at one store
+func (c *Catalog) SetSoldOut(store, id string, soldOut bool) error {
+	if _, ok := c.index[id]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownItem, id)
+	}
+	if c.soldOut[store] == nil {
+		c.soldOut[store] = make(map[string]bool)
+	}
+	c.soldOut[store][id] = soldOut
+	return nil
+}
+
+// Status says whether an item can be ordered right now
+type Status int
+
+const (
+	OnSale     Status = iota
+	NotStocked        // Not sold at this store
+	Disabled          // Switched off everywhere (available: false)
+	SoldOut           // Sold out at this store today
+	OutOfHours        // Outside every serving window
+)
+
+func (s Status) String() string {
+	switch s {
+	case OnSale:
+		return "on sale"
+	case NotStocked:
+		return "not sold at this store"
+	case Disabled:
+		return "unavailable"
+	case SoldOut:
+		return "sold out"
+	case OutOfHours:
+		return "not served at this time"
+	}
+	return "unknown"
+}
+
+// Status works out whether item can be ordered at store at the given time
+func (c *Catalog) Status(item MenuItem, store string, at time.Time) Status {
+	if len(item.Stores) > 0 {
+		stocked := false
+		for _, s := range item.Stores {
+			if s == store {
+				stocked = true
+				break
+			}
+		}
+		if !stocked {
+			return NotStocked
+		}
+	}
+
+	switch {
+	case !item.Available:
+		return Disabled
+	case c.soldOut[store][item.ID]:
+		return SoldOut
+	}
+
+	if len(item.Windows) == 0 {
+		return OnSale
+	}
+	for _, w := range item.Windows {
+		if w.Contains(at) {
+			return OnSale
+		}
+	}
+	return OutOfHours
+}
+
+// Section is one category of a store's menu
+type Section struct {
+	Category Category
+	Items    []MenuItem
+}
+
+// Menu returns what a store shows at the given time, in file order.
+// Items the store doesn't stock are left out; sold-out and out-of-hours
+// items stay in so printers can grey them out - check Status.
+func (c *Catalog) Menu(store string, at time.Time, categoryIDs ...string) []Section {
+	wanted := func(id string) bool {
+		if len(categoryIDs) == 0 {
+			return true
+		}
+		for _, want := range categoryIDs {
+			if want == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	var sections []Section
+	for _, cat := range c.Categories {
+		if !wanted(cat.ID) {
+			continue
+		}
+		section := Section{Category: cat}
+		for _, item := range c.Items {
+			if item.Category == cat.ID && c.Status(item, store, at) != NotStocked {
+				section.Items = append(section.Items, item)
+			}
+		}
+		if len(section.Items) > 0 {
+			sections = append(sections, section)
+		}
+	}
+	return sections
+}
+
+var (
+	ErrUnknownItem = errors.New("unknown menu item")
+	ErrUnknownSize = errors.New("unknown size")
+	ErrUnavailable = errors.New("item unavailable")
+)
+
+// Price validates one order line and returns its unit price. An empty
+// size means the item's smallest size.
+func (c *Catalog) Price(store string, at time.Time, id, size string) (Cents, error) {
+	item, ok := c.Lookup(id)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrUnknownItem, id)
+	}
+
+	if status := c.Status(item, store, at); status != OnSale {
+		return 0, fmt.Errorf("%w: %s is %s", ErrUnavailable, item.Name, status)
+	}
+
+	if size == "" {
+		return item.BasePrice(), nil
+	}
+	price, ok := item.Prices[size]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s comes in %s, not %q",
+			ErrUnknownSize, item.Name, strings.Join(item.Sizes(), "/"), size)
+	}
+	return price, nil
+}
@@ -0,0 +1,323 @@
+package catalog
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCentsJSONRoundTrip(t *testing.T) {
+	tests := []struct {
+		cents Cents
+		json  string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{435, "4.35"},
+		{1200, "12.00"},
+		{-50, "-0.50"},
+		{-1250, "-12.50"},
+	}
+	for _, tt := range tests {
+		data, err := json.Marshal(tt.cents)
+		if err != nil {
+			t.Fatalf("Marshal(%d): %v", tt.cents, err)
+		}
+		if string(data) != tt.json {
+			t.Errorf("Marshal(%d) = %s, want %s", tt.cents, data, tt.json)
+		}
+
+		var back Cents
+		if err := json.Unmarshal(data, &back); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if back != tt.cents {
+			t.Errorf("Unmarshal(%s) = %d, want %d", data, back, tt.cents)
+		}
+	}
+}
+
+func TestCentsUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Cents
+		wantErr bool
+	}{
+		{"4.5", 450, false},
+		{"4.50", 450, false},
+		{`"4.50"`, 450, false},
+		{"4", 400, false},
+		{"4.35", 435, false},
+		{"4.355", 0, true},
+		{"four", 0, true},
+		{".50", 0, true},
+	}
+	for _, tt := range tests {
+		var got Cents
+		err := json.Unmarshal([]byte(tt.in), &got)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %t", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+// A loaded catalog saved with json.Marshal must load back unchanged
+func TestCatalogJSONRoundTrip(t *testing.T) {
+	for _, path := range []string{"../data/menu.json", "../data/menu.yaml"} {
+		original, err := Load(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(original)
+		if err != nil {
+			t.Fatalf("%s: Marshal: %v", path, err)
+		}
+		reloaded, err := Parse(data, JSON)
+		if err != nil {
+			t.Fatalf("%s: Parse(Marshal(catalog)): %v", path, err)
+		}
+		if !reflect.DeepEqual(original.Items, reloaded.Items) {
+			t.Errorf("%s: items changed in the round trip\nbefore: %+v\nafter:  %+v", path, original.Items, reloaded.Items)
+		}
+	}
+}
+
+// item is one valid item in catalog JSON; change tweaks it
+func itemJSON(change string) string {
+	item := `{"id": "latte", "name": "Latte", "category": "coffee", "prices": {"Small": 4.50}`
+	if change != "" {
+		item += ", " + change
+	}
+	return item + "}"
+}
+
+func catalogJSON(items ...string) []byte {
+	return []byte(`{"version": 1, "categories": [{"id": "coffee", "name": "Coffee"}], "items": [` + strings.Join(items, ", ") + `]}`)
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []string // Every problem reported
+	}{
+		{"duplicate item", catalogJSON(itemJSON(""), itemJSON("")), []string{`duplicate item id "latte"`}},
+		{"zero price", catalogJSON(`{"id": "tea", "name": "Tea", "category": "coffee", "prices": {"Small": 0}}`),
+			[]string{"tea: Small price must be positive, got $0.00"}},
+		{"negative price", catalogJSON(`{"id": "tea", "name": "Tea", "category": "coffee", "prices": {"Small": -1.25}}`),
+			[]string{"tea: Small price must be positive, got -$1.25"}},
+		{"no prices", catalogJSON(`{"id": "tea", "name": "Tea", "category": "coffee", "prices": {}}`), []string{"tea has no prices"}},
+		{"unknown category", catalogJSON(`{"id": "tea", "name": "Tea", "category": "drinks", "prices": {"Small": 3}}`),
+			[]string{`tea refers to unknown category "drinks"`}},
+		{"window backwards", catalogJSON(itemJSON(`"windows": [{"from": "11:00", "to": "06:00"}]`)),
+			[]string{"latte: window 11:00-06:00 ends before it starts"}},
+		{"window empty", catalogJSON(itemJSON(`"windows": [{"from": "11:00", "to": "11:00"}]`)),
+			[]string{"latte: window 11:00-11:00 ends before it starts"}},
+		{"window bad time", catalogJSON(itemJSON(`"windows": [{"from": "6am", "to": "11:00"}]`)),
+			[]string{`latte: window 6am-11:00: invalid time "6am", want HH:MM`}},
+		{"window bad day", catalogJSON(itemJSON(`"windows": [{"days": ["sat", "sunday"], "from": "06:00", "to": "11:00"}]`)),
+			[]string{`latte: window sat,sunday 06:00-11:00: unknown day "sunday"`}},
+		{"every problem at once", catalogJSON(
+			`{"name": "Tea", "category": "coffee", "prices": {"Small": 3}}`,
+			`{"id": "mocha", "category": "drinks", "prices": {"Small": 0}}`,
+		), []string{
+			"item #1 (Tea) has no id",
+			"mocha has no name",
+			`mocha refers to unknown category "drinks"`,
+			"mocha: Small price must be positive, got $0.00",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.data, JSON)
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) {
+				t.Fatalf("Parse error = %v, want a ValidationError", err)
+			}
+			if !reflect.DeepEqual(invalid.Problems, tt.want) {
+				t.Errorf("problems = %q, want %q", invalid.Problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateVersionAndCategories(t *testing.T) {
+	c := &Catalog{
+		Version:    2,
+		Revision:   -1,
+		Categories: []Category{{ID: "coffee"}, {ID: "coffee"}, {Name: "Bakery"}},
+	}
+	var invalid *ValidationError
+	if err := c.Validate(); !errors.As(err, &invalid) {
+		t.Fatalf("Validate = %v, want a ValidationError", err)
+	}
+	want := []string{
+		"schema version 2 is not supported (want 1)",
+		"revision must not be negative, got -1",
+		`duplicate category id "coffee"`,
+		"category #3 has no id",
+	}
+	if !reflect.DeepEqual(invalid.Problems, want) {
+		t.Errorf("problems = %q, want %q", invalid.Problems, want)
+	}
+}
+
+// Decoding errors name the file's fields, not the Go types behind them
+func TestParseDecodeErrors(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{`[1, 2]`, "decoding catalog: want an object, got array"},
+		{`{"version": "1"}`, "decoding catalog: version: want a number, got string"},
+		{`{"version": 1, "items": [{"id": "latte", "available": "yes"}]}`, "decoding catalog: items.0.available: want true or false, got string"},
+		{`{"version": 1, "items": [{"windows": [{"from": 6}]}]}`, "decoding catalog: items.0.windows.0.from: want a string, got number"},
+		{`{"version": 1, "items": [{"prise": 4.50}]}`, `decoding catalog: unknown field "prise"`},
+		{`{"version": 1,`, "decoding catalog: unexpected EOF"},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.data), JSON)
+		if err == nil || err.Error() != tt.want {
+			t.Errorf("Parse(%s) error = %v, want %q", tt.data, err, tt.want)
+		}
+	}
+}
+
+func loadMenu(t *testing.T) *Catalog {
+	t.Helper()
+	c, err := Load("../data/menu.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestStatus(t *testing.T) {
+	monday := time.Date(2024, time.March, 11, 8, 30, 0, 0, time.Local)
+	saturday := time.Date(2024, time.March, 16, 12, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name  string
+		id    string
+		store string
+		at    time.Time
+		want  Status
+	}{
+		{"all day", "latte", "seattle", monday, OnSale},
+		{"breakfast window", "breakfast-sandwich", "seattle", monday, OnSale},
+		{"after breakfast", "breakfast-sandwich", "seattle", monday.Add(3 * time.Hour), OutOfHours},
+		{"weekend brunch window", "breakfast-sandwich", "seattle", saturday, OnSale},
+		{"window end is exclusive", "sandwich", "seattle", time.Date(2024, time.March, 11, 20, 0, 0, 0, time.Local), OutOfHours},
+		{"not stocked", "salad", "boston", monday.Add(3 * time.Hour), NotStocked},
+		{"disabled", "pumpkin-latte", "seattle", monday, Disabled},
+		{"not stocked beats disabled", "pumpkin-latte", "portland", monday, NotStocked},
+	}
+	c := loadMenu(t)
+	for _, tt := range tests {
+		item, ok := c.Lookup(tt.id)
+		if !ok {
+			t.Fatalf("no item %q in the menu", tt.id)
+		}
+		if got := c.Status(item, tt.store, tt.at); got != tt.want {
+			t.Errorf("%s: Status(%s, %s) = %s, want %s", tt.name, tt.id, tt.store, got, tt.want)
+		}
+	}
+}
+
+func TestSoldOutAndAvailable(t *testing.T) {
+	c := loadMenu(t)
+	at := time.Date(2024, time.March, 11, 8, 30, 0, 0, time.Local)
+	status := func(store string) Status {
+		item, _ := c.Lookup("latte")
+		return c.Status(item, store, at)
+	}
+
+	if err := c.SetSoldOut("seattle", "latte", true); err != nil {
+		t.Fatal(err)
+	}
+	if got := status("seattle"); got != SoldOut {
+		t.Errorf("sold out at seattle: %s", got)
+	}
+	if got := status("portland"); got != OnSale {
+		t.Errorf("selling out at seattle changed portland to %s", got)
+	}
+	if _, err := c.Price("seattle", at, "latte", "Small"); !errors.Is(err, ErrUnavailable) {
+		t.Errorf("Price of a sold-out item: err = %v, want ErrUnavailable", err)
+	}
+
+	if err := c.SetSoldOut("seattle", "latte", false); err != nil {
+		t.Fatal(err)
+	}
+	if got := status("seattle"); got != OnSale {
+		t.Errorf("back in stock: %s", got)
+	}
+
+	if err := c.SetAvailable("latte", false); err != nil {
+		t.Fatal(err)
+	}
+	if got := status("portland"); got != Disabled {
+		t.Errorf("switched off: %s at portland", got)
+	}
+
+	for _, err := range []error{c.SetSoldOut("seattle", "flat-white", true), c.SetAvailable("flat-white", false)} {
+		if !errors.Is(err, ErrUnknownItem) {
+			t.Errorf("unknown item: err = %v, want ErrUnknownItem", err)
+		}
+	}
+}
+
+func TestMenu(t *testing.T) {
+	c := loadMenu(t)
+	lunch := time.Date(2024, time.March, 11, 12, 0, 0, 0, time.Local)
+	if err := c.SetSoldOut("boston", "muffin", true); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(sections []Section) map[string][]string {
+		out := map[string][]string{}
+		for _, s := range sections {
+			for _, item := range s.Items {
+				out[s.Category.ID] = append(out[s.Category.ID], item.ID)
+			}
+		}
+		return out
+	}
+
+	// Boston doesn't stock the salad or the pumpkin latte; the sold-out
+	// muffin and out-of-hours breakfast sandwich stay in for graying out
+	got := ids(c.Menu("boston", lunch, "bakery", "food"))
+	want := map[string][]string{
+		"bakery": {"croissant", "muffin", "bagel", "cookie"},
+		"food":   {"breakfast-sandwich", "sandwich"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("boston bakery and food = %v, want %v", got, want)
+	}
+
+	seattle := c.Menu("seattle", lunch)
+	if len(seattle) != len(c.Categories) {
+		t.Errorf("no category filter gave %d sections, want all %d", len(seattle), len(c.Categories))
+	}
+	if got := ids(seattle)["coffee"]; !contains(got, "pumpkin-latte") {
+		t.Errorf("seattle coffee = %v, want the disabled pumpkin latte listed", got)
+	}
+	if got := c.Menu("seattle", lunch, "tea"); len(got) != 0 {
+		t.Errorf("unknown category gave %d sections", len(got))
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
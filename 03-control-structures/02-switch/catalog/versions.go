@@ -0,0 +1,469 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// A History keeps every revision of the menu with the time it takes
+// effect. Orders are priced from the revision active when they were
+// placed and remember that revision, so raising the latte price at 9:00
+// doesn't change what an 8:59 order costs, and reports can show exactly
+// which menu a price came from.
+type History struct {
+	revisions []*Catalog // Sorted by EffectiveFrom
+}
+
+// NewHistory starts a history with base as revision 1 (unless the file
+// set a revision)
+func NewHistory(base *Catalog) *History {
+	if base.Revision == 0 {
+		base.Revision = 1
+	}
+	return &History{revisions: []*Catalog{base}}
+}
+
+// Schedule adds a revision that takes effect at its EffectiveFrom, which
+// must be after every revision already in the history
+func (h *History) Schedule(next *Catalog) error {
+	last := h.revisions[len(h.revisions)-1]
+	if !next.EffectiveFrom.After(last.EffectiveFrom) {
+		return fmt.Errorf("revision effective %s must start after revision %d (%s)",
+			next.EffectiveFrom.Format(time.RFC3339), last.Revision, last.EffectiveFrom.Format(time.RFC3339))
+	}
+
+	if next.Revision == 0 {
+		next.Revision = last.Revision + 1
+	}
+	if next.Revision <= last.Revision {
+		return fmt.Errorf("revision %d must be greater than %d", next.Revision, last.Revision)
+	}
+	if err := next.Validate(); err != nil {
+		return err
+	}
+
+	next.soldOut = last.soldOut
+	next.reindex()
+	h.revisions = append(h.revisions, next)
+	return nil
+}
+
+// At returns the revision in effect at t. Before the first revision takes
+// effect the first revision is used.
+func (h *History) At(t time.Time) *Catalog {
+	// The first revision whose start is after t, minus one
+	i := sort.Search(len(h.revisions), func(i int) bool {
+		return h.revisions[i].EffectiveFrom.After(t)
+	})
+	if i == 0 {
+		return h.revisions[0]
+	}
+	return h.revisions[i-1]
+}
+
+// Revision finds a revision by number
+func (h *History) Revision(n int) (*Catalog, bool) {
+	for _, c := range h.revisions {
+		if c.Revision == n {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Revisions lists every revision, oldest first
+func (h *History) Revisions() []*Catalog {
+	return append([]*Catalog(nil), h.revisions...)
+}
+
+// Pending lists the revisions that haven't taken effect yet at now
+func (h *History) Pending(now time.Time) []*Catalog {
+	var pending []*Catalog
+	for _, c := range h.revisions {
+		if c.EffectiveFrom.After(now) {
+			pending = append(pending, c)
+		}
+	}
+	return pending
+}
+
+// SetSoldOut marks an item sold out at a store in every revision. Stock
+// is about today, not about which menu is printed.
+func (h *History) SetSoldOut(store, id string, soldOut bool) error {
+	return h.revisions[0].SetSoldOut(store, id, soldOut)
+}
+
+// === Scheduled changes ===
+
+// Change edits a copy of a catalog for the next revision
+type Change func(*Catalog) error
+
+// SetPrice changes the price of one size, adding the size if it is new
+func SetPrice(id, size string, price Cents) Change {
+	return func(c *Catalog) error {
+		i, ok := c.index[id]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownItem, id)
+		}
+		c.Items[i].Prices[size] = price
+		return nil
+	}
+}
+
+// RemoveSize stops selling one size of an item
+func RemoveSize(id, size string) Change {
+	return func(c *Catalog) error {
+		i, ok := c.index[id]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownItem, id)
+		}
+		if _, ok := c.Items[i].Prices[size]; !ok {
+			return fmt.Errorf("%w: %s has no %q", ErrUnknownSize, c.Items[i].Name, size)
+		}
+		delete(c.Items[i].Prices, size)
+		return nil
+	}
+}
+
+// Rename changes an item's display name; its ID stays the same
+func Rename(id, name string) Change {
+	return func(c *Catalog) error {
+		i, ok := c.index[id]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownItem, id)
+		}
+		c.Items[i].Name = name
+		return nil
+	}
+}
+
+// SetAvailable switches an item on or off from this revision on
+func SetAvailable(id string, available bool) Change {
+	return func(c *Catalog) error {
+		return c.SetAvailable(id, available)
+	}
+}
+
+// SetStores limits an item to some stores; no stores means every store
+func SetStores(id string, stores ...string) Change {
+	return func(c *Catalog) error {
+		i, ok := c.index[id]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownItem, id)
+		}
+		c.Items[i].Stores = append([]string(nil), stores...)
+		return nil
+	}
+}
+
+// SetWindows changes when an item is served; no windows means all day
+func SetWindows(id string, windows ...Window) Change {
+	return func(c *Catalog) error {
+		i, ok := c.index[id]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownItem, id)
+		}
+		c.Items[i].Windows = append([]Window(nil), windows...)
+		return nil
+	}
+}
+
+// AddItem puts a new item on the menu
+func AddItem(item MenuItem) Change {
+	return func(c *Catalog) error {
+		if _, exists := c.index[item.ID]; exists {
+			return fmt.Errorf("item %q already exists", item.ID)
+		}
+		// Copy the maps and slices: the caller's item must not change when a
+		// later SetPrice edits this revision, and a nil Prices can't be set
+		prices := make(map[string]Cents, len(item.Prices))
+		for size, price := range item.Prices {
+			prices[size] = price
+		}
+		item.Prices = prices
+		item.Stores = append([]string(nil), item.Stores...)
+		item.Windows = append([]Window(nil), item.Windows...)
+		c.Items = append(c.Items, item)
+		c.reindex()
+		return nil
+	}
+}
+
+// RemoveItem takes an item off the menu
+func RemoveItem(id string) Change {
+	return func(c *Catalog) error {
+		i, ok := c.index[id]
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownItem, id)
+		}
+		c.Items = append(c.Items[:i], c.Items[i+1:]...)
+		c.reindex()
+		return nil
+	}
+}
+
+// Next copies c, applies the changes and returns the copy as a new
+// revision taking effect at from. c itself is never modified, so orders
+// already priced from it keep their prices.
+func (c *Catalog) Next(from time.Time, changes ...Change) (*Catalog, error) {
+	next := c.clone()
+	next.Revision = 0 // Assigned by History.Schedule
+	next.EffectiveFrom = from
+
+	for _, change := range changes {
+		if err := change(next); err != nil {
+			return nil, err
+		}
+	}
+	return next, nil
+}
+
+// clone deep-copies the menu. The sold-out table is shared on purpose.
+func (c *Catalog) clone() *Catalog {
+	next := *c
+	next.Categories = append([]Category(nil), c.Categories...)
+	next.Items = make([]MenuItem, len(c.Items))
+
+	for i, item := range c.Items {
+		item.Prices = make(map[string]Cents, len(c.Items[i].Prices))
+		for size, price := range c.Items[i].Prices {
+			item.Prices[size] = price
+		}
+		item.Stores = append([]string(nil), item.Stores...)
+		item.Windows = append([]Window(nil), item.Windows...)
+		next.Items[i] = item
+	}
+
+	next.reindex()
+	return &next
+}
+
+// === Order lines ===
+
+// OrderLine is one priced line of an order. It copies the price like
+// OrderItem.Price does, and also records the revision it came from.
+type OrderLine struct {
+	ItemID    string
+	Name      string
+	Size      string
+	Quantity  int
+	UnitPrice Cents
+	Revision  int
+}
+
+func (l OrderLine) Total() Cents {
+	return l.UnitPrice * Cents(l.Quantity)
+}
+
+// ErrPriceMismatch means an order line doesn't match its revision
+var ErrPriceMismatch = errors.New("price does not match catalog revision")
+
+// Line prices an order line from the revision in effect when the order
+// was placed
+func (h *History) Line(store string, placed time.Time, id, size string, quantity int) (OrderLine, error) {
+	menu := h.At(placed)
+
+	price, err := menu.Price(store, placed, id, size)
+	if err != nil {
+		return OrderLine{}, err
+	}
+
+	item, _ := menu.Lookup(id)
+	if size == "" {
+		size = item.Sizes()[0]
+	}
+	return OrderLine{
+		ItemID:    id,
+		Name:      item.Name,
+		Size:      size,
+		Quantity:  quantity,
+		UnitPrice: price,
+		Revision:  menu.Revision,
+	}, nil
+}
+
+// Check confirms a stored order line against the revision it names, e.g.
+// when a report or refund reads old orders back
+func (h *History) Check(line OrderLine) error {
+	menu, ok := h.Revision(line.Revision)
+	if !ok {
+		return fmt.Errorf("%s: unknown catalog revision %d", line.Name, line.Revision)
+	}
+	item, ok := menu.Lookup(line.ItemID)
+	if !ok {
+		return fmt.Errorf("%w: %q in revision %d", ErrUnknownItem, line.ItemID, line.Revision)
+	}
+	if price := item.Prices[line.Size]; price != line.UnitPrice {
+		return fmt.Errorf("%w: %s %s charged %s, revision %d says %s",
+			ErrPriceMismatch, line.Size, item.Name, line.UnitPrice, line.Revision, price)
+	}
+	return nil
+}
+
+// === Diffs ===
+
+// ChangeKind says what changed about an item between two revisions
+type ChangeKind int
+
+const (
+	Added ChangeKind = iota
+	Removed
+	PriceChanged
+	SizeAdded
+	SizeRemoved
+	Renamed
+	AvailabilityChanged
+	StoresChanged
+	WindowsChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case PriceChanged:
+		return "price"
+	case SizeAdded:
+		return "new size"
+	case SizeRemoved:
+		return "size removed"
+	case Renamed:
+		return "renamed"
+	case AvailabilityChanged:
+		return "availability"
+	case StoresChanged:
+		return "stores"
+	case WindowsChanged:
+		return "hours"
+	}
+	return "unknown"
+}
+
+// Difference is one change between two revisions
+type Difference struct {
+	Kind   ChangeKind
+	ItemID string
+	Size   string
+	Old    string
+	New    string
+}
+
+func (d Difference) String() string {
+	subject := d.ItemID
+	if d.Size != "" {
+		subject += " (" + d.Size + ")"
+	}
+
+	switch d.Kind {
+	case Added, Removed:
+		return fmt.Sprintf("%-13s %s", d.Kind, subject)
+	default:
+		return fmt.Sprintf("%-13s %s: %s → %s", d.Kind, subject, d.Old, d.New)
+	}
+}
+
+// Diff lists what changed from one revision to another, item by item
+// in the old order with new items last. Names, availability, prices and
+// sizes, stores and serving hours are compared; descriptions and
+// categories are not.
+func Diff(from, to *Catalog) []Difference {
+	var diffs []Difference
+
+	for _, before := range from.Items {
+		after, ok := to.Lookup(before.ID)
+		if !ok {
+			diffs = append(diffs, Difference{Kind: Removed, ItemID: before.ID})
+			continue
+		}
+
+		if before.Name != after.Name {
+			diffs = append(diffs, Difference{Kind: Renamed, ItemID: before.ID, Old: before.Name, New: after.Name})
+		}
+		if before.Available != after.Available {
+			diffs = append(diffs, Difference{Kind: AvailabilityChanged, ItemID: before.ID,
+				Old: availability(before.Available), New: availability(after.Available)})
+		}
+		if was, now := storeList(before.Stores), storeList(after.Stores); was != now {
+			diffs = append(diffs, Difference{Kind: StoresChanged, ItemID: before.ID, Old: was, New: now})
+		}
+		if was, now := windowList(before.Windows), windowList(after.Windows); was != now {
+			diffs = append(diffs, Difference{Kind: WindowsChanged, ItemID: before.ID, Old: was, New: now})
+		}
+
+		for _, size := range before.Sizes() {
+			newPrice, ok := after.Prices[size]
+			switch {
+			case !ok:
+				diffs = append(diffs, Difference{Kind: SizeRemoved, ItemID: before.ID, Size: size,
+					Old: before.Prices[size].String(), New: "-"})
+			case newPrice != before.Prices[size]:
+				diffs = append(diffs, Difference{Kind: PriceChanged, ItemID: before.ID, Size: size,
+					Old: before.Prices[size].String(), New: newPrice.String()})
+			}
+		}
+		for _, size := range after.Sizes() {
+			if _, ok := before.Prices[size]; !ok {
+				diffs = append(diffs, Difference{Kind: SizeAdded, ItemID: before.ID, Size: size,
+					Old: "-", New: after.Prices[size].String()})
+			}
+		}
+	}
+
+	for _, after := range to.Items {
+		if _, ok := from.Lookup(after.ID); !ok {
+			diffs = append(diffs, Difference{Kind: Added, ItemID: after.ID})
+		}
+	}
+	return diffs
+}
+
+func availability(available bool) string {
+	if available {
+		return "available"
+	}
+	return "unavailable"
+}
+
+// storeList and windowList describe where and when an item is sold.
+// Order doesn't matter, so both are sorted before comparing.
+func storeList(stores []string) string {
+	if len(stores) == 0 {
+		return "all stores"
+	}
+	sorted := append([]string(nil), stores...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ", ")
+}
+
+func windowList(windows []Window) string {
+	if len(windows) == 0 {
+		return "all day"
+	}
+	var list []string
+	for _, w := range windows {
+		list = append(list, w.String())
+	}
+	sort.Strings(list)
+	return strings.Join(list, "; ")
+}
+
+// DiffReport formats Diff as a short text report
+func DiffReport(from, to *Catalog) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Revision %d → %d (effective %s)\n",
+		from.Revision, to.Revision, to.EffectiveFrom.Format("Mon Jan 2 15:04"))
+
+	diffs := Diff(from, to)
+	if len(diffs) == 0 {
+		b.WriteString("  no changes\n")
+	}
+	for _, d := range diffs {
+		fmt.Fprintf(&b, "  %s\n", d)
+	}
+	return b.String()
+}
@@ -0,0 +1,108 @@
+package catalog
+
+import (
+	"testing"
+	"time"
+)
+
+func testCatalog(t *testing.T) *Catalog {
+	t.Helper()
+	c, err := Load("../data/menu.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestAddItemThenSetPrice(t *testing.T) {
+	base := testCatalog(t)
+	from := time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC)
+
+	// No prices yet: the size is added by SetPrice in the same revision
+	added := MenuItem{ID: "chai", Name: "Chai Latte", Category: "coffee", Available: true}
+	next, err := base.Next(from, AddItem(added), SetPrice("chai", "Medium", 495))
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	item, ok := next.Lookup("chai")
+	if !ok || item.Prices["Medium"] != 495 {
+		t.Fatalf("chai = %+v, want Medium at 495", item)
+	}
+	if added.Prices != nil {
+		t.Error("AddItem handed the caller's item to the revision")
+	}
+
+	// With prices: the caller's map is copied, not shared
+	prices := map[string]Cents{"Medium": 575}
+	next, err = base.Next(from,
+		AddItem(MenuItem{ID: "lavender", Name: "Lavender Latte", Category: "coffee", Prices: prices, Available: true}),
+		SetPrice("lavender", "Medium", 600),
+	)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	if prices["Medium"] != 575 {
+		t.Errorf("SetPrice changed the caller's map to %d", prices["Medium"])
+	}
+	if item, _ := next.Lookup("lavender"); item.Prices["Medium"] != 600 {
+		t.Errorf("lavender Medium = %d, want 600", item.Prices["Medium"])
+	}
+}
+
+func TestDiff(t *testing.T) {
+	base := testCatalog(t)
+	next, err := base.Next(time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+		SetPrice("latte", "Small", 475),
+		SetPrice("cold-brew", "Small", 400),
+		RemoveSize("macchiato", "Medium"),
+		Rename("mocha", "Café Mocha"),
+		SetAvailable("pumpkin-latte", true),
+		SetStores("salad"),
+		SetStores("pumpkin-latte", "seattle", "portland"),
+		SetWindows("sandwich", Window{From: "11:00", To: "21:00"}),
+		RemoveItem("bagel"),
+		AddItem(MenuItem{ID: "chai", Name: "Chai Latte", Category: "coffee",
+			Prices: map[string]Cents{"Medium": 495}, Available: true}),
+	)
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+
+	want := map[Difference]bool{
+		{Kind: PriceChanged, ItemID: "latte", Size: "Small", Old: "$4.50", New: "$4.75"}:           true,
+		{Kind: SizeAdded, ItemID: "cold-brew", Size: "Small", Old: "-", New: "$4.00"}:              true,
+		{Kind: SizeRemoved, ItemID: "macchiato", Size: "Medium", Old: "$5.00", New: "-"}:           true,
+		{Kind: Renamed, ItemID: "mocha", Old: "Mocha", New: "Café Mocha"}:                          true,
+		{Kind: AvailabilityChanged, ItemID: "pumpkin-latte", Old: "unavailable", New: "available"}: true,
+		{Kind: StoresChanged, ItemID: "salad", Old: "portland, seattle", New: "all stores"}:        true,
+		{Kind: StoresChanged, ItemID: "pumpkin-latte", Old: "seattle", New: "portland, seattle"}:   true,
+		{Kind: WindowsChanged, ItemID: "sandwich", Old: "11:00-20:00", New: "11:00-21:00"}:         true,
+		{Kind: Removed, ItemID: "bagel"}: true,
+		{Kind: Added, ItemID: "chai"}:    true,
+	}
+
+	got := Diff(base, next)
+	for _, d := range got {
+		if !want[d] {
+			t.Errorf("unexpected difference %v", d)
+		}
+		delete(want, d)
+	}
+	for d := range want {
+		t.Errorf("missing difference %v", d)
+	}
+}
+
+// Listing the same stores in another order is not a change
+func TestDiffIgnoresStoreOrder(t *testing.T) {
+	base := testCatalog(t)
+	next, err := base.Next(time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC),
+		SetStores("salad", "portland", "seattle"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diffs := Diff(base, next); len(diffs) != 0 {
+		t.Errorf("Diff = %v, want none", diffs)
+	}
+}
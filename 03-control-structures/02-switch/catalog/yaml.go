@@ -0,0 +1,307 @@
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// The standard library has no YAML package, and a catalog only needs a
+// small part of YAML: nested mappings and "- " lists by indentation,
+// [a, b] and {k: v} on one line, quoted or plain scalars and # comments.
+// parseYAML turns that subset into the same maps and slices that
+// encoding/json works with. Anchors, multi-line strings and multiple
+// documents are not supported.
+
+type yamlLine struct {
+	number int // 1-based, for error messages
+	indent int
+	text   string
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func parseYAML(data []byte) (any, error) {
+	p := &yamlParser{}
+	for i, raw := range strings.Split(string(data), "\n") {
+		raw = strings.TrimRight(stripComment(raw), " \r")
+		text := strings.TrimLeft(raw, " ")
+		if text == "" || text == "---" {
+			continue
+		}
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("yaml line %d: tabs are not allowed for indentation", i+1)
+		}
+		p.lines = append(p.lines, yamlLine{number: i + 1, indent: len(raw) - len(text), text: text})
+	}
+
+	if len(p.lines) == 0 {
+		return map[string]any{}, nil
+	}
+	value, err := p.block(p.lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.lines) {
+		return nil, p.errorf(p.lines[p.pos], "unexpected indentation")
+	}
+	return value, nil
+}
+
+func (p *yamlParser) errorf(line yamlLine, format string, args ...any) error {
+	return fmt.Errorf("yaml line %d: %s", line.number, fmt.Sprintf(format, args...))
+}
+
+func isListItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// block parses the mapping or list starting at the current line
+func (p *yamlParser) block(indent int) (any, error) {
+	if isListItem(p.lines[p.pos].text) {
+		return p.list(indent)
+	}
+	return p.mapping(indent)
+}
+
+func (p *yamlParser) mapping(indent int) (any, error) {
+	result := make(map[string]any)
+
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && isListItem(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf(line, "unexpected indentation")
+		}
+
+		key, rest, ok := splitKey(line.text)
+		if !ok {
+			return nil, p.errorf(line, "expected \"key: value\", got %q", line.text)
+		}
+		if _, dup := result[key]; dup {
+			return nil, p.errorf(line, "duplicate key %q", key)
+		}
+		p.pos++
+
+		if rest != "" {
+			value, err := parseInline(rest)
+			if err != nil {
+				return nil, p.errorf(line, "%v", err)
+			}
+			result[key] = value
+			continue
+		}
+
+		// The value is the nested block below, if any. A list may start at
+		// the key's own indentation.
+		if p.pos < len(p.lines) {
+			next := p.lines[p.pos]
+			if next.indent > indent || (next.indent == indent && isListItem(next.text)) {
+				value, err := p.block(next.indent)
+				if err != nil {
+					return nil, err
+				}
+				result[key] = value
+				continue
+			}
+		}
+		result[key] = nil
+	}
+	return result, nil
+}
+
+func (p *yamlParser) list(indent int) (any, error) {
+	result := []any{}
+
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent != indent || !isListItem(line.text) {
+			if line.indent > indent {
+				return nil, p.errorf(line, "unexpected indentation")
+			}
+			break
+		}
+
+		rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		if rest == "" {
+			// "-" alone: the item is the block below
+			p.pos++
+			if p.pos >= len(p.lines) || p.lines[p.pos].indent <= indent {
+				result = append(result, nil)
+				continue
+			}
+			value, err := p.block(p.lines[p.pos].indent)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+			continue
+		}
+
+		if _, _, isKey := splitKey(rest); isKey {
+			// "- id: latte" starts a mapping whose keys line up with "id"
+			itemIndent := indent + len(line.text) - len(rest)
+			p.lines[p.pos] = yamlLine{number: line.number, indent: itemIndent, text: rest}
+			value, err := p.mapping(itemIndent)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+			continue
+		}
+
+		value, err := parseInline(rest)
+		if err != nil {
+			return nil, p.errorf(line, "%v", err)
+		}
+		result = append(result, value)
+		p.pos++
+	}
+	return result, nil
+}
+
+// splitKey splits "key: value" or "key:" outside of quotes and brackets
+func splitKey(text string) (key, rest string, ok bool) {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return "", "", false
+	}
+
+	quote := byte(0)
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ':' && (i == len(text)-1 || text[i+1] == ' '):
+			key = unquote(strings.TrimSpace(text[:i]))
+			return key, strings.TrimSpace(text[i+1:]), key != ""
+		}
+	}
+	return "", "", false
+}
+
+// parseInline parses a scalar, [list] or {map} written on one line
+func parseInline(text string) (any, error) {
+	switch {
+	case strings.HasPrefix(text, "["):
+		if !strings.HasSuffix(text, "]") {
+			return nil, fmt.Errorf("unclosed [ in %q", text)
+		}
+		result := []any{}
+		for _, part := range splitFlow(text[1 : len(text)-1]) {
+			value, err := parseInline(part)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, value)
+		}
+		return result, nil
+
+	case strings.HasPrefix(text, "{"):
+		if !strings.HasSuffix(text, "}") {
+			return nil, fmt.Errorf("unclosed { in %q", text)
+		}
+		result := make(map[string]any)
+		for _, part := range splitFlow(text[1 : len(text)-1]) {
+			key, rest, ok := splitKey(part)
+			if !ok {
+				return nil, fmt.Errorf("expected \"key: value\" in %q", part)
+			}
+			value, err := parseInline(rest)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = value
+		}
+		return result, nil
+	}
+
+	return scalar(text), nil
+}
+
+// splitFlow splits the inside of [..] or {..} on top-level commas
+func splitFlow(text string) []string {
+	var parts []string
+	depth, start := 0, 0
+	quote := byte(0)
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		case c == ',' && depth == 0:
+			parts = append(parts, strings.TrimSpace(text[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(text[start:]); last != "" {
+		parts = append(parts, last)
+	}
+	return parts
+}
+
+// scalar types a plain value the way YAML does. Numbers stay as their
+// original text (json.Number) so prices are never rounded through float64.
+func scalar(text string) any {
+	if len(text) >= 2 && (text[0] == '"' || text[0] == '\'') && text[len(text)-1] == text[0] {
+		return unquote(text)
+	}
+
+	switch text {
+	case "null", "~":
+		return nil
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+
+	if n := json.Number(text); json.Valid([]byte(n)) && strings.Trim(text, "-0123456789.eE+") == "" {
+		return n
+	}
+	return text
+}
+
+func unquote(text string) string {
+	if len(text) >= 2 && (text[0] == '"' || text[0] == '\'') && text[len(text)-1] == text[0] {
+		return text[1 : len(text)-1]
+	}
+	return text
+}
+
+// stripComment removes a # comment that isn't inside quotes
+func stripComment(line string) string {
+	quote := byte(0)
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' '):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,128 @@
+package catalog
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want any
+	}{
+		{"empty", "# nothing here\n---\n", map[string]any{}},
+		{"scalars", `
+name: GoCoffee   # trailing comment
+quoted: "Tea # not a comment"
+single: 'Chai: spiced'
+on: true
+off: false
+none: ~
+price: 4.50
+count: -3
+time: 06:00
+`, map[string]any{
+			"name": "GoCoffee", "quoted": "Tea # not a comment", "single": "Chai: spiced",
+			"on": true, "off": false, "none": nil,
+			"price": json.Number("4.50"), "count": json.Number("-3"), "time": "06:00",
+		}},
+		{"nested mapping", `
+prices:
+  Small: 4.50
+  Large: 6.00
+empty:
+`, map[string]any{
+			"prices": map[string]any{"Small": json.Number("4.50"), "Large": json.Number("6.00")},
+			"empty":  nil,
+		}},
+		{"list at the key's indentation", `
+stores:
+- seattle
+- portland
+`, map[string]any{"stores": []any{"seattle", "portland"}}},
+		{"list of mappings", `
+items:
+  - id: latte
+    prices: {Small: 4.50, "Extra Large": 7}
+  -
+    id: tea
+    stores: [seattle, "port, land", []]
+`, map[string]any{"items": []any{
+			map[string]any{"id": "latte", "prices": map[string]any{"Small": json.Number("4.50"), "Extra Large": json.Number("7")}},
+			map[string]any{"id": "tea", "stores": []any{"seattle", "port, land", []any{}}},
+		}}},
+		{"top-level list", "- a\n- {k: v}\n", []any{"a", map[string]any{"k": "v"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseYAML([]byte(tt.yaml))
+			if err != nil {
+				t.Fatalf("parseYAML: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseYAML =\n%#v\nwant\n%#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		yaml string
+		want string
+	}{
+		{"name: a\n\tprices: b\n", "yaml line 2: tabs are not allowed"},
+		{"name: a\n  extra: b\n", "yaml line 2: unexpected indentation"},
+		{"name: a\nname: b\n", `yaml line 2: duplicate key "name"`},
+		{"name: a\njust text\n", `yaml line 2: expected "key: value", got "just text"`},
+		{"stores: [seattle, portland\n", "yaml line 1: unclosed [ "},
+		{"prices: {Small 4.50}\n", `yaml line 1: expected "key: value" in "Small 4.50"`},
+		{"items:\n  - a\n    - b\n", "yaml line 3: unexpected indentation"},
+	}
+	for _, tt := range tests {
+		_, err := parseYAML([]byte(tt.yaml))
+		if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+			t.Errorf("parseYAML(%q) error = %v, want %q", tt.yaml, err, tt.want)
+		}
+	}
+}
+
+// The YAML file is the JSON one written differently; both must load the
+// same catalog
+func TestYAMLMatchesJSON(t *testing.T) {
+	fromJSON, err := Load("../data/menu.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromYAML, err := Load("../data/menu.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(fromJSON.Categories, fromYAML.Categories) || !reflect.DeepEqual(fromJSON.Items, fromYAML.Items) {
+		t.Errorf("menu.yaml differs from menu.json\nyaml: %+v\njson: %+v", fromYAML.Items, fromJSON.Items)
+	}
+}
+
+// YAML goes through the same decoding and validation as JSON
+func TestParseYAMLCatalog(t *testing.T) {
+	_, err := Parse([]byte(`
+version: 1
+categories: [{id: coffee, name: Coffee}]
+items:
+  - id: latte
+    name: Latte
+    category: coffee
+    prices: {Small: 0}
+`), YAML)
+	if err == nil || !strings.Contains(err.Error(), "latte: Small price must be positive") {
+		t.Errorf("Parse error = %v, want the zero price reported", err)
+	}
+
+	_, err = Parse([]byte("version: 1\nitems:\n  - id: latte\n    prise: 4.50\n"), YAML)
+	if err == nil || err.Error() != `decoding catalog: unknown field "prise"` {
+		t.Errorf("Parse error = %v, want the unknown field reported", err)
+	}
+}
@@ -0,0 +1,83 @@
+// Package payment holds the rules for approving a payment, shared by the
+// walkthrough in 03_payment_processing.go and the POS terminal
+package payment
+
+import (
+	"fmt"
+
+	"switch-examples/catalog"
+)
+
+// Method is how a customer pays
+type Method string
+
+const (
+	Cash     Method = "cash"
+	Credit   Method = "credit"
+	Debit    Method = "debit"
+	Mobile   Method = "mobile"
+	GiftCard Method = "gift_card"
+)
+
+// Methods are the methods the shop accepts, in the order a till lists them
+var Methods = []Method{Cash, Credit, Debit, Mobile, GiftCard}
+
+var names = map[Method]string{
+	Cash:     "Cash",
+	Credit:   "Credit Card",
+	Debit:    "Debit Card",
+	Mobile:   "Mobile Pay",
+	GiftCard: "Gift Card",
+}
+
+// String is the name a customer sees
+func (m Method) String() string {
+	if name, ok := names[m]; ok {
+		return name
+	}
+	return string(m)
+}
+
+// CreditLimit is what a credit card payment must stay under
+const CreditLimit catalog.Cents = 50000
+
+// GiftCardBalance is what the demo gift card holds
+const GiftCardBalance catalog.Cents = 5000
+
+// Result is the outcome of one payment attempt
+type Result struct {
+	Method   Method
+	Amount   catalog.Cents
+	Approved bool
+	Message  string
+}
+
+// Process decides whether a payment goes through
+func Process(method Method, amount catalog.Cents) Result {
+	r := Result{Method: method, Amount: amount}
+
+	switch method {
+	case Cash:
+		r.Approved, r.Message = true, "Cash accepted"
+	case Credit:
+		if amount < CreditLimit {
+			r.Approved, r.Message = true, "Credit card approved"
+		} else {
+			r.Message = "Credit limit exceeded"
+		}
+	case Debit:
+		r.Approved, r.Message = true, "Debit card approved"
+	case Mobile:
+		r.Approved, r.Message = true, "Mobile payment successful"
+	case GiftCard:
+		if amount <= GiftCardBalance {
+			r.Approved = true
+			r.Message = fmt.Sprintf("Gift card accepted, %s left", GiftCardBalance-amount)
+		} else {
+			r.Message = fmt.Sprintf("Insufficient balance. Card has %s", GiftCardBalance)
+		}
+	default:
+		r.Message = "Payment method not accepted"
+	}
+	return r
+}
@@ -0,0 +1,45 @@
+package payment
+
+import (
+	"testing"
+
+	"switch-examples/catalog"
+)
+
+func TestProcess(t *testing.T) {
+	tests := []struct {
+		method   Method
+		amount   catalog.Cents
+		approved bool
+		message  string
+	}{
+		{Cash, 1550, true, "Cash accepted"},
+		{Credit, 2735, true, "Credit card approved"},
+		{Credit, 49999, true, "Credit card approved"},
+		{Credit, 50000, false, "Credit limit exceeded"},
+		{Debit, 875, true, "Debit card approved"},
+		{Mobile, 1200, true, "Mobile payment successful"},
+		{GiftCard, 4525, true, "Gift card accepted, $4.75 left"},
+		{GiftCard, 5000, true, "Gift card accepted, $0.00 left"},
+		{GiftCard, 5001, false, "Insufficient balance. Card has $50.00"},
+		{"bitcoin", 10000, false, "Payment method not accepted"},
+	}
+	for _, tt := range tests {
+		r := Process(tt.method, tt.amount)
+		if r.Approved != tt.approved || r.Message != tt.message {
+			t.Errorf("Process(%s, %s) = %t %q, want %t %q", tt.method, tt.amount, r.Approved, r.Message, tt.approved, tt.message)
+		}
+		if r.Method != tt.method || r.Amount != tt.amount {
+			t.Errorf("Process(%s, %s) recorded %s %s", tt.method, tt.amount, r.Method, r.Amount)
+		}
+	}
+}
+
+func TestMethodString(t *testing.T) {
+	if got := GiftCard.String(); got != "Gift Card" {
+		t.Errorf("GiftCard = %q, want Gift Card", got)
+	}
+	if got := Method("bitcoin").String(); got != "bitcoin" {
+		t.Errorf("unknown method = %q, want it unchanged", got)
+	}
+}
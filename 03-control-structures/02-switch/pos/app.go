@@ -0,0 +1,469 @@
+package pos
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"switch-examples/catalog"
+	"switch-examples/payment"
+)
+
+// State is the screen the App is showing
+type State int
+
+const (
+	StateMain State = iota
+	StateDrinks
+	StateFood
+	StateSize
+	StateCustomize
+	StateCart
+	StatePayment
+	StateReceipt
+	StateExit
+)
+
+// Width is how many columns the App draws
+const Width = 44
+
+// App is the POS screen. Run draws the current state, reads a key and
+// handles it until the user exits or the input ends.
+type App struct {
+	term  Terminal
+	menu  *catalog.Catalog
+	store string
+	clock func() time.Time
+
+	state   State
+	cursor  int
+	message string
+
+	cart     Cart
+	picked   catalog.MenuItem // Item waiting for a size
+	pickedIn State            // Menu the item was picked from
+	line     int              // Cart line being customized
+	returnTo State            // Where Customize goes when done
+
+	payment payment.Result
+	receipt []string
+}
+
+// NewApp builds a POS for one store. clock decides which menu windows are
+// open; pass time.Now in production and a fixed time in demos.
+func NewApp(term Terminal, menu *catalog.Catalog, store string, clock func() time.Time) *App {
+	return &App{term: term, menu: menu, store: store, clock: clock}
+}
+
+func (a *App) State() State { return a.state }
+func (a *App) Cart() *Cart  { return &a.cart }
+
+// Run loops until Exit or until the terminal has no more keys (io.EOF),
+// in which case it returns nil and can be called again to carry on
+func (a *App) Run() error {
+	for a.state != StateExit {
+		if err := a.term.Draw(a.View()); err != nil {
+			return err
+		}
+		event, err := a.term.ReadKey()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		a.Handle(event)
+	}
+	return a.term.Draw([]string{"Thank you for visiting GoCoffee! ☕"})
+}
+
+// === Options ===
+
+func (a *App) items() []catalog.MenuItem {
+	categories := []string{"coffee", "cold"}
+	if a.state == StateFood || (a.state == StateSize && a.pickedIn == StateFood) {
+		categories = []string{"bakery", "food"}
+	}
+
+	now := a.clock()
+	var items []catalog.MenuItem
+	for _, section := range a.menu.Menu(a.store, now, categories...) {
+		for _, item := range section.Items {
+			if a.menu.Status(item, a.store, now) == catalog.OnSale {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+func isDrink(item catalog.MenuItem) bool {
+	return item.Category == "coffee" || item.Category == "cold"
+}
+
+// options lists what the cursor can move over in the current state
+func (a *App) options() []string {
+	var options []string
+
+	switch a.state {
+	case StateMain:
+		options = []string{
+			"Drinks",
+			"Food",
+			fmt.Sprintf("Cart (%d)", a.cart.Count()),
+			"Checkout",
+			"Exit",
+		}
+
+	case StateDrinks, StateFood:
+		for _, item := range a.items() {
+			options = append(options, row(item.Name, "from "+item.BasePrice().String(), Width-4))
+		}
+		options = append(options, "← Back")
+
+	case StateSize:
+		for _, size := range a.picked.Sizes() {
+			options = append(options, row(size, a.picked.Prices[size].String(), Width-4))
+		}
+		options = append(options, "← Back")
+
+	case StateCustomize:
+		line := a.cart.Lines[a.line]
+		for _, custom := range Customizations {
+			mark := "[ ]"
+			for _, c := range line.Customizations {
+				if c.Name == custom.Name {
+					mark = "[x]"
+				}
+			}
+			price := ""
+			if custom.Price > 0 {
+				price = "+" + custom.Price.String()
+			}
+			options = append(options, row(mark+" "+custom.Name, price, Width-4))
+		}
+		options = append(options, "✓ Done")
+
+	case StateCart:
+		for _, line := range a.cart.Lines {
+			options = append(options, row(fmt.Sprintf("%d × %s", line.Quantity, line.Label()), line.Total().String(), Width-4))
+		}
+		options = append(options, "Checkout", "← Back")
+
+	case StatePayment:
+		for _, method := range payment.Methods {
+			options = append(options, method.String())
+		}
+		options = append(options, "← Back")
+	}
+	return options
+}
+
+// row puts left and right at the edges of width columns
+func row(left, right string, width int) string {
+	space := width - len([]rune(left)) - len([]rune(right))
+	if space < 1 {
+		cut := max(0, width-len([]rune(right))-2)
+		left = string([]rune(left)[:min(cut, len([]rune(left)))]) + "…"
+		space = 1
+	}
+	return left + strings.Repeat(" ", space) + right
+}
+
+// === Drawing ===
+
+// View renders the current state as lines of at most Width columns
+func (a *App) View() []string {
+	var title, hint string
+	switch a.state {
+	case StateMain:
+		title, hint = "🏠 MAIN MENU", "↑↓ move  ⏎ select  1-5 jump  ^C quit"
+	case StateDrinks:
+		title, hint = "☕ DRINKS", "↑↓ move  ⏎ add  esc back"
+	case StateFood:
+		title, hint = "🍰 FOOD", "↑↓ move  ⏎ add  esc back"
+	case StateSize:
+		title, hint = "📏 SIZE: "+a.picked.Name, "↑↓ move  ⏎ choose  esc back"
+	case StateCustomize:
+		title, hint = "✨ CUSTOMIZE: "+a.cart.Lines[a.line].Label(), "⏎ toggle  esc done"
+	case StateCart:
+		title, hint = "🛒 CART", "+/- qty  d remove  c customize  esc back"
+	case StatePayment:
+		title, hint = "💳 PAYMENT: "+a.cart.Total().String(), "⏎ pay  esc back"
+	case StateReceipt:
+		title, hint = "✅ PAID", "any key: next order"
+	}
+
+	name := "GoCoffee POS"
+	if r := []rune(a.store); len(r) > 0 {
+		name += " · " + strings.ToUpper(string(r[0])) + string(r[1:])
+	}
+	now := a.clock()
+	header := row(name, now.Format("Mon 3:04 PM"), Width)
+	lines := []string{header, strings.Repeat("═", Width), row(title, "", Width), ""}
+
+	if a.state == StateReceipt {
+		lines = append(lines, a.receipt...)
+	} else {
+		options := a.options()
+		for i, option := range options {
+			cursor := "  "
+			if i == min(a.cursor, len(options)-1) { // Where Handle will clamp it
+				cursor = "▶ "
+			}
+			lines = append(lines, fmt.Sprintf("%s%d %s", cursor, (i+1)%10, option))
+		}
+	}
+
+	if a.state == StateCart && len(a.cart.Lines) > 0 {
+		lines = append(lines, "",
+			row("  Subtotal", a.cart.Subtotal().String(), Width),
+			row("  Tax (8%)", a.cart.Tax().String(), Width),
+			row("  TOTAL", a.cart.Total().String(), Width))
+	}
+
+	lines = append(lines, "", a.message, strings.Repeat("─", Width),
+		row(hint, "", Width),
+		row(cartSummary(a.cart.Count()), a.cart.Total().String(), Width))
+	return lines
+}
+
+func cartSummary(count int) string {
+	if count == 1 {
+		return "Cart: 1 item"
+	}
+	return fmt.Sprintf("Cart: %d items", count)
+}
+
+// === Input ===
+
+// Handle applies one key to the current state
+func (a *App) Handle(event Event) {
+	a.message = ""
+
+	if event.Key == KeyCtrlC {
+		a.state = StateExit
+		return
+	}
+
+	if a.state == StateReceipt {
+		a.cart = Cart{}
+		a.goTo(StateMain)
+		return
+	}
+
+	// A menu window may have closed since the screen was drawn, leaving
+	// the cursor past the end of a shorter list
+	count := len(a.options())
+	a.cursor = min(a.cursor, count-1)
+
+	switch event.Key {
+	case KeyUp:
+		a.cursor = (a.cursor - 1 + count) % count
+	case KeyDown:
+		a.cursor = (a.cursor + 1) % count
+	case KeyEnter, KeyRight:
+		a.choose(a.cursor)
+	case KeyEscape, KeyLeft, KeyBackspace:
+		a.back()
+	case KeyDelete:
+		// Only the cart has lines to delete; elsewhere it would remove a
+		// line the screen isn't showing
+		if a.state == StateCart {
+			a.cartKey('d')
+		}
+	case KeyRune:
+		switch {
+		case event.Rune >= '1' && event.Rune <= '9' && int(event.Rune-'1') < count:
+			a.cursor = int(event.Rune - '1')
+			a.choose(a.cursor)
+		case event.Rune == '0' && count >= 10: // Option 10 is shown as 0
+			a.cursor = 9
+			a.choose(a.cursor)
+		case event.Rune == 'q' && a.state == StateMain:
+			a.state = StateExit
+		case a.state == StateCart:
+			a.cartKey(event.Rune)
+		}
+	}
+}
+
+func (a *App) goTo(state State) {
+	a.state = state
+	a.cursor = 0
+}
+
+func (a *App) back() {
+	switch a.state {
+	case StateDrinks, StateFood, StateCart:
+		a.goTo(StateMain)
+	case StateSize:
+		a.goTo(a.pickedIn)
+	case StateCustomize:
+		a.goTo(a.returnTo)
+	case StatePayment:
+		a.goTo(StateCart)
+	}
+}
+
+// choose selects option i of the current state
+func (a *App) choose(i int) {
+	switch a.state {
+	case StateMain:
+		switch i {
+		case 0:
+			a.goTo(StateDrinks)
+		case 1:
+			a.goTo(StateFood)
+		case 2:
+			a.goTo(StateCart)
+		case 3:
+			a.checkout()
+		case 4:
+			a.state = StateExit
+		}
+
+	case StateDrinks, StateFood:
+		items := a.items()
+		if i >= len(items) {
+			a.back()
+			return
+		}
+		a.picked, a.pickedIn = items[i], a.state
+		if len(a.picked.Prices) == 1 {
+			a.addPicked(a.picked.Sizes()[0])
+			return
+		}
+		a.goTo(StateSize)
+
+	case StateSize:
+		sizes := a.picked.Sizes()
+		if i == len(sizes) {
+			a.back()
+			return
+		}
+		a.addPicked(sizes[i])
+
+	case StateCustomize:
+		if i == len(Customizations) {
+			a.back()
+			return
+		}
+		a.cart.Toggle(a.line, Customizations[i])
+
+	case StateCart:
+		switch i {
+		case len(a.cart.Lines):
+			a.checkout()
+		case len(a.cart.Lines) + 1:
+			a.back()
+		default:
+			a.message = "+/- to change quantity, d to remove"
+		}
+
+	case StatePayment:
+		if i == len(payment.Methods) {
+			a.back()
+			return
+		}
+		a.pay(payment.Methods[i])
+	}
+}
+
+// addPicked puts the picked item in the cart. Drinks go straight to
+// customization for their own line, as customizeDrink did.
+func (a *App) addPicked(size string) {
+	price, err := a.menu.Price(a.store, a.clock(), a.picked.ID, size)
+	if err != nil {
+		a.message = "✗ " + err.Error()
+		a.goTo(a.pickedIn)
+		return
+	}
+
+	line := Line{
+		ItemID:    a.picked.ID,
+		Name:      a.picked.Name,
+		Size:      size,
+		Drink:     isDrink(a.picked),
+		Quantity:  1,
+		UnitPrice: price,
+	}
+
+	if !line.Drink {
+		a.cart.Add(line)
+		a.message = fmt.Sprintf("✓ Added %s (%s)", line.Label(), price)
+		a.goTo(a.pickedIn)
+		return
+	}
+
+	// A new drink gets its own line so its customizations stay with it
+	a.cart.Lines = append(a.cart.Lines, line)
+	a.line, a.returnTo = len(a.cart.Lines)-1, a.pickedIn
+	a.message = fmt.Sprintf("✓ Added %s (%s)", line.Label(), price)
+	a.goTo(StateCustomize)
+}
+
+// cartKey handles the cart's editing keys on the line under the cursor
+func (a *App) cartKey(key rune) {
+	if a.cursor >= len(a.cart.Lines) {
+		return
+	}
+	line := a.cart.Lines[a.cursor]
+
+	switch key {
+	case '+', '=':
+		a.cart.SetQuantity(a.cursor, line.Quantity+1)
+	case '-':
+		a.cart.SetQuantity(a.cursor, line.Quantity-1)
+		if line.Quantity == 1 {
+			a.message = "✓ Removed " + line.Label()
+		}
+	case 'd', 'x':
+		a.cart.Remove(a.cursor)
+		a.message = "✓ Removed " + line.Label()
+	case 'c':
+		if !line.Drink {
+			a.message = line.Name + " can't be customized"
+			return
+		}
+		a.line, a.returnTo = a.cursor, StateCart
+		a.goTo(StateCustomize)
+		return
+	}
+
+	// A line may have gone; keep the cursor and the customized line on
+	// lines that still exist
+	if a.cursor > len(a.cart.Lines) {
+		a.cursor = len(a.cart.Lines)
+	}
+	if a.line >= len(a.cart.Lines) {
+		a.line = max(0, len(a.cart.Lines)-1)
+	}
+}
+
+func (a *App) checkout() {
+	if len(a.cart.Lines) == 0 {
+		a.message = "Your cart is empty!"
+		return
+	}
+	a.goTo(StatePayment)
+}
+
+func (a *App) pay(method payment.Method) {
+	a.payment = payment.Process(method, a.cart.Total())
+	if !a.payment.Approved {
+		a.message = "❌ " + a.payment.Message
+		return
+	}
+
+	a.receipt = Receipt(a.cart, a.payment, Width-4)
+	for i := range a.receipt {
+		a.receipt[i] = "  " + a.receipt[i]
+	}
+	a.message = "✅ " + a.payment.Message
+	a.state = StateReceipt
+}
+
+// Payment returns the last payment attempt
+func (a *App) Payment() payment.Result { return a.payment }
@@ -0,0 +1,256 @@
+package pos
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"switch-examples/catalog"
+	"switch-examples/payment"
+)
+
+// newTestApp opens the Seattle POS on Monday 8:30, inside the breakfast
+// window, like the scripted demo
+func newTestApp(t *testing.T) (*App, *FakeTerminal) {
+	t.Helper()
+	menu, err := catalog.Load("../data/menu.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	monday := time.Date(2024, time.March, 11, 8, 30, 0, 0, time.Local)
+	term := &FakeTerminal{}
+	return NewApp(term, menu, "seattle", func() time.Time { return monday }), term
+}
+
+// press feeds keys and runs until they are used up. Run draws View after
+// every key, so a screen that can't be drawn fails here.
+func press(t *testing.T, app *App, term *FakeTerminal, script string) {
+	t.Helper()
+	term.Feed(script)
+	if err := app.Run(); err != nil {
+		t.Fatalf("keys %q: %v", script, err)
+	}
+}
+
+// withLatteAndCroissant leaves a large latte (extra shot, vanilla) and a
+// croissant in the cart and the App on the main menu
+func withLatteAndCroissant(t *testing.T) (*App, *FakeTerminal) {
+	app, term := newTestApp(t)
+	press(t, app, term, "1 down down enter down down enter enter down down enter") // Latte, Large, customize
+	press(t, app, term, "esc esc 2 enter esc")                                     // Croissant
+	if got := len(app.Cart().Lines); got != 2 {
+		t.Fatalf("setup: cart has %d lines, want 2", got)
+	}
+	if app.State() != StateMain {
+		t.Fatalf("setup: state = %d, want main menu", app.State())
+	}
+	return app, term
+}
+
+func TestAddEditDeletePay(t *testing.T) {
+	app, term := withLatteAndCroissant(t)
+
+	latte := app.Cart().Lines[0]
+	if latte.Name != "Latte" || latte.Size != "Large" || latte.UnitPrice != 600 {
+		t.Errorf("line 1 = %s %s at %s, want Large Latte at $6.00", latte.Size, latte.Name, latte.UnitPrice)
+	}
+	if len(latte.Customizations) != 2 || latte.Each() != 725 {
+		t.Errorf("latte customizations = %v, each %s; want extra shot and vanilla at $7.25", latte.Customizations, latte.Each())
+	}
+
+	// Edit: seven more lattes, then take off the extra shot
+	press(t, app, term, "3 +*7")
+	if got := app.Cart().Lines[0].Quantity; got != 8 {
+		t.Errorf("latte quantity = %d, want 8", got)
+	}
+	press(t, app, term, "c enter esc")
+	if got := app.Cart().Lines[0].Each(); got != 675 {
+		t.Errorf("latte each after removing the extra shot = %s, want $6.75", got)
+	}
+	if app.State() != StateCart {
+		t.Errorf("customizing from the cart returned to state %d, want the cart", app.State())
+	}
+
+	// Delete: the croissant goes
+	press(t, app, term, "down del")
+	if lines := app.Cart().Lines; len(lines) != 1 || lines[0].Name != "Latte" {
+		t.Fatalf("after deleting the croissant the cart is %v", lines)
+	}
+	if !strings.Contains(term.Screen(), "✓ Removed Croissant") {
+		t.Errorf("screen doesn't confirm the removal:\n%s", term.Screen())
+	}
+
+	// Pay: over $50 the gift card is declined, cash goes through
+	total := app.Cart().Total()
+	press(t, app, term, "esc 4 5")
+	if app.State() != StatePayment || app.Payment().Approved {
+		t.Errorf("gift card for %s: state %d, approved %t; want declined", total, app.State(), app.Payment().Approved)
+	}
+	press(t, app, term, "1")
+	if app.State() != StateReceipt || !app.Payment().Approved || app.Payment().Amount != total {
+		t.Errorf("cash: state %d, payment %+v; want an approved %s", app.State(), app.Payment(), total)
+	}
+
+	// Any key starts the next order with an empty cart
+	press(t, app, term, "enter")
+	if app.State() != StateMain || len(app.Cart().Lines) != 0 {
+		t.Errorf("next order: state %d with %d lines, want an empty main menu", app.State(), len(app.Cart().Lines))
+	}
+}
+
+// Delete edits the cart only on the cart screen. Anywhere else it used to
+// remove whatever line the invisible cart cursor was on.
+func TestDeleteOnEveryScreen(t *testing.T) {
+	tests := []struct {
+		screen string
+		keys   string // From the main menu
+		state  State
+	}{
+		{"main", "", StateMain},
+		{"drinks", "1", StateDrinks},
+		{"food", "2", StateFood},
+		{"size", "1 down down enter", StateSize},
+		{"customize", "1 down down enter enter", StateCustomize},
+		{"customize from cart", "3 c", StateCustomize},
+		{"payment", "4", StatePayment},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.screen, func(t *testing.T) {
+			app, term := withLatteAndCroissant(t)
+			press(t, app, term, tt.keys)
+			if app.State() != tt.state {
+				t.Fatalf("keys %q reached state %d, want %d", tt.keys, app.State(), tt.state)
+			}
+			before := len(app.Cart().Lines)
+
+			press(t, app, term, "del del del")
+			if got := len(app.Cart().Lines); got != before {
+				t.Errorf("delete on the %s screen changed the cart from %d to %d lines", tt.screen, before, got)
+			}
+			if app.State() != tt.state {
+				t.Errorf("delete moved from state %d to %d", tt.state, app.State())
+			}
+		})
+	}
+
+	t.Run("receipt", func(t *testing.T) {
+		app, term := withLatteAndCroissant(t)
+		press(t, app, term, "4 1")
+		if app.State() != StateReceipt {
+			t.Fatalf("state = %d, want receipt", app.State())
+		}
+		press(t, app, term, "del") // Any key: next order
+		if app.State() != StateMain || len(app.Cart().Lines) != 0 {
+			t.Errorf("state %d with %d lines, want an empty main menu", app.State(), len(app.Cart().Lines))
+		}
+	})
+
+	t.Run("cart", func(t *testing.T) {
+		app, term := withLatteAndCroissant(t)
+		press(t, app, term, "3 del del del") // Two lines, then Checkout is under the cursor
+		if got := len(app.Cart().Lines); got != 0 {
+			t.Errorf("cart has %d lines after deleting both, want 0", got)
+		}
+		if app.State() != StateCart {
+			t.Errorf("state = %d, want the cart", app.State())
+		}
+	})
+}
+
+// Removing the last line must not leave the customize screen pointing
+// past the end of the cart
+func TestRemovingLinesKeepsCustomizeInRange(t *testing.T) {
+	app, term := withLatteAndCroissant(t)
+	press(t, app, term, "1 down down enter enter esc esc") // Second latte, line 3
+	press(t, app, term, "3 down down c esc")               // Customize it from the cart
+	if app.line != 2 {
+		t.Fatalf("customizing line %d, want 2", app.line)
+	}
+
+	press(t, app, term, "down down del")
+	if app.line >= len(app.Cart().Lines) {
+		t.Fatalf("line %d is past the %d-line cart", app.line, len(app.Cart().Lines))
+	}
+	press(t, app, term, "up up c enter esc") // Customize and draw what's left
+	if app.State() != StateCart {
+		t.Errorf("state = %d, want the cart", app.State())
+	}
+
+	press(t, app, term, "up*4 - - d")
+	if len(app.Cart().Lines) != 0 || app.line != 0 {
+		t.Errorf("emptied cart: %d lines, line %d", len(app.Cart().Lines), app.line)
+	}
+}
+
+// The tenth option is numbered 0 on screen, so 0 must pick it
+func TestZeroPicksTenthOption(t *testing.T) {
+	app, term := newTestApp(t)
+	for i := 0; i < 8; i++ {
+		app.cart.Lines = append(app.cart.Lines, Line{Name: "Cookie", Quantity: 1, UnitPrice: 250})
+	}
+	press(t, app, term, "3")
+	if !strings.Contains(term.Screen(), "0 ← Back") {
+		t.Fatalf("option 10 isn't numbered 0:\n%s", term.Screen())
+	}
+
+	press(t, app, term, "0")
+	if app.State() != StateMain {
+		t.Errorf("0 on the cart went to state %d, want main menu (← Back)", app.State())
+	}
+}
+
+// A store with no name still gets a header
+func TestEmptyStoreName(t *testing.T) {
+	app, term := newTestApp(t)
+	app.store = ""
+	press(t, app, term, "1")
+	if header := strings.SplitN(term.Screen(), "\n", 2)[0]; !strings.HasPrefix(header, "GoCoffee POS ") || strings.Contains(header, "·") {
+		t.Errorf("header = %q, want GoCoffee POS without a store", header)
+	}
+}
+
+// The lunch items close at 20:00 while the food screen is open. The
+// cursor was past the end of the shorter list and picking it panicked.
+func TestCursorClampedWhenWindowCloses(t *testing.T) {
+	app, term := newTestApp(t)
+	now := time.Date(2024, time.March, 11, 19, 59, 0, 0, time.Local)
+	app.clock = func() time.Time { return now }
+
+	press(t, app, term, "2 up") // ← Back, below Sandwich and Salad
+	before := len(app.options())
+
+	now = now.Add(time.Minute)
+	if after := len(app.options()); after >= before {
+		t.Fatalf("food list went from %d to %d options, want it shorter", before, after)
+	}
+	press(t, app, term, "enter")
+	if app.State() != StateMain {
+		t.Errorf("enter on the clamped cursor went to state %d, want main menu (← Back)", app.State())
+	}
+
+	// A stale index straight into choose is ← Back too
+	app.goTo(StateFood)
+	app.choose(before - 1)
+	if app.State() != StateMain {
+		t.Errorf("choose(%d) went to state %d, want main menu", before-1, app.State())
+	}
+}
+
+// The screen pays through the payment package, not a copy of its rules
+func TestPayUsesPaymentRules(t *testing.T) {
+	for i, method := range payment.Methods {
+		app, term := withLatteAndCroissant(t)
+		total := app.Cart().Total()
+		press(t, app, term, fmt.Sprintf("4 %d", i+1))
+
+		want := payment.Process(method, total)
+		if got := app.Payment(); got != want {
+			t.Errorf("%s: payment = %+v, want %+v", method, got, want)
+		}
+		if !strings.Contains(term.Screen(), want.Message) {
+			t.Errorf("%s: screen doesn't show %q:\n%s", method, want.Message, term.Screen())
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package pos
+
+import (
+	"fmt"
+	"strings"
+
+	"switch-examples/catalog"
+	"switch-examples/payment"
+)
+
+// Customization is an extra on one cart line, like the choices in
+// customizeDrink
+type Customization struct {
+	Name  string
+	Price catalog.Cents
+}
+
+// Customizations offered for drinks
+var Customizations = []Customization{
+	{"Extra Shot", 50},
+	{"Decaf", 0},
+	{"Vanilla Syrup", 75},
+	{"Oat Milk", 60},
+}
+
+// Line is one cart line. Unlike the old Cart's parallel items/prices
+// slices, the quantity and customizations belong to the line itself.
+type Line struct {
+	ItemID         string
+	Name           string
+	Size           string
+	Drink          bool
+	Quantity       int
+	UnitPrice      catalog.Cents
+	Customizations []Customization
+}
+
+// Each is the price of one, customizations included
+func (l Line) Each() catalog.Cents {
+	price := l.UnitPrice
+	for _, c := range l.Customizations {
+		price += c.Price
+	}
+	return price
+}
+
+func (l Line) Total() catalog.Cents {
+	return l.Each() * catalog.Cents(l.Quantity)
+}
+
+// Label is the line's name with its size and customizations
+func (l Line) Label() string {
+	label := l.Name
+	if l.Size != "" && l.Size != "Regular" {
+		label = l.Size + " " + label
+	}
+	for _, c := range l.Customizations {
+		label += " + " + c.Name
+	}
+	return label
+}
+
+type Cart struct {
+	Lines []Line
+}
+
+// Add puts a line in the cart, merging it with an identical line
+func (c *Cart) Add(line Line) int {
+	for i, existing := range c.Lines {
+		if existing.Label() == line.Label() {
+			c.Lines[i].Quantity += line.Quantity
+			return i
+		}
+	}
+	c.Lines = append(c.Lines, line)
+	return len(c.Lines) - 1
+}
+
+func (c *Cart) Remove(i int) {
+	if i >= 0 && i < len(c.Lines) {
+		c.Lines = append(c.Lines[:i], c.Lines[i+1:]...)
+	}
+}
+
+// SetQuantity changes a line's quantity; zero or less removes it
+func (c *Cart) SetQuantity(i, quantity int) {
+	if i < 0 || i >= len(c.Lines) {
+		return
+	}
+	if quantity <= 0 {
+		c.Remove(i)
+		return
+	}
+	c.Lines[i].Quantity = quantity
+}
+
+// Toggle adds a customization to a line, or removes it if already there
+func (c *Cart) Toggle(i int, custom Customization) {
+	if i < 0 || i >= len(c.Lines) {
+		return
+	}
+	line := &c.Lines[i]
+	for j, existing := range line.Customizations {
+		if existing.Name == custom.Name {
+			line.Customizations = append(line.Customizations[:j], line.Customizations[j+1:]...)
+			return
+		}
+	}
+	line.Customizations = append(line.Customizations, custom)
+}
+
+func (c *Cart) Count() int {
+	count := 0
+	for _, line := range c.Lines {
+		count += line.Quantity
+	}
+	return count
+}
+
+func (c *Cart) Subtotal() catalog.Cents {
+	var subtotal catalog.Cents
+	for _, line := range c.Lines {
+		subtotal += line.Total()
+	}
+	return subtotal
+}
+
+// Tax is 8%, as in showCheckout, rounded to the nearest cent
+func (c *Cart) Tax() catalog.Cents {
+	return (c.Subtotal()*8 + 50) / 100
+}
+
+func (c *Cart) Total() catalog.Cents {
+	return c.Subtotal() + c.Tax()
+}
+
+// Receipt formats a paid cart
+func Receipt(cart Cart, p payment.Result, width int) []string {
+	line := func(left string, right catalog.Cents) string {
+		r := right.String()
+		pad := width - len([]rune(left)) - len(r)
+		if pad < 1 {
+			left = string([]rune(left)[:max(0, width-len(r)-2)]) + "…"
+			pad = 1
+		}
+		return left + strings.Repeat(" ", pad) + r
+	}
+
+	lines := []string{"--- RECEIPT ---"}
+	for _, l := range cart.Lines {
+		lines = append(lines, line(fmt.Sprintf("%d × %s", l.Quantity, l.Label()), l.Total()))
+	}
+	lines = append(lines,
+		strings.Repeat("-", width),
+		line("Subtotal", cart.Subtotal()),
+		line("Tax (8%)", cart.Tax()),
+		line("TOTAL", cart.Total()),
+		"Paid by "+p.Method.String(),
+	)
+	return lines
+}
@@ -0,0 +1,82 @@
+package pos
+
+import (
+	"io"
+	"strings"
+)
+
+// FakeTerminal replays scripted keys and records every frame. When the
+// keys run out ReadKey returns io.EOF, App.Run returns, and more keys can
+// be fed to continue from the same screen.
+type FakeTerminal struct {
+	keys   []Event
+	Frames [][]string
+}
+
+// Feed queues more keys, written as in Keys
+func (t *FakeTerminal) Feed(script string) {
+	t.keys = append(t.keys, Keys(script)...)
+}
+
+func (t *FakeTerminal) ReadKey() (Event, error) {
+	if len(t.keys) == 0 {
+		return Event{}, io.EOF
+	}
+	key := t.keys[0]
+	t.keys = t.keys[1:]
+	return key, nil
+}
+
+func (t *FakeTerminal) Draw(lines []string) error {
+	t.Frames = append(t.Frames, append([]string(nil), lines...))
+	return nil
+}
+
+// Screen returns the last frame drawn
+func (t *FakeTerminal) Screen() string {
+	if len(t.Frames) == 0 {
+		return ""
+	}
+	return strings.Join(t.Frames[len(t.Frames)-1], "\n")
+}
+
+var keyNames = map[string]Key{
+	"up": KeyUp, "down": KeyDown, "left": KeyLeft, "right": KeyRight,
+	"enter": KeyEnter, "esc": KeyEscape, "bksp": KeyBackspace, "del": KeyDelete,
+	"ctrl-c": KeyCtrlC,
+}
+
+// Keys turns a script such as "down down enter + + esc" into key events.
+// Words are key names (up, down, left, right, enter, esc, bksp, del,
+// ctrl-c); anything else is typed one character at a time. A word can be
+// repeated with *N, e.g. "down*3".
+func Keys(script string) []Event {
+	var events []Event
+	for _, word := range strings.Fields(script) {
+		repeat := 1
+		if name, count, ok := strings.Cut(word, "*"); ok && name != "" {
+			n := 0
+			for _, d := range count {
+				if d < '0' || d > '9' {
+					n = -1
+					break
+				}
+				n = n*10 + int(d-'0')
+			}
+			if n > 0 {
+				word, repeat = name, n
+			}
+		}
+
+		for i := 0; i < repeat; i++ {
+			if key, ok := keyNames[word]; ok {
+				events = append(events, Event{Key: key})
+				continue
+			}
+			for _, r := range word {
+				events = append(events, Event{Key: KeyRune, Rune: r})
+			}
+		}
+	}
+	return events
+}
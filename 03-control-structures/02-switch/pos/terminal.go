@@ -0,0 +1,147 @@
+// Package pos is a keyboard-driven point-of-sale screen built on the
+// state loop from 09_menu_system.go: a state, a switch that draws it and a
+// switch that handles the next key.
+//
+// The App only talks to a Terminal, so it runs the same against a real
+// terminal in raw mode (ANSITerminal) or a FakeTerminal that replays
+// scripted keys and keeps every frame it was asked to draw.
+package pos
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Key is a key press the App understands
+type Key int
+
+const (
+	KeyRune Key = iota // A printable character, in Event.Rune
+	KeyUp
+	KeyDown
+	KeyLeft
+	KeyRight
+	KeyEnter
+	KeyEscape
+	KeyBackspace
+	KeyDelete
+	KeyCtrlC
+)
+
+// Event is one key press
+type Event struct {
+	Key  Key
+	Rune rune
+}
+
+// Terminal is what the App needs from a screen and keyboard
+type Terminal interface {
+	// ReadKey blocks for the next key. io.EOF means no more input.
+	ReadKey() (Event, error)
+
+	// Draw replaces the whole screen with lines
+	Draw(lines []string) error
+}
+
+// ReadEvent decodes one key from raw terminal input: plain bytes and
+// runes, Enter, Backspace, Ctrl-C, Ctrl-D (as io.EOF) and the
+// ESC [ A ... escape sequences arrow keys send.
+func ReadEvent(r *bufio.Reader) (Event, error) {
+	ch, _, err := r.ReadRune()
+	if err != nil {
+		return Event{}, err
+	}
+
+	switch ch {
+	case '\r', '\n':
+		return Event{Key: KeyEnter}, nil
+	case 127, '\b':
+		return Event{Key: KeyBackspace}, nil
+	case 3:
+		return Event{Key: KeyCtrlC}, nil
+	case 4:
+		return Event{}, io.EOF
+	case 27:
+		// A lone ESC, or the start of an escape sequence already waiting
+		// in the buffer
+		if r.Buffered() == 0 {
+			return Event{Key: KeyEscape}, nil
+		}
+		next, _, _ := r.ReadRune()
+		if next != '[' && next != 'O' {
+			return Event{Key: KeyEscape}, nil
+		}
+		code, _, _ := r.ReadRune()
+		switch code {
+		case 'A':
+			return Event{Key: KeyUp}, nil
+		case 'B':
+			return Event{Key: KeyDown}, nil
+		case 'C':
+			return Event{Key: KeyRight}, nil
+		case 'D':
+			return Event{Key: KeyLeft}, nil
+		case '3':
+			r.ReadRune() // The '~' of ESC [ 3 ~
+			return Event{Key: KeyDelete}, nil
+		}
+		return Event{Key: KeyEscape}, nil
+	}
+	return Event{Key: KeyRune, Rune: ch}, nil
+}
+
+// ANSITerminal drives a real terminal. OpenTerminal puts it in raw mode
+// with stty, so keys arrive one at a time without echo; Close restores it.
+type ANSITerminal struct {
+	in    *bufio.Reader
+	out   io.Writer
+	saved string // stty settings to restore
+}
+
+// OpenTerminal switches stdin to raw mode. It fails when stdin isn't a
+// terminal or stty isn't available (e.g. on Windows).
+func OpenTerminal() (*ANSITerminal, error) {
+	saved, err := stty("-g")
+	if err != nil {
+		return nil, fmt.Errorf("stdin is not a terminal: %w", err)
+	}
+	if _, err := stty("raw", "-echo"); err != nil {
+		return nil, err
+	}
+
+	fmt.Print("\033[?25l") // Hide the cursor
+	return &ANSITerminal{
+		in:    bufio.NewReader(os.Stdin),
+		out:   os.Stdout,
+		saved: strings.TrimSpace(saved),
+	}, nil
+}
+
+func stty(args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+func (t *ANSITerminal) ReadKey() (Event, error) {
+	return ReadEvent(t.in)
+}
+
+// Draw clears the screen and writes every line. Raw mode doesn't turn
+// "\n" into a carriage return, so lines end in "\r\n".
+func (t *ANSITerminal) Draw(lines []string) error {
+	_, err := io.WriteString(t.out, "\033[H\033[2J"+strings.Join(lines, "\r\n")+"\r\n")
+	return err
+}
+
+// Close restores the terminal settings saved by OpenTerminal
+func (t *ANSITerminal) Close() error {
+	fmt.Print("\033[?25h\033[H\033[2J") // Show the cursor, clear
+	_, err := stty(t.saved)
+	return err
+}
@@ -122,7 +122,7 @@ ShopHours:
 				
 				if vipAhead {
 					fmt.Print(" - Yielding to VIP customer")
-					customerIndex-- // Put customer back
+					customerIndex-- // Put customer back (can starve them; see 10_priority_queue.go)
 					continue HourlyService
 				}
 			}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"break-continue-examples/barqueue"
+)
+
+// 09_coffee_shop_simulation.go lets VIPs cut in by peeking three customers
+// ahead and doing customerIndex-- to "yield", which can make a regular
+// wait forever. The barqueue package (in ./barqueue) is a real scheduler:
+// VIPs and mobile orders get a head start, everyone ages at the same rate,
+// and drinks with the same milk are steamed together.
+
+var opening = time.Date(2024, time.March, 11, 8, 0, 0, 0, time.UTC)
+
+func at(minutes float64) time.Time {
+	return opening.Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+func label(o barqueue.Order) string {
+	drink := o.Drink
+	if o.Milk != "" {
+		drink += " (" + o.Milk + ")"
+	}
+	return fmt.Sprintf("%s: %s [%s]", o.Customer, drink, o.Class)
+}
+
+func morningOrders() []barqueue.Order {
+	return []barqueue.Order{
+		{ID: "1", Customer: "Alice", Class: barqueue.WalkIn, Drink: "Latte", Milk: "oat", Placed: at(0)},
+		{ID: "2", Customer: "Bob", Class: barqueue.VIP, Drink: "Espresso", Placed: at(1)},
+		{ID: "3", Customer: "Carol", Class: barqueue.WalkIn, Drink: "Cappuccino", Milk: "whole", Placed: at(1.5)},
+		{ID: "4", Customer: "Dan", Class: barqueue.Mobile, Drink: "Latte", Milk: "oat", Placed: at(0), PickupAt: at(14)},
+		{ID: "5", Customer: "Eve", Class: barqueue.WalkIn, Drink: "Flat White", Milk: "oat", Placed: at(2)},
+		{ID: "6", Customer: "Frank", Class: barqueue.VIP, Drink: "Mocha", Milk: "whole", Placed: at(3)},
+		{ID: "7", Customer: "Grace", Class: barqueue.WalkIn, Drink: "Americano", Placed: at(3.5)},
+		{ID: "8", Customer: "Heidi", Class: barqueue.WalkIn, Drink: "Latte", Milk: "oat", Placed: at(4)},
+	}
+}
+
+// serve runs one barista, who starts at start, over the orders. A batch
+// is steamed together and takes two minutes; new orders are added as
+// their Placed time comes. It returns when each order was started, by ID,
+// and how often the milk on the steamer was changed.
+func serve(cfg barqueue.Config, orders []barqueue.Order, start time.Time, show bool) (map[string]time.Time, int) {
+	q := barqueue.New(cfg)
+	started := map[string]time.Time{}
+	switches, lastMilk := 0, ""
+
+	now, next := start, 0
+	for next < len(orders) || q.Len() > 0 {
+		for next < len(orders) && !orders[next].Placed.After(now) {
+			if err := q.Add(orders[next]); err != nil {
+				fmt.Printf("  ❌ %v\n", err)
+			}
+			next++
+		}
+
+		batch := q.Next(now)
+		if batch == nil {
+			now = now.Add(30 * time.Second) // Nothing ready: wait for the next customer
+			continue
+		}
+
+		if milk := batch[0].Milk; milk != "" && milk != lastMilk {
+			switches++
+			lastMilk = milk
+		}
+		var names []string
+		for _, o := range batch {
+			started[o.ID] = now
+			names = append(names, label(o))
+		}
+		if show {
+			fmt.Printf("  %s  %s\n", now.Format("15:04:05"), strings.Join(names, "\n            + "))
+		}
+		now = now.Add(2 * time.Minute)
+	}
+	return started, switches
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Barista Queue ===")
+	fmt.Println()
+
+	cfg := barqueue.DefaultConfig()
+	fmt.Printf("Head starts: VIP %v, mobile %v; mobile orders start %v before pickup\n",
+		cfg.HeadStart[barqueue.VIP], cfg.HeadStart[barqueue.Mobile], cfg.MobileLead)
+	fmt.Printf("Batches of up to %d drinks with the same milk, from %v down the line\n\n", cfg.BatchSize, cfg.BatchWindow)
+
+	// 1. The line at 8:04, before anything is made
+	q := barqueue.New(cfg)
+	for _, o := range morningOrders() {
+		q.Add(o)
+	}
+	fmt.Println("1. The line at 08:04 (Dan's mobile order is held until 08:10)")
+	for i, o := range q.Line(at(4)) {
+		fmt.Printf("  %d. %s\n", i+1, label(o))
+	}
+	fmt.Printf("  %d orders queued, %d in line\n", q.Len(), len(q.Line(at(4))))
+
+	// 2. One barista works through the morning
+	fmt.Println("\n2. The barista starts at 08:04, two minutes a batch")
+	serve(cfg, morningOrders(), at(4), true)
+
+	// 3. Batching saves steamer changes
+	unbatched := cfg
+	unbatched.BatchSize = 1
+	_, without := serve(unbatched, morningOrders(), at(4), false)
+	_, with := serve(cfg, morningOrders(), at(4), false)
+	fmt.Printf("\n3. Milk changes on the steamer: %d one at a time, %d with batching\n", without, with)
+
+	// 4. Aging: a VIP every minute must not starve the walk-in
+	walkIn := barqueue.Order{ID: "walk-in", Customer: "Ivan", Class: barqueue.WalkIn, Drink: "Americano", Placed: at(0.5)}
+	var flood []barqueue.Order
+	for i := 0; i < 30; i++ {
+		flood = append(flood, barqueue.Order{
+			ID:       fmt.Sprintf("vip-%d", i),
+			Customer: fmt.Sprintf("VIP %d", i+1),
+			Class:    barqueue.VIP,
+			Drink:    "Espresso",
+			Placed:   at(float64(i)),
+		})
+		if i == 0 {
+			flood = append(flood, walkIn)
+		}
+	}
+
+	strict := cfg
+	strict.HeadStart = map[barqueue.Class]time.Duration{barqueue.VIP: 24 * time.Hour}
+	strictStart, _ := serve(strict, flood, at(0), false)
+	agedStart, _ := serve(cfg, flood, at(0), false)
+
+	fmt.Println("\n4. A VIP every minute for 30 minutes, two minutes a drink, one walk-in at 08:00:30")
+	fmt.Printf("  Strict priority: walk-in started at %s\n", strictStart["walk-in"].Format("15:04"))
+	fmt.Printf("  With aging:      walk-in started at %s\n", agedStart["walk-in"].Format("15:04"))
+
+	// Only VIPs who ordered within the head start may go first
+	overtaken, unfair := 0, 0
+	for _, o := range flood {
+		if o.Class != barqueue.VIP || !agedStart[o.ID].Before(agedStart["walk-in"]) || !o.Placed.After(walkIn.Placed) {
+			continue
+		}
+		overtaken++
+		if o.Placed.Sub(walkIn.Placed) >= cfg.HeadStart[barqueue.VIP] {
+			unfair++
+		}
+	}
+	if unfair == 0 {
+		fmt.Printf("  ✅ Overtaken by %d VIPs, all within %v of the walk-in\n", overtaken, cfg.HeadStart[barqueue.VIP])
+	} else {
+		fmt.Printf("  ❌ Overtaken by %d VIPs who ordered too late to go first\n", unfair)
+	}
+
+	// 5. Mistakes are errors, and orders can be cancelled
+	fmt.Println("\n5. Errors and cancelling")
+	q = barqueue.New(cfg)
+	orders := morningOrders()
+	for _, o := range orders {
+		q.Add(o)
+	}
+	err := q.Add(orders[0])
+	fmt.Printf("  Same order twice: %v (ErrDuplicate: %t)\n", err, errors.Is(err, barqueue.ErrDuplicate))
+	err = q.Add(barqueue.Order{ID: "9", Customer: "Judy", Class: barqueue.Mobile, Drink: "Latte", Placed: at(5)})
+	fmt.Printf("  Mobile without a pickup time: %v\n", err)
+	fmt.Printf("  Cancel Dan's held order: %t, cancel it again: %t\n", q.Cancel("4"), q.Cancel("4"))
+	fmt.Printf("  Cancel Bob's order: %t, %d left\n", q.Cancel("2"), q.Len())
+}
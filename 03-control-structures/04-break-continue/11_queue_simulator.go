@@ -0,0 +1,127 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"break-continue-examples/shopsim"
+)
+
+// 09_coffee_shop_simulation.go walks the day an hour at a time, mutates
+// patience, staffEnergy, beans and milk inline, and seeds rand with the
+// clock, so no two runs can be compared. The shopsim package (in
+// ./shopsim) is a discrete-event simulator instead: a seeded day of
+// customers is generated once, then replayed against each staffing plan.
+// Same seed, same customers, so the only difference is the plan.
+
+// GoCoffee opens 06:00-20:00, like the old simulation
+const opens = 6
+
+var demand = shopsim.Demand{
+	//         6   7   8   9  10  11  12  13  14  15  16  17  18  19
+	PerHour:  []float64{20, 45, 60, 40, 25, 35, 50, 40, 20, 20, 25, 30, 20, 10},
+	VIPShare: 0.15,
+	Patience: 10 * time.Minute,
+	Spread:   0.3,
+}
+
+type plan struct {
+	name  string
+	staff shopsim.Staffing
+}
+
+var plans = []plan{
+	{"Two all day", shopsim.Staffing{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2}},
+	{"Three all day", shopsim.Staffing{3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3}},
+	{"Follow the rush", shopsim.Staffing{2, 3, 4, 3, 2, 3, 4, 3, 2, 2, 2, 2, 2, 1}},
+	{"Rush, four from 7", shopsim.Staffing{2, 4, 4, 3, 2, 3, 4, 3, 2, 2, 2, 2, 2, 1}},
+}
+
+// summary averages a plan over many simulated days
+type summary struct {
+	p50, p90, p99 time.Duration
+	abandon       float64
+	utilization   float64
+	overtime      time.Duration
+}
+
+func replicate(staff shopsim.Staffing, days int) summary {
+	var s summary
+	var p50s, p90s, p99s []time.Duration
+	for seed := int64(1); seed <= int64(days); seed++ {
+		r := shopsim.Simulate(shopsim.Generate(demand, seed), staff, shopsim.Queue())
+		p50s = append(p50s, r.Percentile(50))
+		p90s = append(p90s, r.Percentile(90))
+		p99s = append(p99s, r.Percentile(99))
+		s.abandon += r.AbandonRate() / float64(days)
+		s.utilization += r.Utilization() / float64(days)
+		s.overtime += r.Overtime / time.Duration(days)
+	}
+	s.p50, s.p90, s.p99 = median(p50s), median(p90s), median(p99s)
+	return s
+}
+
+func median(d []time.Duration) time.Duration {
+	sort.Slice(d, func(i, j int) bool { return d[i] < d[j] })
+	return d[len(d)/2]
+}
+
+func minutes(d time.Duration) string {
+	return fmt.Sprintf("%.1fm", d.Minutes())
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Staffing What-If Simulator ===")
+	fmt.Println()
+
+	customers := shopsim.Generate(demand, 1)
+	fmt.Printf("Day 1 has %d customers. Expected prep times (estimatePreparationTime rules):\n", len(customers))
+	for _, d := range shopsim.Menu {
+		fmt.Printf("  %-11s medium %v, large with 2 extras %v\n", d.Name,
+			shopsim.PrepTime(d, "Medium", 0), shopsim.PrepTime(d, "Large", 2))
+	}
+
+	// 1. One day, hour by hour
+	p := plans[2]
+	r := shopsim.Simulate(customers, p.staff, shopsim.Queue())
+	fmt.Printf("\n1. Day 1 with %q (%d staff-hours)\n", p.name, p.staff.StaffHours())
+	fmt.Printf("  %-6s %5s %8s %6s %9s %9s %6s\n", "Hour", "Staff", "Arrived", "Served", "Gave up", "Avg wait", "Busy")
+	for i, h := range r.Hours {
+		hour := fmt.Sprintf("%02d:00", opens+i)
+		busy := fmt.Sprintf("%.0f%%", h.Utilization()*100)
+		if i == len(p.staff) {
+			if r.Overtime == 0 {
+				break
+			}
+			hour, busy = "after", minutes(r.Overtime)
+		}
+		fmt.Printf("  %-6s %5d %8d %6d %9d %9s %6s\n", hour, h.Staff, h.Arrivals, h.Served, h.Abandoned, minutes(h.AverageWait()), busy)
+	}
+	fmt.Printf("  Waits: p50 %s, p90 %s, p99 %s; %.1f%% gave up; baristas %.0f%% busy\n",
+		minutes(r.Percentile(50)), minutes(r.Percentile(90)), minutes(r.Percentile(99)),
+		r.AbandonRate()*100, r.Utilization()*100)
+
+	// 2. Reproducible: the same seed gives the same day
+	again := shopsim.Simulate(shopsim.Generate(demand, 1), p.staff, shopsim.Queue())
+	fmt.Printf("\n2. Run it again with seed 1: p90 %s, %d gave up - identical: %t\n",
+		minutes(again.Percentile(90)), again.Abandoned,
+		again.Percentile(90) == r.Percentile(90) && again.Abandoned == r.Abandoned)
+
+	// 3. Compare the plans over many days
+	const days = 50
+	fmt.Printf("\n3. Staffing plans over %d simulated days (medians of daily percentiles)\n", days)
+	fmt.Printf("  %-18s %6s %6s %6s %6s %8s %6s %9s\n", "Plan", "Hours", "p50", "p90", "p99", "Gave up", "Busy", "Overtime")
+	fmt.Println("  " + strings.Repeat("-", 72))
+	for _, p := range plans {
+		s := replicate(p.staff, days)
+		fmt.Printf("  %-18s %6d %6s %6s %6s %7.1f%% %5.0f%% %9s\n", p.name, p.staff.StaffHours(),
+			minutes(s.p50), minutes(s.p90), minutes(s.p99), s.abandon*100, s.utilization*100, minutes(s.overtime))
+	}
+
+	fmt.Println()
+	fmt.Println("💡 Two all day is cheap but loses customers at 8 and 12; three all day")
+	fmt.Println("   pays for idle afternoons. Following the rush gets most of the service")
+	fmt.Println("   for fewer hours - and the simulator shows exactly where it falls short.")
+}
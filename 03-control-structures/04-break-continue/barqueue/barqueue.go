@@ -0,0 +1,245 @@
+// Package barqueue decides which drink the baristas make next.
+//
+// Orders come in three classes. Walk-ins wait in line, VIPs get a head
+// start, and mobile pre-orders are held until shortly before their pickup
+// time. Every order ages at the same rate once it's eligible, so a head
+// start is all a higher class ever gets: a walk-in can only be overtaken
+// by VIPs that ordered less than the VIP head start after it, and never
+// waits forever. Drinks with the same steamed milk can be pulled forward
+// a little to be made in one batch.
+package barqueue
+
+import (
+	"container/heap"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type Class int
+
+const (
+	WalkIn Class = iota
+	Mobile
+	VIP
+)
+
+func (c Class) String() string {
+	switch c {
+	case Mobile:
+		return "mobile"
+	case VIP:
+		return "VIP"
+	}
+	return "walk-in"
+}
+
+// Order is one drink to make
+type Order struct {
+	ID       string
+	Customer string
+	Class    Class
+	Drink    string
+	Milk     string // Steamed milk, "" for none; orders batch by milk
+	Placed   time.Time
+	PickupAt time.Time // Mobile orders only
+}
+
+type Config struct {
+	// HeadStart moves an order ahead of walk-ins that have waited less
+	// than this. Classes not in the map get none.
+	HeadStart map[Class]time.Duration
+
+	// MobileLead is how long before its pickup time a mobile order joins
+	// the line, so it's fresh when the customer walks in
+	MobileLead time.Duration
+
+	// BatchSize is the most drinks Next returns at once; 1 turns batching
+	// off. BatchWindow is how far behind the first drink another one can
+	// be and still be pulled forward into its batch.
+	BatchSize   int
+	BatchWindow time.Duration
+}
+
+// DefaultConfig is what the Seattle store uses
+func DefaultConfig() Config {
+	return Config{
+		HeadStart:   map[Class]time.Duration{VIP: 5 * time.Minute, Mobile: 2 * time.Minute},
+		MobileLead:  4 * time.Minute,
+		BatchSize:   3,
+		BatchWindow: 4 * time.Minute,
+	}
+}
+
+var (
+	ErrDuplicate = errors.New("order already queued")
+	ErrNoPickup  = errors.New("mobile order needs a pickup time")
+)
+
+// entry is a queued order. rank is when the order counts as having joined
+// the line: the earlier, the sooner it's made. Because all eligible orders
+// age at the same rate, rank never changes and a heap can hold them.
+type entry struct {
+	order    Order
+	eligible time.Time
+	rank     time.Time
+	index    int // In the heap, or -1 while held
+}
+
+type byRank []*entry
+
+func (h byRank) Len() int { return len(h) }
+func (h byRank) Less(i, j int) bool {
+	if !h[i].rank.Equal(h[j].rank) {
+		return h[i].rank.Before(h[j].rank)
+	}
+	return h[i].order.Placed.Before(h[j].order.Placed)
+}
+func (h byRank) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+func (h *byRank) Push(x any) {
+	e := x.(*entry)
+	e.index = len(*h)
+	*h = append(*h, e)
+}
+func (h *byRank) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	e.index = -1
+	return e
+}
+
+// Scheduler is a priority queue of orders. It isn't safe for concurrent
+// use; a shop has one ticket rail.
+type Scheduler struct {
+	cfg   Config
+	ready byRank            // Eligible orders
+	held  []*entry          // Mobile orders waiting for their lead time
+	byID  map[string]*entry // Every queued order
+}
+
+func New(cfg Config) *Scheduler {
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 1
+	}
+	return &Scheduler{cfg: cfg, byID: map[string]*entry{}}
+}
+
+// Add queues an order
+func (s *Scheduler) Add(o Order) error {
+	if _, ok := s.byID[o.ID]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicate, o.ID)
+	}
+	if o.Class == Mobile && o.PickupAt.IsZero() {
+		return fmt.Errorf("%w: %s", ErrNoPickup, o.ID)
+	}
+
+	e := &entry{order: o, eligible: o.Placed, index: -1}
+	if o.Class == Mobile {
+		if start := o.PickupAt.Add(-s.cfg.MobileLead); start.After(o.Placed) {
+			e.eligible = start
+		}
+	}
+	e.rank = e.eligible.Add(-s.cfg.HeadStart[o.Class])
+	s.byID[o.ID] = e
+
+	if o.Class == Mobile && e.eligible.After(o.Placed) {
+		s.held = append(s.held, e)
+	} else {
+		heap.Push(&s.ready, e)
+	}
+	return nil
+}
+
+// release moves held orders whose time has come into the line
+func (s *Scheduler) release(now time.Time) {
+	kept := s.held[:0]
+	for _, e := range s.held {
+		if e.eligible.After(now) {
+			kept = append(kept, e)
+			continue
+		}
+		heap.Push(&s.ready, e)
+	}
+	s.held = kept
+}
+
+// Next removes and returns the next batch to make: the top order plus up
+// to BatchSize-1 others with the same milk that are within BatchWindow of
+// it in line. It returns nil if nothing is ready at now.
+func (s *Scheduler) Next(now time.Time) []Order {
+	s.release(now)
+	if len(s.ready) == 0 {
+		return nil
+	}
+
+	first := heap.Pop(&s.ready).(*entry)
+	delete(s.byID, first.order.ID)
+	batch := []Order{first.order}
+	if first.order.Milk == "" {
+		return batch
+	}
+
+	var mates []*entry
+	limit := first.rank.Add(s.cfg.BatchWindow)
+	for _, e := range s.ready {
+		if e.order.Milk == first.order.Milk && !e.rank.After(limit) {
+			mates = append(mates, e)
+		}
+	}
+	sort.Slice(mates, func(i, j int) bool { return byRank(mates).Less(i, j) })
+
+	for _, e := range mates {
+		if len(batch) == s.cfg.BatchSize {
+			break
+		}
+		heap.Remove(&s.ready, e.index)
+		delete(s.byID, e.order.ID)
+		batch = append(batch, e.order)
+	}
+	return batch
+}
+
+// Cancel removes a queued order, held or not
+func (s *Scheduler) Cancel(id string) bool {
+	e, ok := s.byID[id]
+	if !ok {
+		return false
+	}
+	delete(s.byID, id)
+
+	if e.index >= 0 {
+		heap.Remove(&s.ready, e.index)
+		return true
+	}
+	for i, h := range s.held {
+		if h == e {
+			s.held = append(s.held[:i], s.held[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Len counts every queued order, including held mobile orders
+func (s *Scheduler) Len() int {
+	return len(s.byID)
+}
+
+// Line lists the orders that are ready at now in the order they would be
+// made, ignoring batching. Held mobile orders aren't included.
+func (s *Scheduler) Line(now time.Time) []Order {
+	s.release(now)
+	entries := append(byRank(nil), s.ready...)
+	sort.Slice(entries, func(i, j int) bool { return entries.Less(i, j) })
+
+	line := make([]Order, len(entries))
+	for i, e := range entries {
+		line[i] = e.order
+	}
+	return line
+}
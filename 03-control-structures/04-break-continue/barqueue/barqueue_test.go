@@ -0,0 +1,358 @@
+package barqueue
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+var t0 = time.Date(2024, time.March, 11, 8, 0, 0, 0, time.UTC)
+
+func at(minutes float64) time.Time {
+	return t0.Add(time.Duration(minutes * float64(time.Minute)))
+}
+
+// noBatching is DefaultConfig with one drink per Next, so tests see the
+// plain priority order
+func noBatching() Config {
+	cfg := DefaultConfig()
+	cfg.BatchSize = 1
+	return cfg
+}
+
+// drain calls Next at now until the queue is empty and lists the IDs made,
+// batches joined with "+"
+func drain(s *Scheduler, now time.Time) string {
+	var made []string
+	for batch := s.Next(now); batch != nil; batch = s.Next(now) {
+		var ids []string
+		for _, o := range batch {
+			ids = append(ids, o.ID)
+		}
+		made = append(made, strings.Join(ids, "+"))
+	}
+	return strings.Join(made, " ")
+}
+
+func mustAdd(t *testing.T, s *Scheduler, orders ...Order) {
+	t.Helper()
+	for _, o := range orders {
+		if err := s.Add(o); err != nil {
+			t.Fatalf("Add(%s): %v", o.ID, err)
+		}
+	}
+}
+
+func TestHeadStart(t *testing.T) {
+	tests := []struct {
+		name   string
+		orders []Order
+		want   string
+	}{
+		{
+			"walk-ins first come first served",
+			[]Order{
+				{ID: "w2", Class: WalkIn, Placed: at(1)},
+				{ID: "w1", Class: WalkIn, Placed: at(0)},
+			},
+			"w1 w2",
+		},
+		{
+			"VIP inside its head start goes first",
+			[]Order{
+				{ID: "w", Class: WalkIn, Placed: at(0)},
+				{ID: "v", Class: VIP, Placed: at(4)},
+			},
+			"v w",
+		},
+		{
+			"VIP exactly one head start later ties and loses on placed time",
+			[]Order{
+				{ID: "w", Class: WalkIn, Placed: at(0)},
+				{ID: "v", Class: VIP, Placed: at(5)},
+			},
+			"w v",
+		},
+		{
+			"VIP past its head start waits",
+			[]Order{
+				{ID: "w", Class: WalkIn, Placed: at(0)},
+				{ID: "v", Class: VIP, Placed: at(6)},
+			},
+			"w v",
+		},
+		{
+			"mobile and VIP tie on rank, earlier placed first",
+			[]Order{
+				{ID: "w", Class: WalkIn, Placed: at(0)},
+				{ID: "m", Class: Mobile, Placed: at(1), PickupAt: at(1)},
+				{ID: "v", Class: VIP, Placed: at(4)},
+				{ID: "m2", Class: Mobile, Placed: at(3), PickupAt: at(3)},
+			},
+			"m v w m2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(noBatching())
+			mustAdd(t, s, tt.orders...)
+			if got := drain(s, at(30)); got != tt.want {
+				t.Errorf("made %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassWithoutHeadStart(t *testing.T) {
+	cfg := noBatching()
+	cfg.HeadStart = map[Class]time.Duration{VIP: 5 * time.Minute} // Mobile gets none
+	s := New(cfg)
+	mustAdd(t, s,
+		Order{ID: "w", Class: WalkIn, Placed: at(0)},
+		Order{ID: "m", Class: Mobile, Placed: at(1), PickupAt: at(1)},
+	)
+	if got := drain(s, at(30)); got != "w m" {
+		t.Errorf("made %q, want %q", got, "w m")
+	}
+}
+
+// A walk-in is overtaken by exactly the VIPs placed less than HeadStart
+// after it, however many VIPs keep arriving
+func TestAgingBound(t *testing.T) {
+	cfg := noBatching()
+	headStart := cfg.HeadStart[VIP]
+
+	for _, step := range []time.Duration{30 * time.Second, time.Minute, 90 * time.Second} {
+		t.Run(fmt.Sprint(step), func(t *testing.T) {
+			s := New(cfg)
+			mustAdd(t, s, Order{ID: "walk-in", Class: WalkIn, Placed: t0})
+
+			ahead := 0
+			for i := 1; i <= 20; i++ {
+				placed := t0.Add(time.Duration(i) * step)
+				mustAdd(t, s, Order{ID: fmt.Sprintf("vip-%d", i), Class: VIP, Placed: placed})
+				if placed.Sub(t0) < headStart {
+					ahead++
+				}
+			}
+
+			for made := 0; ; made++ {
+				batch := s.Next(t0.Add(time.Hour))
+				if batch == nil {
+					t.Fatal("queue ran dry before the walk-in was made")
+				}
+				if batch[0].ID == "walk-in" {
+					if made != ahead {
+						t.Errorf("walk-in was made after %d VIPs, want %d", made, ahead)
+					}
+					return
+				}
+			}
+		})
+	}
+}
+
+func TestMobileHeldUntilLead(t *testing.T) {
+	cfg := noBatching() // MobileLead 4m, mobile head start 2m
+	release := at(16)   // Pickup at 20, minus the lead
+
+	tests := []struct {
+		name string
+		now  time.Time
+		want string // What Line shows
+	}{
+		{"well before", at(10), ""},
+		{"just before", release.Add(-time.Second), ""},
+		{"at release", release, "m"},
+		{"after release", at(25), "m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(cfg)
+			mustAdd(t, s, Order{ID: "m", Class: Mobile, Placed: at(0), PickupAt: at(20)})
+
+			var ids []string
+			for _, o := range s.Line(tt.now) {
+				ids = append(ids, o.ID)
+			}
+			if got := strings.Join(ids, " "); got != tt.want {
+				t.Errorf("Line = %q, want %q", got, tt.want)
+			}
+			if s.Len() != 1 {
+				t.Errorf("Len = %d, want 1 (held orders count)", s.Len())
+			}
+			if got := drain(s, tt.now); got != tt.want {
+				t.Errorf("Next made %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Once released, a mobile order ranks from its release time minus its head
+// start, not from when it was placed
+func TestMobileRanksFromRelease(t *testing.T) {
+	tests := []struct {
+		walkIn float64 // Minutes; the mobile order ranks at 16-2 = 14
+		want   string
+	}{
+		{13, "w m"},
+		{14, "m w"}, // Same rank, the mobile order was placed first
+		{15, "m w"},
+	}
+	for _, tt := range tests {
+		s := New(noBatching())
+		mustAdd(t, s,
+			Order{ID: "m", Class: Mobile, Placed: at(0), PickupAt: at(20)},
+			Order{ID: "w", Class: WalkIn, Placed: at(tt.walkIn)},
+		)
+		if got := drain(s, at(30)); got != tt.want {
+			t.Errorf("walk-in at %v min: made %q, want %q", tt.walkIn, got, tt.want)
+		}
+	}
+}
+
+func TestMobilePickupWithinLeadIsNotHeld(t *testing.T) {
+	s := New(noBatching())
+	mustAdd(t, s, Order{ID: "m", Class: Mobile, Placed: at(0), PickupAt: at(2)})
+	if got := drain(s, at(0)); got != "m" {
+		t.Errorf("made %q at once, want %q", got, "m")
+	}
+}
+
+func TestMilkBatching(t *testing.T) {
+	// Walk-ins a minute apart; "x" has no milk and breaks no batch
+	line := []Order{
+		{ID: "a", Milk: "oat", Placed: at(0)},
+		{ID: "b", Milk: "whole", Placed: at(1)},
+		{ID: "c", Milk: "oat", Placed: at(2)},
+		{ID: "x", Placed: at(3)},
+		{ID: "d", Milk: "oat", Placed: at(4)},
+		{ID: "e", Milk: "oat", Placed: at(5)},
+		{ID: "f", Milk: "whole", Placed: at(6)},
+	}
+
+	tests := []struct {
+		name   string
+		size   int
+		window time.Duration
+		want   string
+	}{
+		{"batching off", 1, 4 * time.Minute, "a b c x d e f"},
+		{"pairs", 2, 4 * time.Minute, "a+c b x d+e f"},
+		{"default", 3, 4 * time.Minute, "a+c+d b x e f"}, // f is 5 minutes behind b
+		{"window reaches the last oat", 4, 5 * time.Minute, "a+c+d+e b+f x"},
+		{"window too short for anyone", 3, 30 * time.Second, "a b c x d e f"},
+		{"window edge is included", 3, 2 * time.Minute, "a+c b x d+e f"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultConfig()
+			cfg.BatchSize, cfg.BatchWindow = tt.size, tt.window
+			s := New(cfg)
+			mustAdd(t, s, line...)
+			if got := drain(s, at(30)); got != tt.want {
+				t.Errorf("made %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestZeroBatchSizeMeansOne(t *testing.T) {
+	s := New(Config{})
+	mustAdd(t, s,
+		Order{ID: "a", Milk: "oat", Placed: at(0)},
+		Order{ID: "b", Milk: "oat", Placed: at(0)},
+	)
+	if got := drain(s, at(1)); got != "a b" {
+		t.Errorf("made %q, want %q", got, "a b")
+	}
+}
+
+func TestCancel(t *testing.T) {
+	newQueue := func(t *testing.T) *Scheduler {
+		s := New(noBatching())
+		mustAdd(t, s,
+			Order{ID: "w1", Class: WalkIn, Placed: at(0)},
+			Order{ID: "held", Class: Mobile, Placed: at(0), PickupAt: at(20)},
+			Order{ID: "w2", Class: WalkIn, Placed: at(1)},
+			Order{ID: "w3", Class: WalkIn, Placed: at(2)},
+		)
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		cancel string
+		ok     bool
+		want   string
+	}{
+		{"ready at the top", "w1", true, "w2 w3 held"},
+		{"ready in the middle", "w2", true, "w1 w3 held"},
+		{"held", "held", true, "w1 w2 w3"},
+		{"unknown", "nope", false, "w1 w2 w3 held"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newQueue(t)
+			if ok := s.Cancel(tt.cancel); ok != tt.ok {
+				t.Fatalf("Cancel(%q) = %t, want %t", tt.cancel, ok, tt.ok)
+			}
+			if s.Cancel(tt.cancel) {
+				t.Errorf("second Cancel(%q) succeeded", tt.cancel)
+			}
+			wantLen := 4
+			if tt.ok {
+				wantLen = 3
+			}
+			if s.Len() != wantLen {
+				t.Errorf("Len = %d, want %d", s.Len(), wantLen)
+			}
+			if got := drain(s, at(30)); got != tt.want {
+				t.Errorf("made %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddErrors(t *testing.T) {
+	s := New(DefaultConfig())
+	mustAdd(t, s,
+		Order{ID: "w", Class: WalkIn, Placed: at(0)},
+		Order{ID: "m", Class: Mobile, Placed: at(0), PickupAt: at(20)},
+	)
+
+	tests := []struct {
+		name  string
+		order Order
+		want  error
+	}{
+		{"duplicate of a ready order", Order{ID: "w", Class: VIP, Placed: at(1)}, ErrDuplicate},
+		{"duplicate of a held order", Order{ID: "m", Class: WalkIn, Placed: at(1)}, ErrDuplicate},
+		{"mobile without pickup", Order{ID: "m2", Class: Mobile, Placed: at(1)}, ErrNoPickup},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Add(tt.order)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Add = %v, want %v", err, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.order.ID) {
+				t.Errorf("error %q doesn't name the order", err)
+			}
+		})
+	}
+	if s.Len() != 2 {
+		t.Errorf("rejected orders were queued: Len = %d, want 2", s.Len())
+	}
+
+	// An order that has been made or cancelled can be queued again
+	s.Next(at(1))
+	s.Cancel("m")
+	mustAdd(t, s,
+		Order{ID: "w", Class: WalkIn, Placed: at(2)},
+		Order{ID: "m", Class: Mobile, Placed: at(2), PickupAt: at(2)},
+	)
+}
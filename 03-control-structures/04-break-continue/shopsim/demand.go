@@ -0,0 +1,135 @@
+package shopsim
+
+import (
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Drink is one thing on the menu and how often it's ordered
+type Drink struct {
+	Name   string
+	Coffee bool
+	Weight float64
+}
+
+// Menu is the order mix, roughly what the registers see
+var Menu = []Drink{
+	{"Latte", true, 30},
+	{"Cappuccino", true, 15},
+	{"Americano", true, 15},
+	{"Mocha", true, 10},
+	{"Espresso", true, 10},
+	{"Pastry", false, 20},
+}
+
+// Customer is one arrival. Everything random about a customer is drawn
+// when the demand is generated, so every staffing plan sees exactly the
+// same customers and differences come from the plan alone.
+type Customer struct {
+	ID             int
+	Arrive         time.Duration // After opening
+	Drink          Drink
+	Size           string
+	Customizations int
+	VIP            bool
+	Service        time.Duration // How long this one actually takes
+	Patience       time.Duration // Longest they'll wait for a barista
+}
+
+// PrepTime is the expected time for an order, with the rules from
+// estimatePreparationTime and prepareCoffeeOrder: 2 minutes base, +1 for
+// coffee, +30s per customization and +30s for a large
+func PrepTime(drink Drink, size string, customizations int) time.Duration {
+	t := 2 * time.Minute
+	if drink.Coffee {
+		t += time.Minute
+	}
+	t += 30 * time.Second * time.Duration(customizations)
+	if size == "Large" {
+		t += 30 * time.Second
+	}
+	return t
+}
+
+// Demand describes who comes in
+type Demand struct {
+	PerHour  []float64     // Expected arrivals in each opening hour
+	VIPShare float64       // Fraction of customers who are VIPs
+	Patience time.Duration // Mean patience; VIPs wait half again as long
+	Spread   float64       // Variation of service times around PrepTime (0.3 = ±30% typical)
+}
+
+// Generate draws the day's customers. The same demand and seed always
+// give the same customers.
+func Generate(d Demand, seed int64) []Customer {
+	rng := rand.New(rand.NewSource(seed))
+	var customers []Customer
+
+	for hour, rate := range d.PerHour {
+		if rate <= 0 {
+			continue
+		}
+		// Arrivals are a Poisson process within each hour; because
+		// exponential gaps have no memory, restarting at each hour with
+		// the new rate is exact
+		start := time.Duration(hour) * time.Hour
+		at := start
+		for {
+			at += time.Duration(rng.ExpFloat64() / rate * float64(time.Hour))
+			if at >= start+time.Hour {
+				break
+			}
+			customers = append(customers, newCustomer(rng, d, len(customers)+1, at))
+		}
+	}
+	return customers
+}
+
+func newCustomer(rng *rand.Rand, d Demand, id int, at time.Duration) Customer {
+	c := Customer{ID: id, Arrive: at, Drink: pickDrink(rng)}
+
+	c.Size = "Medium"
+	if c.Drink.Coffee {
+		switch r := rng.Float64(); {
+		case r < 0.25:
+			c.Size = "Small"
+		case r > 0.70:
+			c.Size = "Large"
+		}
+		switch r := rng.Float64(); {
+		case r > 0.85:
+			c.Customizations = 2
+		case r > 0.50:
+			c.Customizations = 1
+		}
+	}
+
+	// Log-normal around the expected time, keeping its mean
+	mean := PrepTime(c.Drink, c.Size, c.Customizations)
+	factor := math.Exp(d.Spread*rng.NormFloat64() - d.Spread*d.Spread/2)
+	c.Service = time.Duration(float64(mean) * factor).Round(time.Second)
+
+	c.VIP = rng.Float64() < d.VIPShare
+	patience := d.Patience
+	if c.VIP {
+		patience = patience * 3 / 2
+	}
+	c.Patience = max(time.Minute, time.Duration(rng.ExpFloat64()*float64(patience)))
+	return c
+}
+
+func pickDrink(rng *rand.Rand) Drink {
+	total := 0.0
+	for _, d := range Menu {
+		total += d.Weight
+	}
+	r := rng.Float64() * total
+	for _, d := range Menu {
+		if r < d.Weight {
+			return d
+		}
+		r -= d.Weight
+	}
+	return Menu[len(Menu)-1]
+}
@@ -0,0 +1,80 @@
+package shopsim
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var morning = Demand{
+	PerHour:  []float64{20, 45, 30},
+	VIPShare: 0.1,
+	Patience: 8 * time.Minute,
+	Spread:   0.3,
+}
+
+func TestGenerateSameSeed(t *testing.T) {
+	a, b := Generate(morning, 42), Generate(morning, 42)
+	if len(a) == 0 {
+		t.Fatal("Generate drew no customers")
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Error("the same seed gave different customers")
+	}
+	if reflect.DeepEqual(a, Generate(morning, 43)) {
+		t.Error("a different seed gave the same customers")
+	}
+}
+
+func TestGenerateCustomers(t *testing.T) {
+	customers := Generate(morning, 7)
+	end := time.Duration(len(morning.PerHour)) * time.Hour
+	for i, c := range customers {
+		if c.ID != i+1 {
+			t.Errorf("customer %d has ID %d", i, c.ID)
+		}
+		if i > 0 && c.Arrive < customers[i-1].Arrive {
+			t.Errorf("customer %d arrives at %v, before customer %d", c.ID, c.Arrive, i)
+		}
+		if c.Arrive < 0 || c.Arrive >= end {
+			t.Errorf("customer %d arrives at %v, outside opening hours", c.ID, c.Arrive)
+		}
+		if c.Patience < time.Minute || c.Service <= 0 {
+			t.Errorf("customer %d: patience %v, service %v", c.ID, c.Patience, c.Service)
+		}
+		if !c.Drink.Coffee && (c.Size != "Medium" || c.Customizations != 0) {
+			t.Errorf("customer %d: %s %s with %d customizations", c.ID, c.Size, c.Drink.Name, c.Customizations)
+		}
+	}
+}
+
+// Hours with no expected arrivals stay empty
+func TestGenerateClosedHours(t *testing.T) {
+	d := morning
+	d.PerHour = []float64{0, 30, -5}
+	for _, c := range Generate(d, 1) {
+		if c.Arrive < time.Hour || c.Arrive >= 2*time.Hour {
+			t.Errorf("customer %d arrives at %v, outside the one open hour", c.ID, c.Arrive)
+		}
+	}
+}
+
+func TestPrepTime(t *testing.T) {
+	latte := Drink{Name: "Latte", Coffee: true}
+	pastry := Drink{Name: "Pastry"}
+	tests := []struct {
+		drink          Drink
+		size           string
+		customizations int
+		want           time.Duration
+	}{
+		{pastry, "Medium", 0, 2 * time.Minute},
+		{latte, "Medium", 0, 3 * time.Minute},
+		{latte, "Large", 2, 4*time.Minute + 30*time.Second},
+	}
+	for _, tt := range tests {
+		if got := PrepTime(tt.drink, tt.size, tt.customizations); got != tt.want {
+			t.Errorf("PrepTime(%s, %s, %d) = %v, want %v", tt.drink.Name, tt.size, tt.customizations, got, tt.want)
+		}
+	}
+}
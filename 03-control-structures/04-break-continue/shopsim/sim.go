@@ -0,0 +1,286 @@
+// Package shopsim is a discrete-event simulation of the coffee bar.
+//
+// Instead of stepping through the day an hour at a time and mutating
+// counters inline, the simulator keeps a queue of timestamped events
+// (a customer arrives, a drink is finished, a customer gives up, a shift
+// changes) and jumps from one to the next. Customers come from Generate,
+// wait in a barqueue.Scheduler, and are served by as many baristas as the
+// Staffing plan has on shift that hour.
+package shopsim
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"break-continue-examples/barqueue"
+)
+
+// Staffing is how many baristas are on shift in each opening hour. The
+// doors close after the last hour; the last shift stays to finish the
+// queue.
+type Staffing []int
+
+// StaffHours is the paid time in the plan, not counting overtime
+func (s Staffing) StaffHours() int {
+	total := 0
+	for _, n := range s {
+		total += n
+	}
+	return total
+}
+
+type eventKind int
+
+const (
+	shiftChange eventKind = iota // Ordered first, so new staff serve arrivals at the same instant
+	arrive
+	finish
+	giveUp
+)
+
+type event struct {
+	at    time.Duration
+	kind  eventKind
+	index int // Customer, or the hour for a shiftChange
+	seq   int // Breaks ties so runs are deterministic
+}
+
+type eventQueue []event
+
+func (q eventQueue) Len() int { return len(q) }
+func (q eventQueue) Less(i, j int) bool {
+	if q[i].at != q[j].at {
+		return q[i].at < q[j].at
+	}
+	if q[i].kind != q[j].kind {
+		return q[i].kind < q[j].kind
+	}
+	return q[i].seq < q[j].seq
+}
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+func (q *eventQueue) Push(x any)   { *q = append(*q, x.(event)) }
+func (q *eventQueue) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return e
+}
+
+// HourStats is what happened in one hour. Overtime after closing is
+// counted as an extra hour at the end.
+type HourStats struct {
+	Staff     int
+	Arrivals  int
+	Served    int // Started service this hour
+	Abandoned int
+	Busy      time.Duration // Barista time spent making drinks
+	TotalWait time.Duration // Of the customers served this hour
+}
+
+// Utilization is the share of staffed time spent making drinks
+func (h HourStats) Utilization() float64 {
+	if h.Staff == 0 {
+		return 0
+	}
+	return float64(h.Busy) / float64(time.Duration(h.Staff)*time.Hour)
+}
+
+func (h HourStats) AverageWait() time.Duration {
+	if h.Served == 0 {
+		return 0
+	}
+	return h.TotalWait / time.Duration(h.Served)
+}
+
+// Report is the outcome of one simulated day
+type Report struct {
+	Customers int
+	Served    int
+	Abandoned int
+	Waits     []time.Duration // Of served customers, sorted
+	Hours     []HourStats
+	Overtime  time.Duration // From closing until the last drink
+	Busy      time.Duration
+	Staffed   time.Duration // Barista time paid for, overtime included
+}
+
+// Percentile returns the p-th percentile (0-100) wait, nearest rank: the
+// smallest wait that at least p% of waits are no longer than
+func (r Report) Percentile(p float64) time.Duration {
+	if len(r.Waits) == 0 {
+		return 0
+	}
+	i := int(math.Ceil(p/100*float64(len(r.Waits)))) - 1
+	return r.Waits[min(max(i, 0), len(r.Waits)-1)]
+}
+
+func (r Report) AbandonRate() float64 {
+	if r.Customers == 0 {
+		return 0
+	}
+	return float64(r.Abandoned) / float64(r.Customers)
+}
+
+func (r Report) Utilization() float64 {
+	if r.Staffed == 0 {
+		return 0
+	}
+	return float64(r.Busy) / float64(r.Staffed)
+}
+
+// sim is the state of one run
+type sim struct {
+	customers []Customer
+	staffing  Staffing
+	queue     *barqueue.Scheduler
+	events    eventQueue
+	seq       int
+
+	now        time.Duration
+	staff      int
+	busy       int
+	lastFinish time.Duration
+
+	report Report
+}
+
+// day anchors barqueue's wall-clock times; only differences matter
+var day = time.Date(2024, time.March, 11, 6, 0, 0, 0, time.UTC)
+
+// Queue is how customers line up: VIPs get barqueue's head start, and
+// drinks are made one at a time
+func Queue() barqueue.Config {
+	cfg := barqueue.DefaultConfig()
+	cfg.BatchSize = 1
+	return cfg
+}
+
+// Simulate runs one day. It is deterministic: the same customers and plan
+// always give the same report.
+func Simulate(customers []Customer, staffing Staffing, queue barqueue.Config) Report {
+	s := &sim{
+		customers: customers,
+		staffing:  staffing,
+		queue:     barqueue.New(queue),
+	}
+	s.report.Customers = len(customers)
+	s.report.Hours = make([]HourStats, len(staffing)+1) // +1 for overtime
+
+	for hour := range staffing {
+		s.schedule(time.Duration(hour)*time.Hour, shiftChange, hour)
+	}
+	for i, c := range customers {
+		s.schedule(c.Arrive, arrive, i)
+	}
+
+	for len(s.events) > 0 {
+		e := heap.Pop(&s.events).(event)
+		s.now = e.at
+		s.handle(e)
+	}
+
+	s.close()
+	return s.report
+}
+
+func (s *sim) schedule(at time.Duration, kind eventKind, index int) {
+	s.seq++
+	heap.Push(&s.events, event{at: at, kind: kind, index: index, seq: s.seq})
+}
+
+// hour is the stats bucket for a time; anything after closing is overtime
+func (s *sim) hour(t time.Duration) *HourStats {
+	h := min(int(t/time.Hour), len(s.staffing))
+	return &s.report.Hours[h]
+}
+
+func (s *sim) handle(e event) {
+	switch e.kind {
+	case shiftChange:
+		s.staff = s.staffing[e.index]
+		s.report.Hours[e.index].Staff = s.staff
+
+	case arrive:
+		c := s.customers[e.index]
+		s.hour(s.now).Arrivals++
+		class := barqueue.WalkIn
+		if c.VIP {
+			class = barqueue.VIP
+		}
+		s.queue.Add(barqueue.Order{
+			ID:     strconv.Itoa(e.index),
+			Class:  class,
+			Drink:  c.Drink.Name,
+			Placed: day.Add(s.now),
+		})
+		s.schedule(s.now+c.Patience, giveUp, e.index)
+
+	case giveUp:
+		// Cancel only succeeds if they're still waiting
+		if s.queue.Cancel(strconv.Itoa(e.index)) {
+			s.report.Abandoned++
+			s.hour(s.now).Abandoned++
+		}
+
+	case finish:
+		s.busy--
+		s.lastFinish = s.now
+	}
+
+	s.startWork()
+}
+
+// startWork gives waiting customers to free baristas. When a shift gets
+// smaller, baristas finish their drink before leaving, so busy can be
+// above staff for a while.
+func (s *sim) startWork() {
+	for s.busy < s.staff {
+		next := s.queue.Next(day.Add(s.now))
+		if next == nil {
+			return
+		}
+		i, _ := strconv.Atoi(next[0].ID)
+		c := s.customers[i]
+
+		wait := s.now - c.Arrive
+		s.report.Served++
+		s.report.Waits = append(s.report.Waits, wait)
+		h := s.hour(s.now)
+		h.Served++
+		h.TotalWait += wait
+
+		s.busy++
+		s.addBusy(s.now, s.now+c.Service)
+		s.schedule(s.now+c.Service, finish, i)
+	}
+}
+
+// addBusy splits a service interval over the hours it spans
+func (s *sim) addBusy(from, to time.Duration) {
+	s.report.Busy += to - from
+	for from < to {
+		end := min(to, (from/time.Hour+1)*time.Hour)
+		if int(from/time.Hour) >= len(s.staffing) {
+			end = to
+		}
+		s.hour(from).Busy += end - from
+		from = end
+	}
+}
+
+func (s *sim) close() {
+	closing := time.Duration(len(s.staffing)) * time.Hour
+	s.report.Overtime = max(0, s.lastFinish-closing)
+
+	last := 0
+	if len(s.staffing) > 0 {
+		last = s.staffing[len(s.staffing)-1]
+	}
+	s.report.Staffed = time.Duration(s.staffing.StaffHours())*time.Hour + time.Duration(last)*s.report.Overtime
+	s.report.Hours[len(s.staffing)].Staff = last
+
+	sort.Slice(s.report.Waits, func(i, j int) bool { return s.report.Waits[i] < s.report.Waits[j] })
+}
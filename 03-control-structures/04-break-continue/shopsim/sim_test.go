@@ -0,0 +1,118 @@
+package shopsim
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSimulateDeterministic(t *testing.T) {
+	customers := Generate(morning, 42)
+	plan := Staffing{2, 3, 2}
+	a := Simulate(customers, plan, Queue())
+	b := Simulate(customers, plan, Queue())
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("two runs differ:\n%+v\n%+v", a, b)
+	}
+}
+
+func TestSimulateAccounting(t *testing.T) {
+	customers := Generate(morning, 42)
+	r := Simulate(customers, Staffing{2, 3, 2}, Queue())
+
+	if r.Customers != len(customers) || r.Served+r.Abandoned != r.Customers {
+		t.Errorf("%d customers: %d served + %d abandoned", r.Customers, r.Served, r.Abandoned)
+	}
+	if len(r.Waits) != r.Served {
+		t.Errorf("%d waits for %d served", len(r.Waits), r.Served)
+	}
+	if len(r.Hours) != 4 {
+		t.Errorf("%d hour buckets, want 3 plus overtime", len(r.Hours))
+	}
+
+	var arrivals, served, abandoned int
+	var busy time.Duration
+	for _, h := range r.Hours {
+		arrivals += h.Arrivals
+		served += h.Served
+		abandoned += h.Abandoned
+		busy += h.Busy
+	}
+	if arrivals != r.Customers || served != r.Served || abandoned != r.Abandoned || busy != r.Busy {
+		t.Errorf("hours add up to %d arrivals, %d served, %d abandoned, %v busy; report has %d, %d, %d, %v",
+			arrivals, served, abandoned, busy, r.Customers, r.Served, r.Abandoned, r.Busy)
+	}
+	if u := r.Utilization(); u <= 0 || u > 1 {
+		t.Errorf("Utilization = %v, want within (0, 1]", u)
+	}
+}
+
+// With nobody on shift nobody is served, and everyone gives up
+func TestSimulateNoStaff(t *testing.T) {
+	customers := Generate(morning, 42)
+	r := Simulate(customers, Staffing{0, 0, 0}, Queue())
+
+	if r.Served != 0 || r.Abandoned != len(customers) {
+		t.Errorf("no staff: %d served, %d of %d abandoned", r.Served, r.Abandoned, len(customers))
+	}
+	if r.AbandonRate() != 1 {
+		t.Errorf("AbandonRate = %v, want 1", r.AbandonRate())
+	}
+	if r.Utilization() != 0 || r.Percentile(50) != 0 || r.Overtime != 0 {
+		t.Errorf("no staff: utilization %v, median wait %v, overtime %v", r.Utilization(), r.Percentile(50), r.Overtime)
+	}
+}
+
+// More baristas never make the waits longer for the same customers
+func TestMoreStaffShorterWaits(t *testing.T) {
+	customers := Generate(morning, 42)
+	short := Simulate(customers, Staffing{1, 1, 1}, Queue())
+	full := Simulate(customers, Staffing{4, 4, 4}, Queue())
+	if full.Percentile(90) > short.Percentile(90) || full.Abandoned > short.Abandoned {
+		t.Errorf("4 baristas: p90 %v, %d abandoned; 1 barista: p90 %v, %d abandoned",
+			full.Percentile(90), full.Abandoned, short.Percentile(90), short.Abandoned)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	r := Report{Waits: []time.Duration{1 * time.Minute, 2 * time.Minute, 3 * time.Minute, 4 * time.Minute}}
+	tests := []struct {
+		p    float64
+		want time.Duration
+	}{
+		{-10, 1 * time.Minute},
+		{0, 1 * time.Minute},
+		{25, 1 * time.Minute},
+		{50, 2 * time.Minute},
+		{51, 3 * time.Minute},
+		{100, 4 * time.Minute},
+		{150, 4 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := r.Percentile(tt.p); got != tt.want {
+			t.Errorf("Percentile(%v) = %v, want %v", tt.p, got, tt.want)
+		}
+	}
+	if got := (Report{}).Percentile(50); got != 0 {
+		t.Errorf("Percentile of no waits = %v, want 0", got)
+	}
+}
+
+func TestUtilization(t *testing.T) {
+	tests := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"hour without staff", HourStats{Busy: time.Hour}.Utilization(), 0},
+		{"hour half busy", HourStats{Staff: 2, Busy: time.Hour}.Utilization(), 0.5},
+		{"day without staff", Report{Busy: time.Hour}.Utilization(), 0},
+		{"day fully busy", Report{Busy: 3 * time.Hour, Staffed: 3 * time.Hour}.Utilization(), 1},
+		{"no customers", Report{}.AbandonRate(), 0},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+}
@@ -110,6 +110,8 @@ Shutdown:
 }
 
 // Example 2: Better state machine using switch
+// (12_machine_controller.go takes this further: typed states, a hardware
+// driver, temperature regulation, fault codes and state-change events)
 func coffeeMachineSwitch() {
 	type MachineState struct {
 		state  string
@@ -138,6 +138,8 @@ Cleanup:
 }
 
 // Example 2: Simple parser using goto for state transitions
+// parseOrderFormat can't handle commas or colons in values and accepts any
+// key; 11_order_codec.go is the full codec for this format
 func parseOrderFormat(input string) {
 	fmt.Printf("Parsing order string: %s\n\n", input)
 	
@@ -163,7 +163,7 @@ ScanStart:
 		goto CheckUnderscore
 	}
 	
-	// Unknown token
+	// Unknown token - skipped without a word (10_order_parser.go reports it)
 	pos++
 	goto ScanStart
 	
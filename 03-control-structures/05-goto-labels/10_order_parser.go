@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"goto-labels-examples/orderparse"
+	"switch-examples/catalog"
+)
+
+// scanCoffeeOrder in 09_real_world_goto.go knows five uppercase tokens and
+// skips everything else without a word. The orderparse package (in
+// ./orderparse) is the structured version: a lexer, a recursive-descent
+// parser and a menu to resolve names against, loaded from the same
+// 02-switch/data/menu.json the switch chapter prints. A mistake is reported with
+// its column and the closest menu words, and the parser keeps going, so
+// one typo doesn't hide the rest of the order.
+
+// === Demo ===
+
+func main() {
+	fmt.Println("=== GoCoffee: Free-Text Order Parser ===")
+	fmt.Println()
+
+	menu, err := catalog.Load("../02-switch/data/menu.json")
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
+	}
+	parser := orderparse.New(orderparse.FromCatalog(menu, "coffee", "cold"))
+
+	orders := []string{
+		"2 large oat lattes, extra shot, no foam; 1 croissant warmed",
+		"LARGE LATTE EXTRA SHOT",
+		"a doppio espresso and two cold brews with vanilla",
+		"3 lage lattte, extr shot; croissant",
+		"large croissant; 25 mochas, warmed",
+		"latte oat almond; muffin 2 bagels",
+		"tall latte & honey",
+		"",
+	}
+
+	for _, text := range orders {
+		fmt.Printf("> %q\n", text)
+		order, err := parser.Parse(text)
+
+		for _, line := range order.Lines {
+			fmt.Printf("  ✓ %-48s %7s\n", line, line.Total())
+		}
+		if len(order.Lines) > 0 {
+			fmt.Printf("  %-50s %7s\n", "Total", order.Total())
+		}
+
+		if list, ok := err.(orderparse.ParseErrors); ok {
+			for _, e := range list {
+				fmt.Printf("  ❌ %v\n", e)
+				for _, row := range strings.Split(e.Caret(text), "\n") {
+					fmt.Printf("       %s\n", row)
+				}
+			}
+		}
+		fmt.Println()
+	}
+
+	fmt.Println("💡 A parser that reports every mistake, with where it is and what")
+	fmt.Println("   was probably meant, beats one that quietly drops what it can't read.")
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"goto-labels-examples/codec"
+)
+
+// parseOrderFormat in 06_legitimate_uses.go reads "KEY:value,KEY:value"
+// with goto and string concatenation. A value can't hold a comma or a
+// colon, a repeated key overwrites the first, and any key is accepted.
+// The codec package (in ./codec) is the same wire format done properly:
+// quoted values with escapes, repeated keys as lists, and a Schema for
+// what an order must contain. Its FuzzDecode test checks that Decode
+// never panics and that whatever it accepts round-trips.
+
+func main() {
+	fmt.Println("=== GoCoffee: Order Wire Format Codec ===")
+	fmt.Println()
+
+	original := "COFFEE:Latte,SIZE:Large,EXTRAS:ExtraShot"
+
+	fmt.Println("1. The message from 06_legitimate_uses.go")
+	order, err := codec.DecodeOrder(original)
+	if err != nil {
+		fmt.Printf("  ❌ %v\n", err)
+		return
+	}
+	fmt.Printf("  %s\n  → %+v\n", original, order)
+
+	fmt.Println("\n2. Values with commas, colons and quotes, and a list")
+	order = codec.Order{
+		Coffee:   "Mocha",
+		Size:     "Medium",
+		Quantity: 2,
+		Extras:   []string{"Oat Milk", "Vanilla"},
+		Name:     "Smith, Jane",
+		Note:     `pickup at 10:30, say "hi"`,
+		Price:    1150,
+		ToGo:     true,
+	}
+	encoded, err := codec.EncodeOrder(order)
+	if err != nil {
+		fmt.Printf("  ❌ %v\n", err)
+		return
+	}
+	decoded, err := codec.DecodeOrder(encoded)
+	fmt.Printf("  %s\n", encoded)
+	fmt.Printf("  Round-trips: %t (err: %v)\n", reflect.DeepEqual(order, decoded), err)
+
+	fmt.Println("\n3. Syntax errors")
+	for _, input := range []string{
+		"COFFEE:Latte,SIZE",
+		"COFFEE:Latte,,SIZE:Large",
+		"NOTE:pickup at 10:30",
+		`NAME:"Smith, Jane`,
+		`NOTE:"tab\x"`,
+		"COFFEE:Latte SIZE:Large",
+	} {
+		_, err := codec.Decode(input)
+		var syntaxErr *codec.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			fmt.Printf("  %-26s ❌ %v\n", input, err)
+			fmt.Printf("  %s^\n", strings.Repeat(" ", syntaxErr.Offset))
+		}
+	}
+
+	fmt.Println("\n4. Schema errors")
+	for _, input := range []string{
+		"COFFEE:Latte",
+		"COFFEE:Tea,SIZE:Large,SIZE:Small,QTY:two",
+		"COFFEE:Latte,SIZE:Large,PRICE:4.555,COLOR:Red",
+	} {
+		_, err := codec.DecodeOrder(input)
+		var invalid *codec.ValidationError
+		if errors.As(err, &invalid) {
+			fmt.Printf("  %s\n", input)
+			for _, problem := range invalid.Problems {
+				fmt.Printf("    ❌ %s\n", problem)
+			}
+		}
+	}
+
+	fmt.Println("\n5. Fuzzing the decoder")
+	fmt.Println("  go test -fuzz=FuzzDecode -fuzztime=30s ./codec")
+}
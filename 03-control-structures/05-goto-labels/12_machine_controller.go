@@ -0,0 +1,743 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// coffeeMachineSwitch in 04_state_machine.go walks idle → check_resources
+// → heating → grinding → brewing → dispensing with string states and
+// sleeps, and the monitor in 08_infinite_loop.go nudges machineTemp by
+// random amounts. This is the controller behind a real machine:
+//
+//   - States are typed, and only the transitions in a table are allowed.
+//   - Hardware sits behind a Driver, so the same controller runs the
+//     simulated boiler here or a real one.
+//   - The boiler is held at temperature by a PID regulator.
+//   - Beans and water are tracked, and problems become fault codes that
+//     take the machine out of service until maintenance clears them.
+//   - Every state change is emitted as an Event to whoever listens.
+//
+// Time is simulated: the controller is stepped a second at a time, so a
+// whole morning runs instantly and always the same way.
+
+// === States ===
+
+type State int
+
+const (
+	Off State = iota
+	WarmingUp
+	Idle
+	CheckResources
+	Heating
+	Grinding
+	Brewing
+	Dispensing
+	Faulted
+	Maintenance
+)
+
+var stateNames = [...]string{
+	"OFF", "WARMING_UP", "IDLE", "CHECK_RESOURCES", "HEATING",
+	"GRINDING", "BREWING", "DISPENSING", "FAULTED", "MAINTENANCE",
+}
+
+func (s State) String() string {
+	if s >= 0 && int(s) < len(stateNames) {
+		return stateNames[s]
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// transitions is every move the machine may make. Any state can fault;
+// only maintenance gets it out again.
+var transitions = map[State][]State{
+	Off:            {WarmingUp},
+	WarmingUp:      {Idle, Maintenance},
+	Idle:           {CheckResources, Maintenance, Off},
+	CheckResources: {Heating, Grinding},
+	Heating:        {Grinding},
+	Grinding:       {Brewing},
+	Brewing:        {Dispensing},
+	Dispensing:     {Idle},
+	Faulted:        {Maintenance, Off},
+	Maintenance:    {Idle, Off},
+}
+
+func canMove(from, to State) bool {
+	if to == Faulted {
+		return from != Off && from != Faulted
+	}
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// === Faults ===
+
+type Fault int
+
+const (
+	NoFault Fault = iota
+	LowBeans
+	LowWater
+	Overheat
+	HeaterTimeout
+	SensorFailure
+	GrinderJam
+	PumpFailure
+)
+
+var faults = [...]struct{ code, text string }{
+	{"E00", "no fault"},
+	{"E01", "not enough beans"},
+	{"E02", "not enough water"},
+	{"E03", "boiler overheated"},
+	{"E04", "boiler did not reach temperature"},
+	{"E05", "temperature sensor failed"},
+	{"E06", "grinder jammed"},
+	{"E07", "pump failed"},
+}
+
+func (f Fault) Code() string {
+	if f >= 0 && int(f) < len(faults) {
+		return faults[f].code
+	}
+	return fmt.Sprintf("E%02d", int(f))
+}
+
+func (f Fault) String() string {
+	if f >= 0 && int(f) < len(faults) {
+		return faults[f].code + " " + faults[f].text
+	}
+	return fmt.Sprintf("Fault(%d)", int(f))
+}
+
+// === Driver ===
+
+// Levels is what's left in the hoppers
+type Levels struct {
+	Beans int // Grams
+	Water int // Millilitres
+}
+
+// Driver is the hardware. A real one talks to the boiler, grinder and
+// pump; SimDriver below models them.
+type Driver interface {
+	Temperature() (float64, error) // Boiler, °C
+	SetHeater(power float64)       // 0 (off) to 1 (full)
+	Grind(grams int) error
+	Pump(ml int, d time.Duration) error // Runs the pump for d
+	Levels() Levels
+	Refill()
+	Step(d time.Duration) // Lets simulated time pass; a real driver ignores it
+}
+
+var (
+	ErrJammed     = errors.New("grinder jammed")
+	ErrPumpFailed = errors.New("pump failed")
+)
+
+// SimDriver is a boiler that heats with the heater, loses heat to the
+// room, and is cooled by fresh water while the pump runs
+type SimDriver struct {
+	Temp    float64
+	Ambient float64
+	Noise   float64 // Sensor noise, °C
+
+	// Faults to inject
+	JamAfter      int     // Grinds before the grinder jams; 0 never
+	PumpFailAfter int     // Shots before the pump fails; 0 never
+	SensorDiesAt  int     // Reads before the sensor fails; 0 never
+	StuckHeater   float64 // If set, the heater ignores SetHeater and runs at this power
+
+	levels  Levels
+	power   float64
+	pumpMl  float64 // Per second, while pumping
+	pumpFor time.Duration
+	grinds  int
+	pumps   int
+	reads   int
+	rng     *rand.Rand
+}
+
+const (
+	beanCapacity  = 500
+	waterCapacity = 2000
+)
+
+func NewSimDriver(seed int64) *SimDriver {
+	return &SimDriver{
+		Temp:    20,
+		Ambient: 20,
+		Noise:   0.1,
+		levels:  Levels{Beans: beanCapacity, Water: waterCapacity},
+		rng:     rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (d *SimDriver) Temperature() (float64, error) {
+	d.reads++
+	if d.SensorDiesAt > 0 && d.reads >= d.SensorDiesAt {
+		return 0, errors.New("sensor not responding")
+	}
+	return d.Temp + d.rng.NormFloat64()*d.Noise, nil
+}
+
+func (d *SimDriver) SetHeater(power float64) {
+	d.power = math.Max(0, math.Min(1, power))
+}
+
+func (d *SimDriver) Grind(grams int) error {
+	d.grinds++
+	if d.JamAfter > 0 && d.grinds > d.JamAfter {
+		return ErrJammed
+	}
+	d.levels.Beans -= grams
+	return nil
+}
+
+func (d *SimDriver) Pump(ml int, dur time.Duration) error {
+	d.pumps++
+	if d.PumpFailAfter > 0 && d.pumps > d.PumpFailAfter {
+		return ErrPumpFailed
+	}
+	d.levels.Water -= ml
+	d.pumpMl = float64(ml) / dur.Seconds()
+	d.pumpFor = dur
+	return nil
+}
+
+func (d *SimDriver) Levels() Levels { return d.levels }
+
+func (d *SimDriver) Refill() {
+	d.levels = Levels{Beans: beanCapacity, Water: waterCapacity}
+	d.grinds, d.JamAfter = 0, 0
+	d.pumps, d.PumpFailAfter = 0, 0
+}
+
+func (d *SimDriver) Step(dt time.Duration) {
+	power := d.power
+	if d.StuckHeater > 0 {
+		power = d.StuckHeater
+	}
+	s := dt.Seconds()
+	d.Temp += 2.0 * power * s                 // 2°C a second at full power
+	d.Temp -= 0.01 * (d.Temp - d.Ambient) * s // Heat lost to the room
+	if d.pumpFor > 0 {
+		d.Temp -= 0.1 * d.pumpMl * s // Cold water in
+		d.pumpFor -= dt
+	}
+}
+
+// === Regulation ===
+
+// Regulator decides the heater power for a temperature reading
+type Regulator interface {
+	Power(setpoint, temp float64, dt time.Duration) float64
+}
+
+// PID regulates on how far off the temperature is (P), how long it's been
+// off (I) and how fast it's changing (D)
+type PID struct {
+	Kp, Ki, Kd float64
+
+	integral float64
+	last     float64
+	started  bool
+}
+
+func (p *PID) Power(setpoint, temp float64, dt time.Duration) float64 {
+	s := dt.Seconds()
+	err := setpoint - temp
+	derivative := 0.0
+	if p.started {
+		// On the measurement, not the error, so a new setpoint doesn't kick
+		derivative = -(temp - p.last) / s
+	}
+	p.last, p.started = temp, true
+
+	out := p.Kp*err + p.Ki*p.integral + p.Kd*derivative
+	// Anti-windup: stop integrating while the heater is flat out
+	if (out < 1 || err < 0) && (out > 0 || err > 0) {
+		p.integral += err * s
+	}
+	return math.Max(0, math.Min(1, out))
+}
+
+// Thermostat is the on/off rule from 08_infinite_loop.go: full heat below
+// the band, off above it
+type Thermostat struct {
+	Band float64
+	on   bool
+}
+
+func (t *Thermostat) Power(setpoint, temp float64, _ time.Duration) float64 {
+	switch {
+	case temp < setpoint-t.Band:
+		t.on = true
+	case temp > setpoint+t.Band:
+		t.on = false
+	}
+	if t.on {
+		return 1
+	}
+	return 0
+}
+
+// === Controller ===
+
+// Config is the recipe and the machine's limits
+type Config struct {
+	BrewTemp     float64 // °C at the group head
+	IdleTemp     float64 // °C kept between drinks
+	Tolerance    float64 // Close enough to brew
+	MaxTemp      float64 // Above this is a fault
+	HeatTimeout  time.Duration
+	Dose         int // Grams of coffee a drink
+	Water        int // Millilitres a drink
+	GrindTime    time.Duration
+	BrewTime     time.Duration
+	DispenseTime time.Duration
+	LowWarning   float64 // Warn when a hopper is below this fraction
+}
+
+func DefaultConfig() Config {
+	return Config{
+		BrewTemp:     93,
+		IdleTemp:     90,
+		Tolerance:    1,
+		MaxTemp:      105,
+		HeatTimeout:  3 * time.Minute,
+		Dose:         18,
+		Water:        60,
+		GrindTime:    8 * time.Second,
+		BrewTime:     25 * time.Second,
+		DispenseTime: 5 * time.Second,
+		LowWarning:   0.2,
+	}
+}
+
+type EventKind int
+
+const (
+	StateChanged EventKind = iota
+	Warning
+)
+
+// Event is something listeners are told about
+type Event struct {
+	At       time.Duration // Since power on
+	Kind     EventKind
+	From, To State
+	Fault    Fault
+	Message  string
+}
+
+func (e Event) String() string {
+	at := fmt.Sprintf("%5.0fs", e.At.Seconds())
+	switch {
+	case e.Kind == Warning:
+		return fmt.Sprintf("%s ⚠️  %s", at, e.Message)
+	case e.Fault != NoFault:
+		return fmt.Sprintf("%s %s → %s [%s]", at, e.From, e.To, e.Fault)
+	case e.Message != "":
+		return fmt.Sprintf("%s %s → %s (%s)", at, e.From, e.To, e.Message)
+	}
+	return fmt.Sprintf("%s %s → %s", at, e.From, e.To)
+}
+
+var (
+	ErrBusy        = errors.New("machine is busy")
+	ErrFaulted     = errors.New("machine is faulted")
+	ErrMaintenance = errors.New("machine is in maintenance")
+	ErrOff         = errors.New("machine is off")
+)
+
+// Telemetry is a snapshot for a status display
+type Telemetry struct {
+	State  State
+	Temp   float64
+	Heater float64
+	Levels Levels
+	Cups   int
+	Fault  Fault
+}
+
+// Controller runs one machine
+type Controller struct {
+	cfg       Config
+	driver    Driver
+	regulator Regulator
+	listeners []func(Event)
+
+	state   State
+	fault   Fault
+	clock   time.Duration
+	entered time.Duration // When the current state began
+	temp    float64
+	heater  float64
+	cups    int
+	warned  map[string]bool
+}
+
+const tick = time.Second
+
+func NewController(cfg Config, driver Driver, regulator Regulator) *Controller {
+	return &Controller{cfg: cfg, driver: driver, regulator: regulator, warned: map[string]bool{}}
+}
+
+// Listen registers fn to be called with every event, in order
+func (c *Controller) Listen(fn func(Event)) {
+	c.listeners = append(c.listeners, fn)
+}
+
+func (c *Controller) emit(e Event) {
+	e.At = c.clock
+	for _, fn := range c.listeners {
+		fn(e)
+	}
+}
+
+func (c *Controller) moveTo(to State, message string) {
+	if !canMove(c.state, to) {
+		panic(fmt.Sprintf("illegal transition %s → %s", c.state, to))
+	}
+	from := c.state
+	c.state, c.entered = to, c.clock
+	c.emit(Event{Kind: StateChanged, From: from, To: to, Message: message})
+}
+
+func (c *Controller) fail(f Fault) {
+	from := c.state
+	c.state, c.entered, c.fault = Faulted, c.clock, f
+	c.driver.SetHeater(0) // A faulted machine never heats
+	c.heater = 0
+	c.emit(Event{Kind: StateChanged, From: from, To: Faulted, Fault: f})
+}
+
+func (c *Controller) Telemetry() Telemetry {
+	return Telemetry{State: c.state, Temp: c.temp, Heater: c.heater, Levels: c.driver.Levels(), Cups: c.cups, Fault: c.fault}
+}
+
+// PowerOn starts warming up to the idle temperature
+func (c *Controller) PowerOn() error {
+	if c.state != Off {
+		return ErrBusy
+	}
+	c.moveTo(WarmingUp, "")
+	return nil
+}
+
+// Brew starts a drink. It fails unless the machine is idle.
+func (c *Controller) Brew() error {
+	switch c.state {
+	case Idle:
+		c.moveTo(CheckResources, "")
+		return nil
+	case Off:
+		return ErrOff
+	case Faulted:
+		return fmt.Errorf("%w: %s", ErrFaulted, c.fault)
+	case Maintenance:
+		return ErrMaintenance
+	}
+	return fmt.Errorf("%w: %s", ErrBusy, c.state)
+}
+
+// EnterMaintenance takes the machine out of service. It's allowed when
+// idle or faulted, never mid-drink.
+func (c *Controller) EnterMaintenance() error {
+	if c.state != Idle && c.state != Faulted && c.state != WarmingUp {
+		return fmt.Errorf("%w: %s", ErrBusy, c.state)
+	}
+	c.driver.SetHeater(0)
+	c.heater = 0
+	c.moveTo(Maintenance, "")
+	return nil
+}
+
+// Refill tops up beans and water and clears a jam. Only in maintenance.
+func (c *Controller) Refill() error {
+	if c.state != Maintenance {
+		return fmt.Errorf("refill: %w", ErrBusy)
+	}
+	c.driver.Refill()
+	c.warned = map[string]bool{}
+	return nil
+}
+
+// ExitMaintenance clears the fault and goes back into service
+func (c *Controller) ExitMaintenance() error {
+	if c.state != Maintenance {
+		return fmt.Errorf("exit maintenance: not in maintenance (%s)", c.state)
+	}
+	cleared := c.fault
+	c.fault = NoFault
+	message := ""
+	if cleared != NoFault {
+		message = "cleared " + cleared.Code()
+	}
+	c.moveTo(Idle, message)
+	return nil
+}
+
+// Step advances the machine by one tick
+func (c *Controller) Step() {
+	c.clock += tick
+	c.driver.Step(tick)
+
+	if c.state == Off || c.state == Faulted || c.state == Maintenance {
+		return
+	}
+
+	temp, err := c.driver.Temperature()
+	if err != nil {
+		c.fail(SensorFailure)
+		return
+	}
+	c.temp = temp
+	if temp > c.cfg.MaxTemp {
+		c.fail(Overheat)
+		return
+	}
+
+	setpoint := c.cfg.IdleTemp
+	if c.state >= CheckResources && c.state <= Dispensing {
+		setpoint = c.cfg.BrewTemp
+	}
+	c.heater = c.regulator.Power(setpoint, temp, tick)
+	c.driver.SetHeater(c.heater)
+
+	elapsed := c.clock - c.entered
+	switch c.state {
+	case WarmingUp:
+		if math.Abs(temp-c.cfg.IdleTemp) <= c.cfg.Tolerance {
+			c.moveTo(Idle, fmt.Sprintf("%.1f°C", temp))
+		} else if elapsed >= 2*c.cfg.HeatTimeout {
+			c.fail(HeaterTimeout)
+		}
+
+	case CheckResources:
+		levels := c.driver.Levels()
+		switch {
+		case levels.Beans < c.cfg.Dose:
+			c.fail(LowBeans)
+		case levels.Water < c.cfg.Water:
+			c.fail(LowWater)
+		case math.Abs(temp-c.cfg.BrewTemp) <= c.cfg.Tolerance:
+			c.startGrinding()
+		default:
+			c.moveTo(Heating, fmt.Sprintf("%.1f°C", temp))
+		}
+
+	case Heating:
+		if math.Abs(temp-c.cfg.BrewTemp) <= c.cfg.Tolerance {
+			c.startGrinding()
+		} else if elapsed >= c.cfg.HeatTimeout {
+			c.fail(HeaterTimeout)
+		}
+
+	case Grinding:
+		if elapsed >= c.cfg.GrindTime {
+			if err := c.driver.Pump(c.cfg.Water, c.cfg.BrewTime); err != nil {
+				c.fail(PumpFailure)
+				return
+			}
+			c.moveTo(Brewing, "")
+		}
+
+	case Brewing:
+		if elapsed >= c.cfg.BrewTime {
+			c.moveTo(Dispensing, "")
+		}
+
+	case Dispensing:
+		if elapsed >= c.cfg.DispenseTime {
+			c.cups++
+			c.moveTo(Idle, fmt.Sprintf("cup #%d", c.cups))
+			c.checkLevels()
+		}
+	}
+}
+
+func (c *Controller) startGrinding() {
+	if err := c.driver.Grind(c.cfg.Dose); err != nil {
+		c.fail(GrinderJam)
+		return
+	}
+	c.moveTo(Grinding, "")
+}
+
+// checkLevels warns once per refill when a hopper runs low
+func (c *Controller) checkLevels() {
+	levels := c.driver.Levels()
+	check := func(name string, left, capacity int, unit string) {
+		if float64(left) < c.cfg.LowWarning*float64(capacity) && !c.warned[name] {
+			c.warned[name] = true
+			c.emit(Event{Kind: Warning, Message: fmt.Sprintf("%s low: %d%s left", name, left, unit)})
+		}
+	}
+	check("beans", levels.Beans, beanCapacity, "g")
+	check("water", levels.Water, waterCapacity, "ml")
+}
+
+// RunUntil steps until the machine is in one of states, or limit passes.
+// It reports whether a wanted state was reached.
+func (c *Controller) RunUntil(limit time.Duration, states ...State) bool {
+	for end := c.clock + limit; c.clock < end; {
+		for _, s := range states {
+			if c.state == s {
+				return true
+			}
+		}
+		c.Step()
+	}
+	return false
+}
+
+// === Demo ===
+
+func newMachine(driver Driver, regulator Regulator, print bool) *Controller {
+	c := NewController(DefaultConfig(), driver, regulator)
+	if print {
+		c.Listen(func(e Event) { fmt.Printf("  %v\n", e) })
+	}
+	return c
+}
+
+func pid() *PID { return &PID{Kp: 0.15, Ki: 0.004, Kd: 0.2} }
+
+// makeCup brews one drink and waits for the machine to settle
+func makeCup(c *Controller) error {
+	if err := c.Brew(); err != nil {
+		return err
+	}
+	c.RunUntil(10*time.Minute, Idle, Faulted)
+	return nil
+}
+
+// brewStability runs a morning of drinks and reports how far from the
+// brew temperature the boiler strayed while brewing, and the heater's
+// switching (how often its power changed by more than half)
+func brewStability(regulator Regulator) (worst float64, switches int) {
+	driver := NewSimDriver(7)
+	driver.Noise = 0
+	c := newMachine(driver, regulator, false)
+	c.PowerOn()
+	c.RunUntil(30*time.Minute, Idle)
+
+	lastHeater := 0.0
+	for cup := 0; cup < 8; cup++ {
+		c.Brew()
+		for c.state != Idle && c.state != Faulted {
+			c.Step()
+			if c.state == Brewing {
+				worst = math.Max(worst, math.Abs(driver.Temp-c.cfg.BrewTemp))
+			}
+			if math.Abs(c.heater-lastHeater) > 0.5 {
+				switches++
+			}
+			lastHeater = c.heater
+		}
+		c.RunUntil(time.Minute) // A minute between drinks
+	}
+	return worst, switches
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Machine Controller ===")
+	fmt.Println()
+
+	// 1. Power on and make three drinks, printing every event
+	fmt.Println("1. Power on, then three drinks")
+	driver := NewSimDriver(1)
+	machine := newMachine(driver, pid(), true)
+	machine.PowerOn()
+	machine.RunUntil(10*time.Minute, Idle)
+	for i := 0; i < 3; i++ {
+		makeCup(machine)
+	}
+	t := machine.Telemetry()
+	fmt.Printf("  Telemetry: %s, %.1f°C, heater %.0f%%, %dg beans, %dml water, %d cups\n",
+		t.State, t.Temp, t.Heater*100, t.Levels.Beans, t.Levels.Water, t.Cups)
+
+	// 2. Requests the machine can't take right now are errors
+	fmt.Println("\n2. Busy and illegal requests")
+	machine.Brew()
+	fmt.Printf("  Brew while %s: %v\n", machine.state, machine.Brew())
+	fmt.Printf("  Maintenance mid-drink: %v\n", machine.EnterMaintenance())
+	machine.RunUntil(10*time.Minute, Idle)
+
+	// 3. PID against the on/off thermostat from 08_infinite_loop.go
+	fmt.Println("\n3. Boiler stability over eight drinks")
+	worst, switches := brewStability(pid())
+	fmt.Printf("  PID:        strays up to %.1f°C while brewing, %d big heater swings\n", worst, switches)
+	worst, switches = brewStability(&Thermostat{Band: 3})
+	fmt.Printf("  Thermostat: strays up to %.1f°C while brewing, %d big heater swings\n", worst, switches)
+
+	// 4. Running out of beans, then maintenance
+	fmt.Println("\n4. Running out of beans")
+	driver = NewSimDriver(2)
+	machine = newMachine(driver, pid(), false)
+	machine.PowerOn()
+	machine.RunUntil(10*time.Minute, Idle)
+	machine.Listen(func(e Event) {
+		if e.Kind == Warning || e.To == Faulted || e.From == Faulted || e.From == Maintenance {
+			fmt.Printf("  %v\n", e)
+		}
+	})
+	for machine.state != Faulted {
+		makeCup(machine)
+	}
+	err := machine.Brew()
+	fmt.Printf("  Brew now: %v (ErrFaulted: %t)\n", err, errors.Is(err, ErrFaulted))
+	machine.EnterMaintenance()
+	machine.Refill()
+	machine.ExitMaintenance()
+	makeCup(machine)
+	fmt.Printf("  Back in service: %s, %d cups, %dg beans\n", machine.state, machine.cups, driver.Levels().Beans)
+
+	// 5. Hardware faults cut the heater and wait for maintenance
+	fmt.Println("\n5. Hardware faults")
+	for _, scenario := range []struct {
+		name   string
+		inject func(*SimDriver)
+	}{
+		{"grinder jams on the 3rd drink", func(d *SimDriver) { d.JamAfter = 2 }},
+		{"pump fails on the 2nd drink", func(d *SimDriver) { d.PumpFailAfter = 1 }},
+		{"sensor dies mid-morning", func(d *SimDriver) { d.SensorDiesAt = 200 }},
+		{"heater relay sticks on", func(d *SimDriver) { d.StuckHeater = 1 }},
+		{"heater element burns out", func(d *SimDriver) { d.StuckHeater = 0.05 }},
+	} {
+		driver := NewSimDriver(3)
+		scenario.inject(driver)
+		machine := newMachine(driver, pid(), false)
+		machine.PowerOn()
+		machine.RunUntil(10*time.Minute, Idle, Faulted)
+		for i := 0; i < 5 && machine.state == Idle; i++ {
+			makeCup(machine)
+		}
+		t := machine.Telemetry()
+		fmt.Printf("  %-30s → %s after %d cups, heater %.0f%%\n", scenario.name+":", t.Fault, t.Cups, t.Heater*100)
+	}
+
+	var lines []string
+	for s := Off; s <= Maintenance; s++ {
+		var to []string
+		for _, next := range transitions[s] {
+			to = append(to, next.String())
+		}
+		lines = append(lines, fmt.Sprintf("  %-15s → %s", s, strings.Join(to, ", ")))
+	}
+	fmt.Println("\nAllowed transitions (any running state may also fault):")
+	fmt.Println(strings.Join(lines, "\n"))
+}
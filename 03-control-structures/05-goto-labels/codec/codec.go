@@ -0,0 +1,512 @@
+// Package codec reads and writes the "KEY:value,KEY:value" order format.
+//
+// parseOrderFormat in 06_legitimate_uses.go reads "KEY:value,KEY:value"
+// with goto and string concatenation. A value can't hold a comma or a
+// colon, a repeated key overwrites the first, and any key is accepted.
+// This package is the same wire format done properly:
+//
+//	record = [ field { "," field } ] .
+//	field  = key ":" value .
+//	key    = letter { letter | digit | "_" } .
+//	value  = bare | quoted .
+//
+// A bare value runs to the next comma and can't contain , : " or \.
+// Anything else is quoted, with \" \\ \n \r \t escapes inside the quotes:
+//
+//	NAME:"Smith, Jane",NOTE:"pickup at 10:30",EXTRAS:Oat Milk,EXTRAS:Vanilla
+//
+// Spaces around keys and bare values are ignored. A key that appears more
+// than once is a list, and a Schema says which keys must be there, which
+// may repeat and what type each value has.
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// === Records ===
+
+// Record is a decoded message: each key's values, in the order the keys
+// first appeared
+type Record struct {
+	keys   []string
+	values map[string][]string
+}
+
+// Add appends a value to key, making it a list if it's already there
+func (r *Record) Add(key, value string) {
+	if r.values == nil {
+		r.values = map[string][]string{}
+	}
+	if _, ok := r.values[key]; !ok {
+		r.keys = append(r.keys, key)
+	}
+	r.values[key] = append(r.values[key], value)
+}
+
+// Get returns key's first value
+func (r Record) Get(key string) (string, bool) {
+	values := r.values[key]
+	if len(values) == 0 {
+		return "", false
+	}
+	return values[0], true
+}
+
+// List returns every value of key
+func (r Record) List(key string) []string {
+	return r.values[key]
+}
+
+func (r Record) Keys() []string {
+	return r.keys
+}
+
+// Equal compares keys, their order and their values
+func (r Record) Equal(other Record) bool {
+	if len(r.keys) != len(other.keys) {
+		return false
+	}
+	for i, key := range r.keys {
+		if other.keys[i] != key || !reflect.DeepEqual(r.values[key], other.values[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+// === Decoding ===
+
+// SyntaxError is a malformed message and the byte offset of the problem
+type SyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.Offset, e.Msg)
+}
+
+type decoder struct {
+	s   string
+	pos int
+}
+
+func (d *decoder) eof() bool { return d.pos >= len(d.s) }
+
+func (d *decoder) skipSpace() {
+	for !d.eof() && isSpace(d.s[d.pos]) {
+		d.pos++
+	}
+}
+
+func (d *decoder) errorf(offset int, format string, args ...any) error {
+	return &SyntaxError{Offset: offset, Msg: fmt.Sprintf(format, args...)}
+}
+
+// near shows what's at offset, for error messages
+func (d *decoder) near(offset int) string {
+	if offset >= len(d.s) {
+		return "end of input"
+	}
+	return strconv.QuoteRune(rune(d.s[offset]))
+}
+
+// Decode parses a message. It never panics, whatever the input; see
+// FuzzDecode.
+func Decode(s string) (Record, error) {
+	d := &decoder{s: s}
+	var r Record
+
+	d.skipSpace()
+	if d.eof() {
+		return r, nil
+	}
+
+	for {
+		key, err := d.key()
+		if err != nil {
+			return Record{}, err
+		}
+
+		d.skipSpace()
+		if d.eof() || d.s[d.pos] != ':' {
+			return Record{}, d.errorf(d.pos, "expected ':' after key %s, found %s", key, d.near(d.pos))
+		}
+		d.pos++
+		d.skipSpace()
+
+		value, err := d.value()
+		if err != nil {
+			return Record{}, err
+		}
+		r.Add(key, value)
+
+		d.skipSpace()
+		if d.eof() {
+			return r, nil
+		}
+		if d.s[d.pos] != ',' {
+			return Record{}, d.errorf(d.pos, "expected ',' after value of %s, found %s", key, d.near(d.pos))
+		}
+		d.pos++
+		d.skipSpace()
+	}
+}
+
+func (d *decoder) key() (string, error) {
+	start := d.pos
+	if d.eof() || !isLetter(d.s[d.pos]) {
+		return "", d.errorf(d.pos, "expected a key, found %s", d.near(d.pos))
+	}
+	for !d.eof() && isKeyByte(d.s[d.pos]) {
+		d.pos++
+	}
+	return d.s[start:d.pos], nil
+}
+
+func (d *decoder) value() (string, error) {
+	if !d.eof() && d.s[d.pos] == '"' {
+		return d.quoted()
+	}
+
+	start := d.pos
+	for !d.eof() && d.s[d.pos] != ',' {
+		switch c := d.s[d.pos]; c {
+		case ':', '"', '\\':
+			return "", d.errorf(d.pos, "%q in a bare value; put the value in quotes", c)
+		}
+		d.pos++
+	}
+
+	end := d.pos
+	for end > start && isSpace(d.s[end-1]) {
+		end--
+	}
+	return d.s[start:end], nil
+}
+
+var unescape = map[byte]byte{'"': '"', '\\': '\\', 'n': '\n', 'r': '\r', 't': '\t'}
+
+func (d *decoder) quoted() (string, error) {
+	open := d.pos
+	d.pos++ // Opening quote
+
+	var b strings.Builder
+	for !d.eof() {
+		c := d.s[d.pos]
+		switch c {
+		case '"':
+			d.pos++
+			return b.String(), nil
+		case '\\':
+			if d.pos+1 >= len(d.s) {
+				return "", d.errorf(d.pos, "unfinished escape at end of input")
+			}
+			e, ok := unescape[d.s[d.pos+1]]
+			if !ok {
+				return "", d.errorf(d.pos, "unknown escape \\%c", d.s[d.pos+1])
+			}
+			b.WriteByte(e)
+			d.pos += 2
+		default:
+			b.WriteByte(c)
+			d.pos++
+		}
+	}
+	return "", d.errorf(open, "quoted value never closed")
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isKeyByte(c byte) bool {
+	return isLetter(c) || (c >= '0' && c <= '9') || c == '_'
+}
+
+// === Encoding ===
+
+// ErrBadKey is returned by Encode for a key Decode couldn't read back
+var ErrBadKey = errors.New("invalid key")
+
+// Encode writes a record so that Decode gives it back unchanged. Values
+// are bare when they can be and quoted when they must be.
+func Encode(r Record) (string, error) {
+	var b strings.Builder
+	for _, key := range r.keys {
+		if !validKey(key) {
+			return "", fmt.Errorf("%w %q", ErrBadKey, key)
+		}
+		for _, value := range r.values[key] {
+			if b.Len() > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(key)
+			b.WriteByte(':')
+			writeValue(&b, value)
+		}
+	}
+	return b.String(), nil
+}
+
+func validKey(key string) bool {
+	if key == "" || !isLetter(key[0]) {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if !isKeyByte(key[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// needsQuotes reports whether a bare value would decode differently
+func needsQuotes(value string) bool {
+	if value == "" || isSpace(value[0]) || isSpace(value[len(value)-1]) {
+		return true
+	}
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c < ' ' || c == ',' || c == ':' || c == '"' || c == '\\' {
+			return true
+		}
+	}
+	return false
+}
+
+func writeValue(b *strings.Builder, value string) {
+	if !needsQuotes(value) {
+		b.WriteString(value)
+		return
+	}
+	b.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; c {
+		case '"', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+}
+
+// === Schema ===
+
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Bool
+	Money // "4.50" or "4"
+)
+
+func (t Type) String() string {
+	return [...]string{"string", "int", "bool", "money"}[t]
+}
+
+// Field declares one key of a message
+type Field struct {
+	Key      string
+	Type     Type
+	Required bool
+	List     bool     // May appear more than once
+	OneOf    []string // Allowed values, if not empty
+}
+
+// Schema is the keys a message may have; any other key is an error
+type Schema []Field
+
+// ValidationError lists every problem with a record, not just the first
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid order: %s", strings.Join(e.Problems, "; "))
+}
+
+// Validate checks r against the schema
+func (s Schema) Validate(r Record) error {
+	var problems []string
+	known := map[string]bool{}
+
+	for _, field := range s {
+		known[field.Key] = true
+		values := r.List(field.Key)
+
+		switch {
+		case len(values) == 0 && field.Required:
+			problems = append(problems, fmt.Sprintf("missing required key %s", field.Key))
+		case len(values) > 1 && !field.List:
+			problems = append(problems, fmt.Sprintf("%s given %d times", field.Key, len(values)))
+		}
+
+		for _, value := range values {
+			if err := field.check(value); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", field.Key, err))
+			}
+		}
+	}
+
+	for _, key := range r.Keys() {
+		if !known[key] {
+			problems = append(problems, fmt.Sprintf("unknown key %s", key))
+		}
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func (f Field) check(value string) error {
+	var err error
+	switch f.Type {
+	case Int:
+		_, err = strconv.Atoi(value)
+	case Bool:
+		_, err = strconv.ParseBool(value)
+	case Money:
+		_, err = parseMoney(value)
+	}
+	if err != nil {
+		return fmt.Errorf("%q is not a valid %s", value, f.Type)
+	}
+
+	if len(f.OneOf) > 0 {
+		for _, allowed := range f.OneOf {
+			if value == allowed {
+				return nil
+			}
+		}
+		return fmt.Errorf("%q is not one of %s", value, strings.Join(f.OneOf, ", "))
+	}
+	return nil
+}
+
+type Cents int64
+
+func (c Cents) String() string {
+	return fmt.Sprintf("%d.%02d", c/100, c%100)
+}
+
+// parseMoney reads "4", "4.5" or "4.50" exactly, without floats
+func parseMoney(s string) (Cents, error) {
+	whole, frac, hasFrac := strings.Cut(s, ".")
+	if whole == "" || len(frac) > 2 || (hasFrac && frac == "") {
+		return 0, fmt.Errorf("bad amount %q", s)
+	}
+	for len(frac) < 2 {
+		frac += "0"
+	}
+	digits := whole + frac
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return 0, fmt.Errorf("bad amount %q", s)
+		}
+	}
+	n, err := strconv.ParseInt(digits, 10, 64)
+	return Cents(n), err
+}
+
+// === Orders ===
+
+var orderSchema = Schema{
+	{Key: "COFFEE", Type: String, Required: true, OneOf: []string{"Espresso", "Americano", "Latte", "Cappuccino", "Mocha"}},
+	{Key: "SIZE", Type: String, Required: true, OneOf: []string{"Small", "Medium", "Large"}},
+	{Key: "QTY", Type: Int},
+	{Key: "EXTRAS", Type: String, List: true},
+	{Key: "NAME", Type: String},
+	{Key: "NOTE", Type: String},
+	{Key: "PRICE", Type: Money},
+	{Key: "TOGO", Type: Bool},
+}
+
+type Order struct {
+	Coffee   string
+	Size     string
+	Quantity int
+	Extras   []string
+	Name     string
+	Note     string
+	Price    Cents
+	ToGo     bool
+}
+
+// DecodeOrder decodes and validates one order message
+func DecodeOrder(s string) (Order, error) {
+	r, err := Decode(s)
+	if err != nil {
+		return Order{}, err
+	}
+	if err := orderSchema.Validate(r); err != nil {
+		return Order{}, err
+	}
+
+	// Validate checked every type, so the conversions can't fail
+	o := Order{Quantity: 1, Extras: r.List("EXTRAS")}
+	o.Coffee, _ = r.Get("COFFEE")
+	o.Size, _ = r.Get("SIZE")
+	o.Name, _ = r.Get("NAME")
+	o.Note, _ = r.Get("NOTE")
+	if v, ok := r.Get("QTY"); ok {
+		o.Quantity, _ = strconv.Atoi(v)
+	}
+	if v, ok := r.Get("PRICE"); ok {
+		o.Price, _ = parseMoney(v)
+	}
+	if v, ok := r.Get("TOGO"); ok {
+		o.ToGo, _ = strconv.ParseBool(v)
+	}
+	return o, nil
+}
+
+// EncodeOrder writes an order, leaving out optional fields at their zero
+// value (and QTY at 1)
+func EncodeOrder(o Order) (string, error) {
+	var r Record
+	r.Add("COFFEE", o.Coffee)
+	r.Add("SIZE", o.Size)
+	if o.Quantity != 1 {
+		r.Add("QTY", strconv.Itoa(o.Quantity))
+	}
+	for _, extra := range o.Extras {
+		r.Add("EXTRAS", extra)
+	}
+	if o.Name != "" {
+		r.Add("NAME", o.Name)
+	}
+	if o.Note != "" {
+		r.Add("NOTE", o.Note)
+	}
+	if o.Price != 0 {
+		r.Add("PRICE", o.Price.String())
+	}
+	if o.ToGo {
+		r.Add("TOGO", "true")
+	}
+
+	if err := orderSchema.Validate(r); err != nil {
+		return "", err
+	}
+	return Encode(r)
+}
@@ -0,0 +1,145 @@
+package codec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// FuzzDecode checks that Decode never panics and that every record it
+// accepts survives Decode → Encode → Decode unchanged.
+//
+//	go test -fuzz=FuzzDecode -fuzztime=30s ./codec
+func FuzzDecode(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"COFFEE:Latte,SIZE:Large,EXTRAS:ExtraShot",
+		`COFFEE:Mocha,SIZE:Medium,QTY:2,EXTRAS:Oat Milk,EXTRAS:Vanilla,NAME:"Smith, Jane",NOTE:"pickup at 10:30, say \"hi\"",PRICE:11.50,TOGO:true`,
+		`A:"\\\"",B:x`,
+		`NOTE:"line\nbreak\ttab\rreturn"`,
+		"  COFFEE : Latte ,  SIZE:Large  ",
+		`A:""`,
+		"COFFEE:Latte,SIZE",
+		"COFFEE:Latte,,SIZE:Large",
+		`NAME:"Smith, Jane`,
+		`NOTE:"tab\x"`,
+		"K:\x00\xff",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		r, err := Decode(input)
+		if err != nil {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Decode(%q) returned %T, want *SyntaxError", input, err)
+			}
+			if syntaxErr.Offset < 0 || syntaxErr.Offset > len(input) {
+				t.Fatalf("Decode(%q): offset %d is outside the input", input, syntaxErr.Offset)
+			}
+			return
+		}
+
+		encoded, err := Encode(r)
+		if err != nil {
+			t.Fatalf("Decode(%q) accepted a record Encode rejects: %v", input, err)
+		}
+		again, err := Decode(encoded)
+		if err != nil {
+			t.Fatalf("Decode(%q) re-encoded as %q, which doesn't decode: %v", input, encoded, err)
+		}
+		if !again.Equal(r) {
+			t.Fatalf("Decode(%q) re-encoded as %q, which decodes differently", input, encoded)
+		}
+	})
+}
+
+func TestEncodeQuotesOnlyWhenNeeded(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Latte", "K:Latte"},
+		{"Oat Milk", "K:Oat Milk"},
+		{"", `K:""`},
+		{" padded", `K:" padded"`},
+		{"Smith, Jane", `K:"Smith, Jane"`},
+		{"10:30", `K:"10:30"`},
+		{`say "hi"`, `K:"say \"hi\""`},
+		{`C:\`, `K:"C:\\"`},
+		{"two\nlines", `K:"two\nlines"`},
+	}
+	for _, tt := range tests {
+		var r Record
+		r.Add("K", tt.value)
+		got, err := Encode(r)
+		if err != nil {
+			t.Fatalf("Encode(%q): %v", tt.value, err)
+		}
+		if got != tt.want {
+			t.Errorf("Encode(%q) = %s, want %s", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestEncodeRejectsBadKeys(t *testing.T) {
+	for _, key := range []string{"", "1A", "A B", "A:B", "Ä"} {
+		var r Record
+		r.Add(key, "x")
+		if _, err := Encode(r); !errors.Is(err, ErrBadKey) {
+			t.Errorf("Encode with key %q: err = %v, want ErrBadKey", key, err)
+		}
+	}
+}
+
+func TestOrderRoundTrip(t *testing.T) {
+	orders := []Order{
+		{Coffee: "Latte", Size: "Large", Quantity: 1, Extras: []string{"ExtraShot"}},
+		{
+			Coffee:   "Mocha",
+			Size:     "Medium",
+			Quantity: 2,
+			Extras:   []string{"Oat Milk", "Vanilla"},
+			Name:     "Smith, Jane",
+			Note:     `pickup at 10:30, say "hi"`,
+			Price:    1150,
+			ToGo:     true,
+		},
+	}
+	for _, order := range orders {
+		encoded, err := EncodeOrder(order)
+		if err != nil {
+			t.Fatalf("EncodeOrder(%+v): %v", order, err)
+		}
+		decoded, err := DecodeOrder(encoded)
+		if err != nil {
+			t.Fatalf("DecodeOrder(%q): %v", encoded, err)
+		}
+		if !reflect.DeepEqual(order, decoded) {
+			t.Errorf("round trip through %q\ngot:  %+v\nwant: %+v", encoded, decoded, order)
+		}
+	}
+}
+
+func TestDecodeOrderValidates(t *testing.T) {
+	tests := []struct {
+		input    string
+		problems int
+	}{
+		{"COFFEE:Latte", 1},
+		{"COFFEE:Tea,SIZE:Large,SIZE:Small,QTY:two", 3},
+		{"COFFEE:Latte,SIZE:Large,PRICE:4.555,COLOR:Red", 2},
+	}
+	for _, tt := range tests {
+		_, err := DecodeOrder(tt.input)
+		var invalid *ValidationError
+		if !errors.As(err, &invalid) {
+			t.Errorf("DecodeOrder(%q) = %v, want a *ValidationError", tt.input, err)
+			continue
+		}
+		if len(invalid.Problems) != tt.problems {
+			t.Errorf("DecodeOrder(%q) found %d problems, want %d: %v", tt.input, len(invalid.Problems), tt.problems, invalid.Problems)
+		}
+	}
+}
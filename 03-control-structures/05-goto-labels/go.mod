@@ -1,3 +1,7 @@
 module goto-labels-examples
 
-go 1.21
\ No newline at end of file
+go 1.21
+
+require switch-examples v0.0.0
+
+replace switch-examples => ../02-switch
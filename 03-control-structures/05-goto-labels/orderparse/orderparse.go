@@ -0,0 +1,693 @@
+// Package orderparse reads free-text orders like "2 large oat lattes,
+// extra shot; 1 croissant warmed". It has a lexer, a recursive-descent
+// parser and a menu, loaded from the switch chapter's catalog, to resolve
+// names against.
+//
+//	order    = item { ";" item } .
+//	item     = [ quantity ] { size | modifier } name { [ "," ] modifier } .
+//	quantity = number | "a" | "an" | "one" | ... | "six" .
+//
+// Names, sizes and modifiers can be several words ("pumpkin spice latte",
+// "extra shot"); the parser always takes the longest phrase that matches.
+// A mistake is reported with its column and the closest menu words, then
+// the parser skips the bad word and keeps going, so one typo doesn't hide
+// the rest of the order.
+package orderparse
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"switch-examples/catalog"
+)
+
+// Cents is the catalog's price type
+type Cents = catalog.Cents
+
+// === Menu ===
+
+// MenuItem is a catalog item as the parser sees it
+type MenuItem struct {
+	Name   string
+	Drink  bool
+	Sizes  []string // Cheapest first
+	Prices []Cents  // Prices[i] is the price of Sizes[i]
+}
+
+// FromCatalog builds the parser's menu from a catalog. Items in
+// drinkCategories take drink modifiers; the rest take food ones. Every
+// item is included, available or not: whether it can be sold now is the
+// catalog's question, not the parser's.
+func FromCatalog(c *catalog.Catalog, drinkCategories ...string) []MenuItem {
+	drinks := map[string]bool{}
+	for _, id := range drinkCategories {
+		drinks[id] = true
+	}
+
+	menu := make([]MenuItem, 0, len(c.Items))
+	for _, item := range c.Items {
+		m := MenuItem{Name: item.Name, Drink: drinks[item.Category], Sizes: item.Sizes()}
+		for _, size := range m.Sizes {
+			m.Prices = append(m.Prices, item.Prices[size])
+		}
+		menu = append(menu, m)
+	}
+	return menu
+}
+
+func (m *MenuItem) Price(size string) (Cents, bool) {
+	for i, s := range m.Sizes {
+		if s == size {
+			return m.Prices[i], true
+		}
+	}
+	return 0, false
+}
+
+// DefaultSize is what "a latte" means: Medium if there is one
+func (m *MenuItem) DefaultSize() string {
+	if _, ok := m.Price("Medium"); ok {
+		return "Medium"
+	}
+	return m.Sizes[0]
+}
+
+// Sizes people say, and the menu size they mean
+var sizeWords = map[string]string{
+	"small": "Small", "tall": "Small",
+	"medium": "Medium", "grande": "Medium",
+	"large": "Large", "venti": "Large",
+	"solo": "Solo", "single": "Solo",
+	"doppio": "Doppio", "double": "Doppio",
+	"regular": "Regular",
+}
+
+type Modifier struct {
+	Name    string
+	Phrases []string // How it's written in an order
+	Price   Cents
+	Drink   bool   // For drinks; otherwise for food
+	Group   string // At most one modifier per group, e.g. one milk
+}
+
+var modifiers = []Modifier{
+	{"Extra Shot", []string{"extra shot"}, 50, true, ""},
+	{"Decaf", []string{"decaf"}, 0, true, ""},
+	{"Vanilla Syrup", []string{"vanilla", "vanilla syrup"}, 75, true, ""},
+	{"Oat Milk", []string{"oat", "oat milk"}, 60, true, "milk"},
+	{"Almond Milk", []string{"almond", "almond milk"}, 60, true, "milk"},
+	{"Skim Milk", []string{"skim", "skim milk"}, 0, true, "milk"},
+	{"No Foam", []string{"no foam"}, 0, true, ""},
+	{"Extra Hot", []string{"extra hot"}, 0, true, ""},
+	{"Warmed", []string{"warm", "warmed", "heated"}, 0, false, ""},
+	{"Toasted", []string{"toasted"}, 0, false, ""},
+}
+
+var numberWords = map[string]int{
+	"a": 1, "an": 1, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+}
+
+// Words that join parts of an order but mean nothing themselves
+var connectors = map[string]bool{"and": true, "with": true, "plus": true}
+
+const maxQuantity = 20
+
+// === Vocabulary ===
+
+type phraseKind int
+
+const (
+	kindItem phraseKind = iota
+	kindSize
+	kindModifier
+)
+
+// entry is what a phrase means
+type entry struct {
+	kind     phraseKind
+	item     *MenuItem
+	size     string
+	modifier *Modifier
+}
+
+func (e entry) name() string {
+	switch e.kind {
+	case kindItem:
+		return e.item.Name
+	case kindSize:
+		return e.size
+	}
+	return e.modifier.Name
+}
+
+// maxWords is the longest phrase, "pumpkin spice latte(s)"
+const maxWords = 3
+
+// Parser reads orders against one menu
+type Parser struct {
+	// vocabulary maps every lowercase phrase the parser knows to its
+	// meaning. Items are there in the plural too: "2 lattes",
+	// "2 sandwiches".
+	vocabulary map[string]entry
+}
+
+// New returns a parser for menu, usually built with FromCatalog
+func New(menu []MenuItem) *Parser {
+	return &Parser{vocabulary: buildVocabulary(menu)}
+}
+
+func buildVocabulary(menu []MenuItem) map[string]entry {
+	vocab := map[string]entry{}
+	for i := range menu {
+		item := &menu[i]
+		name := strings.ToLower(item.Name)
+		vocab[name] = entry{kind: kindItem, item: item}
+		vocab[plural(name)] = entry{kind: kindItem, item: item}
+	}
+	for word, size := range sizeWords {
+		vocab[word] = entry{kind: kindSize, size: size}
+	}
+	for i := range modifiers {
+		for _, phrase := range modifiers[i].Phrases {
+			vocab[phrase] = entry{kind: kindModifier, modifier: &modifiers[i]}
+		}
+	}
+	return vocab
+}
+
+func plural(name string) string {
+	switch {
+	case strings.HasSuffix(name, "ch"), strings.HasSuffix(name, "sh"), strings.HasSuffix(name, "s"):
+		return name + "es"
+	case strings.HasSuffix(name, "y"):
+		return name[:len(name)-1] + "ies"
+	}
+	return name + "s"
+}
+
+// === Lexer ===
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokWord
+	tokNumber
+	tokComma
+	tokSemicolon
+	tokIllegal
+)
+
+// span is a byte range of the input
+type span struct {
+	pos, end int
+}
+
+type token struct {
+	kind tokenKind
+	text string // Lowercased for words
+	span
+}
+
+// lex splits an order into tokens. It never fails: characters it doesn't
+// understand become tokIllegal and the parser reports them.
+func lex(input string) []token {
+	var tokens []token
+	pos := 0
+	for pos < len(input) {
+		r, size := utf8.DecodeRuneInString(input[pos:])
+		start := pos
+
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+			continue
+		case r == ',':
+			pos += size
+			tokens = append(tokens, token{tokComma, ",", span{start, pos}})
+		case r == ';':
+			pos += size
+			tokens = append(tokens, token{tokSemicolon, ";", span{start, pos}})
+		case r >= '0' && r <= '9':
+			// ASCII only: "２" isn't something strconv can read, so it's
+			// left to the default case and reported
+			for pos < len(input) && input[pos] >= '0' && input[pos] <= '9' {
+				pos++
+			}
+			tokens = append(tokens, token{tokNumber, input[start:pos], span{start, pos}})
+		case unicode.IsLetter(r):
+			// Letters, with - and ' allowed inside a word ("half-caf")
+			for pos < len(input) {
+				r, size := utf8.DecodeRuneInString(input[pos:])
+				inner := (r == '-' || r == '\'') && pos > start
+				if !unicode.IsLetter(r) && !inner {
+					break
+				}
+				pos += size
+			}
+			tokens = append(tokens, token{tokWord, strings.ToLower(input[start:pos]), span{start, pos}})
+		default:
+			pos += size
+			tokens = append(tokens, token{tokIllegal, input[start:pos], span{start, pos}})
+		}
+	}
+	return append(tokens, token{tokEOF, "", span{len(input), len(input)}})
+}
+
+// === Errors ===
+
+// ParseError is one problem in an order, with where it is and what the
+// customer might have meant
+type ParseError struct {
+	Pos, End    int // Byte offsets into the input
+	Column      int // 1-based, counted in characters
+	Msg         string
+	Suggestions []string
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("col %d: %s", e.Column, e.Msg)
+	if len(e.Suggestions) > 0 {
+		quoted := make([]string, len(e.Suggestions))
+		for i, s := range e.Suggestions {
+			quoted[i] = strconv.Quote(s)
+		}
+		msg += fmt.Sprintf(" (did you mean %s?)", strings.Join(quoted, " or "))
+	}
+	return msg
+}
+
+// Caret underlines the error in the input
+func (e *ParseError) Caret(input string) string {
+	width := utf8.RuneCountInString(input[e.Pos:e.End])
+	if width == 0 {
+		width = 1
+	}
+	return input + "\n" + strings.Repeat(" ", e.Column-1) + strings.Repeat("^", width)
+}
+
+// ParseErrors is every problem found in one order
+type ParseErrors []*ParseError
+
+func (list ParseErrors) Error() string {
+	switch len(list) {
+	case 0:
+		return "no errors"
+	case 1:
+		return list[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", list[0], len(list)-1)
+}
+
+// === Parser ===
+
+type OrderLine struct {
+	Quantity  int
+	Size      string
+	Item      *MenuItem
+	Modifiers []*Modifier
+}
+
+// Each is the price of one, modifiers included
+func (l OrderLine) Each() Cents {
+	price, _ := l.Item.Price(l.Size)
+	for _, m := range l.Modifiers {
+		price += m.Price
+	}
+	return price
+}
+
+func (l OrderLine) Total() Cents {
+	return l.Each() * Cents(l.Quantity)
+}
+
+func (l OrderLine) String() string {
+	label := l.Item.Name
+	if l.Size != "Regular" {
+		label = l.Size + " " + label
+	}
+	for _, m := range l.Modifiers {
+		label += " + " + m.Name
+	}
+	return fmt.Sprintf("%d × %s", l.Quantity, label)
+}
+
+type Order struct {
+	Lines []OrderLine
+}
+
+func (o Order) Total() Cents {
+	var total Cents
+	for _, line := range o.Lines {
+		total += line.Total()
+	}
+	return total
+}
+
+// parser is the state of one Parse call
+type parser struct {
+	*Parser
+	input  string
+	tokens []token
+	pos    int
+	errs   ParseErrors
+}
+
+// Parse parses a free-text order. The lines it could make sense of are
+// returned even when there are errors; the error is ParseErrors.
+func (ps *Parser) Parse(input string) (Order, error) {
+	p := &parser{Parser: ps, input: input, tokens: lex(input)}
+	var order Order
+
+	for p.peek().kind != tokEOF {
+		if p.peek().kind == tokSemicolon {
+			p.next()
+			continue
+		}
+		if line, ok := p.parseItem(); ok {
+			order.Lines = append(order.Lines, line)
+		}
+	}
+
+	if len(order.Lines) == 0 && len(p.errs) == 0 {
+		p.errorf(p.peek().span, nil, "empty order")
+	}
+	if len(p.errs) > 0 {
+		return order, p.errs
+	}
+	return order, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+// words returns up to maxWords word tokens starting at the current one
+func (p *parser) words() []string {
+	var words []string
+	for i := p.pos; i < len(p.tokens) && p.tokens[i].kind == tokWord && len(words) < maxWords; i++ {
+		words = append(words, p.tokens[i].text)
+	}
+	return words
+}
+
+// phrase finds the longest known phrase at the current token and returns
+// its meaning and how many words it uses
+func (p *parser) phrase() (entry, int, bool) {
+	words := p.words()
+	for n := len(words); n > 0; n-- {
+		if e, ok := p.vocabulary[strings.Join(words[:n], " ")]; ok {
+			return e, n, true
+		}
+	}
+	return entry{}, 0, false
+}
+
+// take consumes n tokens and returns the span they cover
+func (p *parser) take(n int) span {
+	s := span{p.peek().pos, p.peek().end}
+	for i := 0; i < n; i++ {
+		s.end = p.next().end
+	}
+	return s
+}
+
+func (p *parser) errorf(s span, suggestions []string, format string, args ...any) {
+	p.errs = append(p.errs, &ParseError{
+		Pos:         s.pos,
+		End:         s.end,
+		Column:      utf8.RuneCountInString(p.input[:s.pos]) + 1,
+		Msg:         fmt.Sprintf(format, args...),
+		Suggestions: suggestions,
+	})
+}
+
+// startsQuantity reports whether the current token could begin an item
+func (p *parser) startsQuantity() bool {
+	t := p.peek()
+	return t.kind == tokNumber || (t.kind == tokWord && numberWords[t.text] > 0)
+}
+
+// modifierUse remembers where a modifier was written, for errors
+type modifierUse struct {
+	modifier *Modifier
+	at       span
+}
+
+// parseItem parses one item up to the next ";" (or the start of the next
+// item). ok is false if the item had errors; they are in p.errs.
+func (p *parser) parseItem() (line OrderLine, ok bool) {
+	line.Quantity = 1
+	bad := false
+	var sizeAt span
+	var uses []modifierUse
+
+	// size and modifier are shared by both halves of the item
+	addSize := func(size string, at span) {
+		if line.Size != "" {
+			p.errorf(at, nil, "size given twice (%s and %s)", line.Size, size)
+			bad = true
+			return
+		}
+		line.Size, sizeAt = size, at
+	}
+
+	if p.startsQuantity() {
+		t := p.next()
+		n, known := numberWords[t.text]
+		if !known {
+			n, _ = strconv.Atoi(t.text) // Overflow gives 0, out of range below
+		}
+		if n < 1 || n > maxQuantity {
+			p.errorf(t.span, nil, "quantity %s is out of range 1-%d", t.text, maxQuantity)
+			bad = true
+		}
+		line.Quantity = n
+	}
+
+	// Before the name: sizes and modifiers ("large oat")
+Name:
+	for line.Item == nil {
+		t := p.peek()
+		switch t.kind {
+		case tokEOF, tokSemicolon, tokComma:
+			if !bad {
+				p.errorf(t.span, nil, "expected a menu item")
+			}
+			bad = true
+			break Name
+		case tokNumber, tokIllegal:
+			p.errorf(t.span, nil, "unexpected %q", t.text)
+			p.next()
+			bad = true
+		case tokWord:
+			e, n, found := p.phrase()
+			if !found {
+				p.unknown()
+				bad = true
+				continue
+			}
+			at := p.take(n)
+			switch e.kind {
+			case kindItem:
+				line.Item = e.item
+			case kindSize:
+				addSize(e.size, at)
+			case kindModifier:
+				uses = append(uses, modifierUse{e.modifier, at})
+			}
+		}
+	}
+
+	// After the name: modifiers, with or without commas ("warmed",
+	// ", extra shot, no foam")
+	for {
+		t := p.peek()
+		switch t.kind {
+		case tokEOF, tokSemicolon:
+			return p.finish(line, sizeAt, uses, bad)
+		case tokComma:
+			p.next()
+		case tokIllegal:
+			p.errorf(t.span, nil, "unexpected %q", t.text)
+			p.next()
+			bad = true
+		case tokNumber:
+			// "2 lattes 1 croissant": report it and carry on as if the
+			// ";" were there
+			p.errorf(t.span, nil, "expected \";\" before %q", t.text)
+			return p.finish(line, sizeAt, uses, bad)
+		case tokWord:
+			if connectors[t.text] {
+				p.next()
+				if p.startsQuantity() {
+					// "and a croissant" starts the next item
+					return p.finish(line, sizeAt, uses, bad)
+				}
+				continue
+			}
+
+			e, n, found := p.phrase()
+			if !found {
+				p.unknown()
+				bad = true
+				continue
+			}
+			if e.kind == kindItem && line.Item != nil {
+				p.errorf(t.span, nil, "expected \";\" before %q", t.text)
+				return p.finish(line, sizeAt, uses, bad)
+			}
+
+			at := p.take(n)
+			switch e.kind {
+			case kindItem:
+				line.Item = e.item // After an "expected a menu item" error
+			case kindSize:
+				addSize(e.size, at)
+			case kindModifier:
+				uses = append(uses, modifierUse{e.modifier, at})
+			}
+		}
+	}
+}
+
+// finish checks the parsed item against the menu: the size must exist
+// and each modifier must suit the item
+func (p *parser) finish(line OrderLine, sizeAt span, uses []modifierUse, bad bool) (OrderLine, bool) {
+	if line.Item == nil {
+		return line, false
+	}
+	item := line.Item
+
+	if line.Size == "" {
+		line.Size = item.DefaultSize()
+	} else if _, ok := item.Price(line.Size); !ok {
+		p.errorf(sizeAt, nil, "%s doesn't come in %s (sizes: %s)", item.Name, line.Size, strings.Join(item.Sizes, ", "))
+		bad = true
+	}
+
+	groups := map[string]string{}
+	for _, use := range uses {
+		m := use.modifier
+		switch {
+		case m.Drink && !item.Drink:
+			p.errorf(use.at, nil, "%s is for drinks, not %s", m.Name, item.Name)
+			bad = true
+		case !m.Drink && item.Drink:
+			p.errorf(use.at, nil, "%s is for food, not %s", m.Name, item.Name)
+			bad = true
+		case m.Group != "" && groups[m.Group] != "":
+			p.errorf(use.at, nil, "only one %s per item (already %s)", m.Group, groups[m.Group])
+			bad = true
+		case hasModifier(line.Modifiers, m):
+			p.errorf(use.at, nil, "%s given twice", m.Name)
+			bad = true
+		default:
+			line.Modifiers = append(line.Modifiers, m)
+			if m.Group != "" {
+				groups[m.Group] = m.Name
+			}
+		}
+	}
+	return line, !bad
+}
+
+func hasModifier(list []*Modifier, m *Modifier) bool {
+	for _, existing := range list {
+		if existing == m {
+			return true
+		}
+	}
+	return false
+}
+
+// unknown reports the word at the current token and skips it. The one to
+// three words there are compared with every phrase of the same length, so
+// "extr shot" is matched as a whole to "extra shot".
+func (p *parser) unknown() {
+	type candidate struct {
+		phrase   string
+		name     string
+		words    int
+		distance int
+	}
+	var candidates []candidate
+
+	words := p.words()
+	for n := 1; n <= len(words); n++ {
+		text := strings.Join(words[:n], " ")
+		limit := min(utf8.RuneCountInString(text)/3, 3)
+		for phrase, e := range p.vocabulary {
+			if strings.Count(phrase, " ") != n-1 {
+				continue
+			}
+			if d := distance(text, phrase); d <= limit {
+				candidates = append(candidates, candidate{phrase, e.name(), n, d})
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.distance != b.distance {
+			return a.distance < b.distance
+		}
+		if a.words != b.words {
+			return a.words > b.words
+		}
+		return a.phrase < b.phrase
+	})
+
+	// The best match decides how many words were misspelled; suggest its
+	// closest rivals of the same length, once per menu entry
+	n := 1
+	var suggestions []string
+	seen := map[string]bool{}
+	for _, c := range candidates {
+		if len(suggestions) == 0 {
+			n = c.words
+		}
+		if c.words != n || seen[c.name] || len(suggestions) == 3 {
+			continue
+		}
+		seen[c.name] = true
+		suggestions = append(suggestions, c.phrase)
+	}
+
+	at := p.take(n)
+	p.errorf(at, suggestions, "unknown word %q", p.input[at.pos:at.end])
+}
+
+// distance is the Levenshtein edit distance between a and b
+func distance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	prev := make([]int, len(t)+1)
+	curr := make([]int, len(t)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(s); i++ {
+		curr[0] = i
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(t)]
+}
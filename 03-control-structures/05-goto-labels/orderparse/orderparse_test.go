@@ -0,0 +1,192 @@
+package orderparse
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"switch-examples/catalog"
+)
+
+// The same catalog file the switch chapter's menus print
+const catalogPath = "../../02-switch/data/menu.json"
+
+func newParser(t *testing.T) *Parser {
+	t.Helper()
+	c, err := catalog.Load(catalogPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(FromCatalog(c, "coffee", "cold"))
+}
+
+// parse runs Parse with a deadline, so a lexer that stops advancing
+// fails the test instead of hanging it
+func parse(t *testing.T, input string) (Order, error) {
+	t.Helper()
+	type result struct {
+		order Order
+		err   error
+	}
+	p := newParser(t)
+	done := make(chan result, 1)
+	go func() {
+		order, err := p.Parse(input)
+		done <- result{order, err}
+	}()
+	select {
+	case r := <-done:
+		return r.order, r.err
+	case <-time.After(2 * time.Second):
+		t.Fatalf("Parse(%q) did not return", input)
+		return Order{}, nil
+	}
+}
+
+func lines(order Order) []string {
+	var out []string
+	for _, line := range order.Lines {
+		out = append(out, line.String())
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+		total Cents
+	}{
+		{
+			"2 large oat lattes, extra shot, no foam; 1 croissant warmed",
+			[]string{"2 × Large Latte + Oat Milk + Extra Shot + No Foam", "1 × Croissant + Warmed"},
+			2*(600+60+50) + 350,
+		},
+		{"LARGE LATTE EXTRA SHOT", []string{"1 × Large Latte + Extra Shot"}, 650},
+		{
+			"a doppio espresso and two cold brews with vanilla",
+			[]string{"1 × Doppio Espresso", "2 × Medium Cold Brew + Vanilla Syrup"},
+			350 + 2*(450+75),
+		},
+	}
+	for _, tt := range tests {
+		order, err := parse(t, tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q) error: %v", tt.input, err)
+			continue
+		}
+		if got := lines(order); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Parse(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+		if got := order.Total(); got != tt.total {
+			t.Errorf("Parse(%q) total = %v, want %v", tt.input, got, tt.total)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string // Each error's message
+	}{
+		{"3 lage lattte", []string{
+			`col 3: unknown word "lage" (did you mean "large"?)`,
+			`col 8: unknown word "lattte" (did you mean "latte"?)`,
+		}},
+		{"large croissant", []string{"col 1: Croissant doesn't come in Large (sizes: Regular)"}},
+		{"25 mochas", []string{"col 1: quantity 25 is out of range 1-20"}},
+		{"latte oat almond", []string{"col 11: only one milk per item (already Oat Milk)"}},
+		{"", []string{"col 1: empty order"}},
+	}
+	for _, tt := range tests {
+		_, err := parse(t, tt.input)
+		var list ParseErrors
+		if !errors.As(err, &list) {
+			t.Errorf("Parse(%q) error = %v, want ParseErrors", tt.input, err)
+			continue
+		}
+		var got []string
+		for _, e := range list {
+			got = append(got, e.Error())
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Parse(%q) errors = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+// Non-ASCII digits used to match unicode.IsDigit while the loop only
+// stepped over '0'-'9', so the lexer never moved and never returned
+func TestNonASCIIDigits(t *testing.T) {
+	for _, input := range []string{"２ lattes", "latte ٣", "१ croissant; 2 muffins"} {
+		_, err := parse(t, input)
+		var list ParseErrors
+		if !errors.As(err, &list) || !strings.HasPrefix(list[0].Msg, "unexpected") {
+			t.Errorf("Parse(%q) error = %v, want an unexpected-character error", input, err)
+		}
+	}
+
+	// The parser carries on past the bad digit
+	order, _ := parse(t, "१ croissant; 2 muffins")
+	if got := lines(order); len(got) != 1 || got[0] != "2 × Muffin" {
+		t.Errorf("lines after a bad digit = %q, want [2 × Muffin]", got)
+	}
+}
+
+func TestLexNumbers(t *testing.T) {
+	tokens := lex("12 lattes")
+	if tokens[0].kind != tokNumber || tokens[0].text != "12" {
+		t.Errorf("lex(%q)[0] = %+v, want number 12", "12 lattes", tokens[0])
+	}
+	tokens = lex("２")
+	if tokens[0].kind != tokIllegal || tokens[1].kind != tokEOF {
+		t.Errorf("lex(%q) = %+v, want one illegal token", "２", tokens)
+	}
+}
+
+func TestCaret(t *testing.T) {
+	input := "3 lage lattte"
+	_, err := parse(t, input)
+	var list ParseErrors
+	if !errors.As(err, &list) {
+		t.Fatalf("Parse(%q) error = %v", input, err)
+	}
+	want := "3 lage lattte\n  ^^^^"
+	if got := list[0].Caret(input); got != want {
+		t.Errorf("Caret =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestFromCatalog(t *testing.T) {
+	c, err := catalog.Parse([]byte(`{
+		"version": 1,
+		"categories": [{"id": "coffee", "name": "Coffee"}, {"id": "bakery", "name": "Bakery"}],
+		"items": [
+			{"id": "flat-white", "name": "Flat White", "category": "coffee", "prices": {"Large": 5.00, "Small": 4.00}},
+			{"id": "scone", "name": "Scone", "category": "bakery", "prices": {"Regular": 3.25}, "available": false}
+		]
+	}`), catalog.JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := New(FromCatalog(c, "coffee"))
+
+	// A new catalog item is orderable without touching the parser
+	order, err := p.Parse("2 large oat flat whites; scone warmed")
+	if err != nil {
+		t.Fatalf("Parse error: %v", err)
+	}
+	want := []string{"2 × Large Flat White + Oat Milk", "1 × Scone + Warmed"}
+	if got := lines(order); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("lines = %q, want %q", got, want)
+	}
+	if got := order.Total(); got != 2*(500+60)+325 {
+		t.Errorf("total = %v, want $14.45", got)
+	}
+
+	// Drink modifiers follow the category
+	if _, err := p.Parse("scone with oat"); err == nil || !strings.Contains(err.Error(), "Oat Milk is for drinks, not Scone") {
+		t.Errorf("oat on a scone: err = %v", err)
+	}
+}
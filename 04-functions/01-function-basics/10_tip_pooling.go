@@ -0,0 +1,349 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Tips used to vanish after the receipt printed: calculateTip returned a
+// number and Receipt.AddTip stored it on a receipt nobody kept. This example
+// records every tip against its order, pools tips per shift, and splits each
+// pool between the staff on the schedule by hours worked and role weight.
+// The payout report reconciles to the cent.
+
+// ============================================
+// Money Helpers
+// ============================================
+
+// Cents keeps money as whole cents so payouts always add up
+type Cents int64
+
+func (c Cents) String() string {
+	sign := ""
+	if c < 0 {
+		sign = "-"
+		c = -c
+	}
+	return fmt.Sprintf("%s$%d.%02d", sign, c/100, c%100)
+}
+
+// calculateTip now works in cents and rounds to the nearest cent
+func calculateTip(amount Cents, percent int) Cents {
+	return (amount*Cents(percent) + 50) / 100
+}
+
+// ============================================
+// Schedule (from printEmployeeSchedule)
+// ============================================
+
+type ScheduledShift struct {
+	Name  string
+	Role  string
+	Start time.Time
+	End   time.Time
+}
+
+// roleWeights gives shift leads a bigger share per hour
+var roleWeights = map[string]float64{
+	"Barista":    1.0,
+	"Shift Lead": 1.25,
+}
+
+func at(day time.Time, hour, minute int) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, day.Location())
+}
+
+func todaysSchedule(day time.Time) []ScheduledShift {
+	return []ScheduledShift{
+		{"Sarah M.", "Barista", at(day, 6, 0), at(day, 14, 0)},
+		{"John D.", "Shift Lead", at(day, 7, 0), at(day, 15, 0)},
+		{"Lisa K.", "Barista", at(day, 12, 0), at(day, 20, 0)},
+		{"Mike R.", "Barista", at(day, 14, 0), at(day, 22, 0)},
+		{"Emma S.", "Shift Lead", at(day, 15, 0), at(day, 23, 0)},
+	}
+}
+
+// overlap returns how long a scheduled shift falls inside a pool window
+func overlap(shift ScheduledShift, start, end time.Time) time.Duration {
+	from := shift.Start
+	if start.After(from) {
+		from = start
+	}
+	to := shift.End
+	if end.Before(to) {
+		to = end
+	}
+	if !to.After(from) {
+		return 0
+	}
+	return to.Sub(from)
+}
+
+// ============================================
+// Tip Pools
+// ============================================
+
+// TipRecord ties a tip to the order it came from
+type TipRecord struct {
+	OrderID string
+	Time    time.Time
+	Amount  Cents
+}
+
+// TipPool collects the tips for one shift window
+type TipPool struct {
+	Name  string
+	Start time.Time
+	End   time.Time
+	Tips  []TipRecord
+}
+
+func (p *TipPool) Total() Cents {
+	var total Cents
+	for _, tip := range p.Tips {
+		total += tip.Amount
+	}
+	return total
+}
+
+type TipLedger struct {
+	pools []*TipPool
+	held  *TipPool // Tips from outside every window, for the next shift
+}
+
+func NewTipLedger(pools ...*TipPool) *TipLedger {
+	return &TipLedger{pools: pools, held: &TipPool{Name: "Held"}}
+}
+
+// RecordTip files a tip into the pool for the shift it was earned in and
+// returns that pool. A tip outside every window, like an order paid after
+// close, is held for the next shift rather than turned away.
+func (l *TipLedger) RecordTip(orderID string, when time.Time, amount Cents) (*TipPool, error) {
+	if amount <= 0 {
+		return nil, fmt.Errorf("order %s: tip must be positive, got %s", orderID, amount)
+	}
+
+	tip := TipRecord{OrderID: orderID, Time: when, Amount: amount}
+	for _, pool := range l.pools {
+		if !when.Before(pool.Start) && when.Before(pool.End) {
+			pool.Tips = append(pool.Tips, tip)
+			return pool, nil
+		}
+	}
+
+	l.held.Tips = append(l.held.Tips, tip)
+	return l.held, nil
+}
+
+// ============================================
+// Distribution
+// ============================================
+
+type Payout struct {
+	Name   string
+	Role   string
+	Hours  float64
+	Weight float64
+	Amount Cents
+}
+
+// ErrNobodyOnShift means a pool has tips but no one worked its window, so
+// the money has to go somewhere else
+var ErrNobodyOnShift = errors.New("nobody on shift to pay")
+
+// distributePool splits a pool, plus anything carried over from an earlier
+// one, by (hours × role weight). Everyone gets the floor of their exact
+// share, then leftover cents go to the largest remainders so the payouts
+// add up to the total exactly.
+func distributePool(pool *TipPool, schedule []ScheduledShift, carried Cents) ([]Payout, error) {
+	total := pool.Total() + carried
+
+	var payouts []Payout
+	var points []float64
+	totalPoints := 0.0
+
+	for _, shift := range schedule {
+		worked := overlap(shift, pool.Start, pool.End)
+		if worked == 0 {
+			continue
+		}
+
+		weight, exists := roleWeights[shift.Role]
+		if !exists {
+			weight = 1.0
+		}
+
+		p := worked.Hours() * weight
+		payouts = append(payouts, Payout{
+			Name:   shift.Name,
+			Role:   shift.Role,
+			Hours:  worked.Hours(),
+			Weight: weight,
+		})
+		points = append(points, p)
+		totalPoints += p
+	}
+
+	if total == 0 {
+		return payouts, nil
+	}
+	if totalPoints == 0 {
+		return nil, fmt.Errorf("%s pool: %s: %w", pool.Name, total, ErrNobodyOnShift)
+	}
+
+	type remainder struct {
+		index int
+		value float64
+	}
+	var remainders []remainder
+	distributed := Cents(0)
+
+	for i := range payouts {
+		exact := float64(total) * points[i] / totalPoints
+		payouts[i].Amount = Cents(exact)
+		distributed += payouts[i].Amount
+		remainders = append(remainders, remainder{i, exact - float64(payouts[i].Amount)})
+	}
+
+	sort.SliceStable(remainders, func(i, j int) bool {
+		return remainders[i].value > remainders[j].value
+	})
+
+	for i := 0; distributed < total; i++ {
+		payouts[remainders[i%len(remainders)].index].Amount++
+		distributed++
+	}
+
+	return payouts, nil
+}
+
+// ============================================
+// Payout Report
+// ============================================
+
+func printPayoutReport(ledger *TipLedger, schedule []ScheduledShift) {
+	fmt.Println("💰 TIP PAYOUT REPORT")
+	fmt.Println(strings.Repeat("=", 52))
+
+	totals := make(map[string]Cents)
+	var collected, paid, carried Cents
+
+	for _, pool := range ledger.pools {
+		poolTotal := pool.Total()
+		collected += poolTotal
+
+		fmt.Printf("\n%s pool (%s - %s): %s from %d orders\n",
+			pool.Name, pool.Start.Format("3 PM"), pool.End.Format("3 PM"),
+			poolTotal, len(pool.Tips))
+		if carried > 0 {
+			fmt.Printf("  + %s carried over from the pool before\n", carried)
+		}
+		payouts, err := distributePool(pool, schedule, carried)
+		if errors.Is(err, ErrNobodyOnShift) {
+			// Nobody to pay: the tips roll into the next pool
+			fmt.Printf("  ⚠️  %v, carrying it to the next pool\n", err)
+			carried += poolTotal
+			continue
+		}
+		owed := poolTotal + carried
+		carried = 0
+
+		fmt.Printf("  %-10s %-11s %6s %7s %10s\n", "Name", "Role", "Hours", "Weight", "Payout")
+		var poolPaid Cents
+		for _, payout := range payouts {
+			fmt.Printf("  %-10s %-11s %6.1f %7.2f %10s\n",
+				payout.Name, payout.Role, payout.Hours, payout.Weight, payout.Amount)
+			totals[payout.Name] += payout.Amount
+			poolPaid += payout.Amount
+		}
+		paid += poolPaid
+
+		fmt.Printf("  %-37s %10s %s\n", "Pool total", poolPaid, reconcileMark(poolPaid, owed))
+	}
+
+	// Tips from outside every window wait for the next shift with the
+	// carried money
+	if held := ledger.held; len(held.Tips) > 0 {
+		collected += held.Total()
+		carried += held.Total()
+		fmt.Printf("\nOutside every pool: %s from %d orders, held for the next shift\n", held.Total(), len(held.Tips))
+		for _, tip := range held.Tips {
+			fmt.Printf("  %-10s %-26s %10s\n", tip.OrderID, tip.Time.Format("3:04 PM"), tip.Amount)
+		}
+	}
+
+	fmt.Println("\nTOTAL PER EMPLOYEE")
+	fmt.Println(strings.Repeat("-", 52))
+	for _, shift := range schedule {
+		fmt.Printf("  %-37s %10s\n", shift.Name, totals[shift.Name])
+	}
+	fmt.Println(strings.Repeat("-", 52))
+	fmt.Printf("  %-37s %10s\n", "Collected", collected)
+	if carried > 0 {
+		// Unpaid pools and after-hours tips open tomorrow's first pool
+		fmt.Printf("  %-37s %10s\n", "Held for the next shift", carried)
+	}
+	fmt.Printf("  %-37s %10s %s\n", "Paid out", paid, reconcileMark(paid+carried, collected))
+}
+
+func reconcileMark(paid, collected Cents) string {
+	if paid == collected {
+		return "✅"
+	}
+	return fmt.Sprintf("❌ off by %s", collected-paid)
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Tip Pooling ===")
+	fmt.Println()
+
+	day := time.Date(2024, time.March, 15, 0, 0, 0, 0, time.Local)
+	schedule := todaysSchedule(day)
+
+	ledger := NewTipLedger(
+		&TipPool{Name: "Pre-open", Start: at(day, 5, 0), End: at(day, 6, 0)}, // Mobile orders, nobody in yet
+		&TipPool{Name: "Morning", Start: at(day, 6, 0), End: at(day, 14, 0)},
+		&TipPool{Name: "Evening", Start: at(day, 14, 0), End: at(day, 23, 0)},
+	)
+
+	// Tips from today's receipts
+	orders := []struct {
+		id      string
+		time    time.Time
+		total   Cents
+		percent int
+	}{
+		{"ORD-1000", at(day, 5, 40), 980, 15},
+		{"ORD-1001", at(day, 7, 12), 1275, 18},
+		{"ORD-1002", at(day, 8, 3), 450, 20},
+		{"ORD-1003", at(day, 9, 47), 2310, 15},
+		{"ORD-1004", at(day, 12, 30), 890, 20},
+		{"ORD-1005", at(day, 15, 5), 1645, 18},
+		{"ORD-1006", at(day, 18, 41), 725, 25},
+		{"ORD-1007", at(day, 22, 15), 1199, 20},
+		{"ORD-1008", at(day, 23, 30), 500, 20}, // After close
+	}
+
+	for _, order := range orders {
+		tip := calculateTip(order.total, order.percent)
+		pool, err := ledger.RecordTip(order.id, order.time, tip)
+		if err != nil {
+			fmt.Printf("⚠️  %v\n", err)
+			continue
+		}
+		fmt.Printf("Recorded %s tip on %s (%s) → %s\n", tip, order.id, order.time.Format("3:04 PM"), pool.Name)
+	}
+	fmt.Println()
+
+	printPayoutReport(ledger, schedule)
+}
+
+// Key takeaways:
+// 1. Small helpers (at, overlap, calculateTip) keep the main logic readable
+// 2. Record tips where they happen so nothing is lost after the receipt
+// 3. Distribute with integer cents and hand out leftovers by largest remainder
+// 4. A report that reconciles is a report the staff can trust
+// 5. Money nobody can be paid is carried forward, never dropped
@@ -379,11 +379,15 @@ func demonstrateComplexReturns() {
 		fmt.Printf("Formatted: %s\n", formatter("Latte"))
 	}
 	
-	// Returning channels (preview)
-	dataChan, errorChan := startOrderStream()
+	// Returning channels (preview; see 11_order_pipeline.go)
+	dataChan, errorChan := startOrderStream([]string{"Latte", "Mocha", "", "Espresso"})
 	fmt.Println("\nOrder stream started (channels returned)")
-	close(dataChan)
-	close(errorChan)
+	for order := range dataChan {
+		fmt.Printf("  Received: %s\n", order)
+	}
+	for err := range errorChan {
+		fmt.Printf("  Stream error: %v\n", err)
+	}
 }
 
 type TopOrder struct {
@@ -440,10 +444,21 @@ func getOrderProcessors(category string) (func(string) bool, func(string) string
 	return validator, formatter
 }
 
-func startOrderStream() (chan string, chan error) {
+func startOrderStream(orders []string) (<-chan string, <-chan error) {
 	dataChan := make(chan string)
-	errorChan := make(chan error)
-	
-	// In real app, would start goroutine to send data
+	errorChan := make(chan error, len(orders)) // Buffered so the producer never waits on it
+	
+	// The producer owns both channels and closes them when it's done
+	go func() {
+		defer close(dataChan)
+		defer close(errorChan)
+		for i, order := range orders {
+			if order == "" {
+				errorChan <- fmt.Errorf("order %d is empty", i+1)
+				continue
+			}
+			dataChan <- order
+		}
+	}()
 	return dataChan, errorChan
 }
\ No newline at end of file
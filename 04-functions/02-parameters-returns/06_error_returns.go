@@ -5,9 +5,12 @@ import (
 	"fmt"
 	"strings"
 	"time"
+
+	"go-tutorial/04-functions/02-parameters-returns/multierr"
 )
 
-// Custom error types
+// Custom error types (13_domain_errors.go has one taxonomy with codes that
+// works with errors.Is and errors.As)
 type ValidationError struct {
 	Field   string
 	Message string
@@ -282,11 +285,11 @@ func demonstrateCustomErrors() {
 	})
 	
 	if err != nil {
-		if vErr, ok := err.(ValidationError); ok {
+		var vErr ValidationError
+		if errors.As(err, &vErr) {
 			fmt.Printf("Validation failed on field '%s': %s\n", vErr.Field, vErr.Message)
-		} else {
-			fmt.Printf("Error: %v\n", err)
 		}
+		fmt.Printf("Error: %v\n", err)
 	}
 	
 	// Business error
@@ -313,29 +316,32 @@ type Order struct {
 	Total        float64
 }
 
+// validateOrder reports every field that's wrong, not just the first
 func validateOrder(order Order) error {
+	var errs multierr.Errors
+	
 	if order.CustomerName == "" {
-		return ValidationError{
+		errs.Add(ValidationError{
 			Field:   "CustomerName",
 			Message: "customer name is required",
-		}
+		})
 	}
 	
 	if len(order.Items) == 0 {
-		return ValidationError{
+		errs.Add(ValidationError{
 			Field:   "Items",
 			Message: "order must contain at least one item",
-		}
+		})
 	}
 	
 	if order.Total <= 0 {
-		return ValidationError{
+		errs.Add(ValidationError{
 			Field:   "Total",
 			Message: "order total must be positive",
-		}
+		})
 	}
 	
-	return nil
+	return errs.Err()
 }
 
 func processBusinessRule(customerType string, orderAmount float64) error {
@@ -484,26 +490,26 @@ func aggregateErrors() {
 	fmt.Println("\n--- Good: Aggregate multiple errors ---")
 	
 	items := []string{"Coffee", "BadItem1", "Tea", "BadItem2"}
-	errors := processItemsWithErrors(items)
+	err := processItemsWithErrors(items)
 	
-	if len(errors) > 0 {
-		fmt.Printf("Processing completed with %d errors:\n", len(errors))
-		for i, err := range errors {
-			fmt.Printf("  Error %d: %v\n", i+1, err)
-		}
+	if err != nil {
+		fmt.Printf("Processing completed with %v\n", err)
 	} else {
 		fmt.Println("All items processed successfully!")
 	}
 }
 
-func processItemsWithErrors(items []string) []error {
-	var errs []error
+// processItemsWithErrors collects every failure into one error (see
+// 14_multi_errors.go), so callers can still check it with err != nil
+// and errors.Is
+func processItemsWithErrors(items []string) error {
+	var errs multierr.Errors
 	
 	for _, item := range items {
 		if strings.HasPrefix(item, "Bad") {
-			errs = append(errs, fmt.Errorf("cannot process item: %s", item))
+			errs.Add(fmt.Errorf("cannot process item: %s", item))
 		}
 	}
 	
-	return errs
+	return errs.Err()
 }
\ No newline at end of file
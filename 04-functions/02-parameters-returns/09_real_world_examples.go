@@ -2,10 +2,11 @@ package main
 
 import (
 	"fmt"
-	"math"
 	"sort"
 	"strings"
 	"time"
+
+	"go-tutorial/04-functions/02-parameters-returns/shoperr"
 )
 
 // Domain models
@@ -147,7 +148,7 @@ func findCustomerByID(customers []Customer, id string) (*Customer, error) {
 			return &customers[i], nil
 		}
 	}
-	return nil, fmt.Errorf("customer not found: %s", id)
+	return nil, shoperr.E(shoperr.ErrNotFound, "customer.find", "customer "+id, "customer_id", id)
 }
 
 func updateLoyaltyTier(customer *Customer, newSpending float64) (*Customer, error) {
@@ -257,7 +258,7 @@ func demonstrateOrderProcessing() {
 	// Create new order
 	order, err := createOrder("CUST-001", menu)
 	if err != nil {
-		fmt.Printf("Error creating order: %v\n", err)
+		fmt.Printf("Error creating order (%s): %v\n", shoperr.CodeOf(err), err)
 		return
 	}
 	
@@ -267,7 +268,7 @@ func demonstrateOrderProcessing() {
 	// Process payment
 	transaction, err := processOrderPayment(order, "credit", "4242-4242-4242-4242")
 	if err != nil {
-		fmt.Printf("Payment failed: %v\n", err)
+		fmt.Printf("Payment failed (%s): %v\n", shoperr.CodeOf(err), err)
 		return
 	}
 	
@@ -310,7 +311,7 @@ func createMenu() []MenuItem {
 
 func createOrder(customerID string, menu []MenuItem) (*Order, error) {
 	if customerID == "" {
-		return nil, fmt.Errorf("customer ID required")
+		return nil, shoperr.E(shoperr.ErrInvalidInput, "order.create", "customer ID required", "field", "customer_id")
 	}
 	
 	order := &Order{
@@ -391,12 +392,14 @@ func displayOrderSummary(order *Order) {
 }
 
 func processOrderPayment(order *Order, method, details string) (*Transaction, error) {
+	const op = "payment.process"
 	if order == nil {
-		return nil, fmt.Errorf("order cannot be nil")
+		return nil, shoperr.E(shoperr.ErrInvalidInput, op, "order cannot be nil")
 	}
 	
 	if order.Total <= 0 {
-		return nil, fmt.Errorf("invalid order total: %.2f", order.Total)
+		return nil, shoperr.E(shoperr.ErrInvalidInput, op, fmt.Sprintf("order total %.2f", order.Total),
+			"order_id", order.ID, "total", order.Total)
 	}
 	
 	// Validate payment method
@@ -408,7 +411,7 @@ func processOrderPayment(order *Order, method, details string) (*Transaction, er
 	}
 	
 	if !validMethods[method] {
-		return nil, fmt.Errorf("invalid payment method: %s", method)
+		return nil, shoperr.E(shoperr.ErrInvalidInput, op, "payment method "+method, "method", method)
 	}
 	
 	// Create transaction
@@ -430,12 +433,16 @@ func processOrderPayment(order *Order, method, details string) (*Transaction, er
 		order.Status = "Paid"
 	} else {
 		transaction.Status = "Failed"
-		return transaction, fmt.Errorf("payment declined")
+		return transaction, shoperr.E(shoperr.ErrPaymentDeclined, op, fmt.Sprintf("$%.2f", order.Total),
+			"order_id", order.ID, "transaction_id", transaction.ID)
 	}
 	
 	return transaction, nil
 }
 
+// estimatePreparationTime uses fixed rules of thumb; 12_prep_estimator.go
+// learns the real times from PaidAt/ReadyAt and quotes with the queue
+// and staff in mind
 func estimatePreparationTime(order *Order) time.Duration {
 	if order == nil || len(order.Items) == 0 {
 		return 0
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Tax used to be hardcoded everywhere: 0.08 in createOrder, 8.5 in
+// 08_real_world_types.go and 0.085 in 06_money_handling.go. Now that
+// GoCoffee has stores in different cities, each store gets a tax profile
+// with rates per item category and per service mode (dine-in or takeout),
+// and some stores show VAT-style prices that already include tax.
+
+// Cents keeps money as whole cents so totals never drift
+type Cents int64
+
+func (c Cents) String() string {
+	return c.In("$")
+}
+
+// In formats the amount with a currency symbol, like €3.90
+func (c Cents) In(symbol string) string {
+	sign := ""
+	if c < 0 {
+		sign = "-"
+		c = -c
+	}
+	return fmt.Sprintf("%s%s%d.%02d", sign, symbol, c/100, c%100)
+}
+
+// === Categories and Service Modes ===
+
+type TaxCategory string
+
+const (
+	CategoryBeverage      TaxCategory = "beverage"
+	CategoryPreparedFood  TaxCategory = "prepared-food"
+	CategoryPackagedGoods TaxCategory = "packaged-goods" // Bags of beans, merch
+)
+
+type ServiceMode string
+
+const (
+	AnyService ServiceMode = ""
+	DineIn     ServiceMode = "dine-in"
+	Takeout    ServiceMode = "takeout"
+)
+
+// === Tax Profiles ===
+
+type TaxRate struct {
+	Name string
+	Rate float64 // 0.085 means 8.5%
+}
+
+// TaxRule applies a rate to a category; Mode narrows it to dine-in or takeout
+type TaxRule struct {
+	Category TaxCategory
+	Mode     ServiceMode
+	Rate     TaxRate
+}
+
+// TaxProfile describes how one jurisdiction taxes our menu
+type TaxProfile struct {
+	Jurisdiction string
+	Currency     string // Symbol for receipts; "$" if empty
+	Inclusive    bool   // Menu prices already contain tax (VAT style)
+	Rules        []TaxRule
+}
+
+// RateFor finds the most specific rule: category + mode beats category alone
+func (p TaxProfile) RateFor(category TaxCategory, mode ServiceMode) (TaxRate, error) {
+	var fallback *TaxRule
+
+	for i, rule := range p.Rules {
+		if rule.Category != category {
+			continue
+		}
+		if rule.Mode == mode {
+			return rule.Rate, nil
+		}
+		if rule.Mode == AnyService {
+			fallback = &p.Rules[i]
+		}
+	}
+
+	if fallback != nil {
+		return fallback.Rate, nil
+	}
+	return TaxRate{}, fmt.Errorf("%s has no tax rule for %s (%s)", p.Jurisdiction, category, mode)
+}
+
+// storeTaxProfiles replaces the scattered tax constants
+func storeTaxProfiles() map[string]TaxProfile {
+	seattleFood := TaxRate{Name: "WA Sales Tax", Rate: 0.1025}
+	exempt := TaxRate{Name: "Exempt", Rate: 0}
+
+	berlinStandard := TaxRate{Name: "MwSt 19%", Rate: 0.19}
+	berlinReduced := TaxRate{Name: "MwSt 7%", Rate: 0.07}
+
+	return map[string]TaxProfile{
+		"seattle": {
+			Jurisdiction: "Seattle, WA",
+			Rules: []TaxRule{
+				{Category: CategoryBeverage, Rate: seattleFood},
+				{Category: CategoryPreparedFood, Rate: seattleFood},
+				{Category: CategoryPackagedGoods, Rate: exempt}, // Groceries are exempt
+			},
+		},
+		"portland": {
+			Jurisdiction: "Portland, OR",
+			Rules: []TaxRule{ // No sales tax in Oregon
+				{Category: CategoryBeverage, Rate: exempt},
+				{Category: CategoryPreparedFood, Rate: exempt},
+				{Category: CategoryPackagedGoods, Rate: exempt},
+			},
+		},
+		"berlin": {
+			Jurisdiction: "Berlin, DE",
+			Currency:     "€",
+			Inclusive:    true,
+			Rules: []TaxRule{
+				{Category: CategoryBeverage, Mode: DineIn, Rate: berlinStandard},
+				{Category: CategoryBeverage, Mode: Takeout, Rate: berlinReduced},
+				{Category: CategoryPreparedFood, Mode: DineIn, Rate: berlinStandard},
+				{Category: CategoryPreparedFood, Mode: Takeout, Rate: berlinReduced},
+				{Category: CategoryPackagedGoods, Rate: berlinReduced},
+			},
+		},
+	}
+}
+
+// === Orders ===
+
+type OrderItem struct {
+	Name     string
+	Category TaxCategory
+	Price    Cents // Shelf price, with or without tax depending on the profile
+	Quantity int
+}
+
+type Order struct {
+	Customer string
+	Store    string
+	Takeout  bool
+	Items    []OrderItem
+}
+
+// OrderBuilder mirrors the builder in 08_parameter_patterns.go
+type OrderBuilder struct {
+	order *Order
+}
+
+func NewOrderBuilder() *OrderBuilder {
+	return &OrderBuilder{order: &Order{}}
+}
+
+func (b *OrderBuilder) SetCustomer(name string) *OrderBuilder {
+	b.order.Customer = name
+	return b
+}
+
+func (b *OrderBuilder) SetStore(store string) *OrderBuilder {
+	b.order.Store = store
+	return b
+}
+
+func (b *OrderBuilder) AddItem(name string, category TaxCategory, price Cents, quantity int) *OrderBuilder {
+	b.order.Items = append(b.order.Items, OrderItem{
+		Name:     name,
+		Category: category,
+		Price:    price,
+		Quantity: quantity,
+	})
+	return b
+}
+
+// SetTakeout now matters: it changes which tax rate applies
+func (b *OrderBuilder) SetTakeout(takeout bool) *OrderBuilder {
+	b.order.Takeout = takeout
+	return b
+}
+
+func (b *OrderBuilder) Build() *Order {
+	return b.order
+}
+
+func (o *Order) ServiceMode() ServiceMode {
+	if o.Takeout {
+		return Takeout
+	}
+	return DineIn
+}
+
+// === Tax Engine ===
+
+// TaxLine is one row of the per-rate breakdown on the receipt
+type TaxLine struct {
+	Rate    TaxRate
+	Taxable Cents // Net amount the rate applies to
+	Tax     Cents
+}
+
+type TaxResult struct {
+	Jurisdiction string
+	Currency     string
+	Inclusive    bool
+	Net          Cents // Total before tax
+	Tax          Cents
+	Total        Cents // What the customer pays
+	Lines        []TaxLine
+}
+
+// CalculateTax groups items by rate and rounds once per rate, not per item,
+// so the breakdown always adds up to the total
+func CalculateTax(order *Order, profiles map[string]TaxProfile) (TaxResult, error) {
+	profile, exists := profiles[strings.ToLower(order.Store)]
+	if !exists {
+		return TaxResult{}, fmt.Errorf("no tax profile for store %q", order.Store)
+	}
+
+	// Keyed by name and rate: two different rates may share a name
+	grouped := make(map[string]*TaxLine)
+	gross := make(map[string]Cents) // Inclusive prices per rate
+
+	for _, item := range order.Items {
+		rate, err := profile.RateFor(item.Category, order.ServiceMode())
+		if err != nil {
+			return TaxResult{}, err
+		}
+
+		key := fmt.Sprintf("%s@%v", rate.Name, rate.Rate)
+		line, exists := grouped[key]
+		if !exists {
+			line = &TaxLine{Rate: rate}
+			grouped[key] = line
+		}
+
+		amount := item.Price * Cents(item.Quantity)
+		if profile.Inclusive {
+			gross[key] += amount
+		} else {
+			line.Taxable += amount
+		}
+	}
+
+	result := TaxResult{
+		Jurisdiction: profile.Jurisdiction,
+		Currency:     profile.Currency,
+		Inclusive:    profile.Inclusive,
+	}
+	if result.Currency == "" {
+		result.Currency = "$"
+	}
+
+	for key, line := range grouped {
+		if profile.Inclusive {
+			// Back the tax out of the shelf price: net = gross / (1 + rate)
+			line.Taxable = Cents(math.Round(float64(gross[key]) / (1 + line.Rate.Rate)))
+			line.Tax = gross[key] - line.Taxable
+		} else {
+			line.Tax = Cents(math.Round(float64(line.Taxable) * line.Rate.Rate))
+		}
+
+		result.Net += line.Taxable
+		result.Tax += line.Tax
+		result.Lines = append(result.Lines, *line)
+	}
+	result.Total = result.Net + result.Tax
+
+	// Highest rate first; the name breaks ties so the order never varies
+	sort.Slice(result.Lines, func(i, j int) bool {
+		a, b := result.Lines[i].Rate, result.Lines[j].Rate
+		if a.Rate != b.Rate {
+			return a.Rate > b.Rate
+		}
+		return a.Name < b.Name
+	})
+
+	return result, nil
+}
+
+// === Receipt ===
+
+func printTaxReceipt(order *Order, result TaxResult) {
+	// %15s counts bytes, and € is three of them, so pad by runes
+	money := func(c Cents, width int) string {
+		text := c.In(result.Currency)
+		return strings.Repeat(" ", max(0, width-utf8.RuneCountInString(text))) + text
+	}
+	fmt.Printf("GoCoffee %s — %s (%s)\n", result.Jurisdiction, order.Customer, order.ServiceMode())
+	fmt.Println(strings.Repeat("-", 42))
+
+	for _, item := range order.Items {
+		fmt.Printf("%d × %-22s %s\n", item.Quantity, item.Name, money(item.Price*Cents(item.Quantity), 15))
+	}
+
+	fmt.Println(strings.Repeat("-", 42))
+
+	if result.Inclusive {
+		fmt.Printf("%-26s %s\n", "Total", money(result.Total, 15))
+		fmt.Println("Prices include:")
+		for _, line := range result.Lines {
+			fmt.Printf("  %-14s on %s %s\n", line.Rate.Name, money(line.Taxable, 8), money(line.Tax, 14))
+		}
+		return
+	}
+
+	fmt.Printf("%-26s %s\n", "Subtotal", money(result.Net, 15))
+	for _, line := range result.Lines {
+		label := fmt.Sprintf("%s (%.2f%%)", line.Rate.Name, line.Rate.Rate*100)
+		fmt.Printf("%-26s %s\n", label, money(line.Tax, 15))
+	}
+	fmt.Printf("%-26s %s\n", "Total", money(result.Total, 15))
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Tax Engine ===")
+	fmt.Println()
+
+	profiles := storeTaxProfiles()
+
+	orders := []*Order{
+		NewOrderBuilder().
+			SetCustomer("Alice").
+			SetStore("seattle").
+			AddItem("Latte", CategoryBeverage, 450, 2).
+			AddItem("Croissant", CategoryPreparedFood, 325, 1).
+			AddItem("House Blend Beans 1lb", CategoryPackagedGoods, 1499, 1).
+			Build(),
+		NewOrderBuilder().
+			SetCustomer("Bob").
+			SetStore("portland").
+			AddItem("Espresso", CategoryBeverage, 250, 1).
+			SetTakeout(true).
+			Build(),
+		// Same Berlin order twice: takeout switches to the reduced rate
+		NewOrderBuilder().
+			SetCustomer("Clara").
+			SetStore("berlin").
+			AddItem("Cappuccino", CategoryBeverage, 390, 1).
+			AddItem("Apfelstrudel", CategoryPreparedFood, 420, 1).
+			AddItem("Espresso Beans 250g", CategoryPackagedGoods, 899, 1).
+			Build(),
+		NewOrderBuilder().
+			SetCustomer("Clara").
+			SetStore("berlin").
+			AddItem("Cappuccino", CategoryBeverage, 390, 1).
+			AddItem("Apfelstrudel", CategoryPreparedFood, 420, 1).
+			AddItem("Espresso Beans 250g", CategoryPackagedGoods, 899, 1).
+			SetTakeout(true).
+			Build(),
+		NewOrderBuilder().
+			SetCustomer("Dan").
+			SetStore("tokyo").
+			AddItem("Matcha Latte", CategoryBeverage, 500, 1).
+			Build(),
+	}
+
+	for _, order := range orders {
+		result, err := CalculateTax(order, profiles)
+		if err != nil {
+			fmt.Printf("❌ %v\n\n", err)
+			continue
+		}
+		printTaxReceipt(order, result)
+		fmt.Println()
+	}
+}
+
+// Key points:
+// 1. Tax rates are data (profiles per store), not constants in code
+// 2. The most specific rule wins: category + service mode, then category
+// 3. Round once per rate so the per-rate lines add up to the total
+// 4. Inclusive pricing backs the tax out of the shelf price instead of adding it
@@ -0,0 +1,394 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// startOrderStream in 04_multiple_returns.go shows that a function can
+// return channels; this file puts channels to work. During the rush, orders
+// go through three stages joined by bounded channels:
+//
+//	intake ──queue──▶ barista pool ──counter──▶ pickup
+//
+// A full queue pushes back on intake (it waits, or turns customers away),
+// a full pickup counter pushes back on the baristas, and cancelling the
+// context closes the doors. Each stage keeps its own metrics.
+
+// minute is one simulated minute of real time, so the rush takes seconds
+const minute = 10 * time.Millisecond
+
+// Minutes to make each drink, one barista per drink
+var makeMinutes = map[string]float64{
+	"Espresso":   1,
+	"Americano":  1.5,
+	"Latte":      2.5,
+	"Cappuccino": 2.5,
+	"Mocha":      3,
+	"Cold Brew":  1,
+}
+
+type Order struct {
+	ID       int
+	Customer string
+	Drink    string
+	Arrives  time.Duration // After the doors open
+}
+
+// Ticket follows one order through the pipeline. Each stage fills in its
+// own times before handing it on, so nothing is shared between goroutines.
+type Ticket struct {
+	Order
+	Queued   time.Time
+	Started  time.Time
+	Ready    time.Time
+	PickedUp time.Time
+	Barista  int
+}
+
+// Overflow is what intake does when the barista queue is full
+type Overflow int
+
+const (
+	Block  Overflow = iota // Customer waits at the register
+	Reject                 // Customer is turned away
+)
+
+func (o Overflow) String() string {
+	if o == Reject {
+		return "reject"
+	}
+	return "block"
+}
+
+type Config struct {
+	Baristas   int
+	QueueSize  int // Orders waiting for a barista
+	CounterCap int // Drinks waiting on the pickup counter
+	PickupTime time.Duration
+	OnFull     Overflow
+	Drain      bool // On cancel, make the queued orders before stopping
+}
+
+// Validate rejects a pipeline that could never finish: with no baristas
+// nothing reads the queue, and a Block intake waits on it forever
+func (c Config) Validate() error {
+	var errs []error
+	if c.Baristas < 1 {
+		errs = append(errs, fmt.Errorf("need at least 1 barista, got %d", c.Baristas))
+	}
+	if c.QueueSize < 0 || c.CounterCap < 0 {
+		errs = append(errs, fmt.Errorf("queue %d and counter %d must not be negative", c.QueueSize, c.CounterCap))
+	}
+	if c.PickupTime < 0 {
+		errs = append(errs, fmt.Errorf("pickup time %v must not be negative", c.PickupTime))
+	}
+	if c.OnFull != Block && c.OnFull != Reject {
+		errs = append(errs, fmt.Errorf("unknown overflow policy %d", int(c.OnFull)))
+	}
+	return errors.Join(errs...)
+}
+
+// === Metrics ===
+
+// StageMetrics counts what one stage did. Several baristas update the same
+// stage, so every update takes the lock.
+type StageMetrics struct {
+	Name string
+
+	mu       sync.Mutex
+	In       int
+	Out      int
+	Dropped  int           // Rejected or cancelled
+	MaxDepth int           // Deepest the stage's output channel got
+	Blocked  time.Duration // Waiting on a full output channel
+	Busy     time.Duration // Doing the stage's work
+}
+
+func (m *StageMetrics) received() {
+	m.mu.Lock()
+	m.In++
+	m.mu.Unlock()
+}
+
+func (m *StageMetrics) dropped() {
+	m.mu.Lock()
+	m.Dropped++
+	m.mu.Unlock()
+}
+
+func (m *StageMetrics) worked(d time.Duration) {
+	m.mu.Lock()
+	m.Busy += d
+	m.mu.Unlock()
+}
+
+// sent records a hand-off: how long it waited and the queue depth after
+func (m *StageMetrics) sent(blocked time.Duration, depth int) {
+	m.mu.Lock()
+	m.Out++
+	m.Blocked += blocked
+	m.MaxDepth = max(m.MaxDepth, depth)
+	m.mu.Unlock()
+}
+
+// === Pipeline ===
+
+type Pipeline struct {
+	cfg      Config
+	Intake   *StageMetrics
+	Baristas *StageMetrics
+	Pickup   *StageMetrics
+	Elapsed  time.Duration
+}
+
+func NewPipeline(cfg Config) (*Pipeline, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("pipeline config: %w", err)
+	}
+	return &Pipeline{
+		cfg:      cfg,
+		Intake:   &StageMetrics{Name: "intake"},
+		Baristas: &StageMetrics{Name: "baristas"},
+		Pickup:   &StageMetrics{Name: "pickup"},
+	}, nil
+}
+
+// Run feeds the orders in at their arrival times and returns the tickets
+// that were picked up. It returns once every stage has stopped: when all
+// orders are done, or soon after ctx is cancelled.
+func (p *Pipeline) Run(ctx context.Context, orders []Order) []*Ticket {
+	start := time.Now()
+	queue := make(chan *Ticket, p.cfg.QueueSize)
+	counter := make(chan *Ticket, p.cfg.CounterCap)
+
+	go p.intake(ctx, start, orders, queue)
+
+	var wg sync.WaitGroup
+	for i := 1; i <= p.cfg.Baristas; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			p.barista(ctx, id, queue, counter)
+		}(i)
+	}
+	// The counter closes when the last barista goes home
+	go func() {
+		wg.Wait()
+		close(counter)
+	}()
+
+	done := p.pickup(counter)
+	p.Elapsed = time.Since(start)
+	return done
+}
+
+// intake places each order when its customer arrives. It owns the queue
+// and closes it when the orders run out or the doors close.
+func (p *Pipeline) intake(ctx context.Context, start time.Time, orders []Order, queue chan<- *Ticket) {
+	defer close(queue)
+
+	for _, order := range orders {
+		select {
+		case <-time.After(time.Until(start.Add(order.Arrives))):
+		case <-ctx.Done():
+			return
+		}
+		p.Intake.received()
+		ticket := &Ticket{Order: order, Queued: time.Now()}
+
+		if p.cfg.OnFull == Reject {
+			select {
+			case queue <- ticket:
+				p.Intake.sent(0, len(queue))
+			default:
+				p.Intake.dropped()
+			}
+			continue
+		}
+
+		waiting := time.Now()
+		select {
+		case queue <- ticket:
+			p.Intake.sent(time.Since(waiting), len(queue))
+		case <-ctx.Done():
+			p.Intake.dropped()
+			return
+		}
+	}
+}
+
+// barista makes drinks until the queue is closed and empty. Without Drain,
+// a cancelled context also stops it between (and during) drinks.
+func (p *Pipeline) barista(ctx context.Context, id int, queue <-chan *Ticket, counter chan<- *Ticket) {
+	for ticket := range queue {
+		p.Baristas.received()
+		if !p.cfg.Drain && ctx.Err() != nil {
+			p.Baristas.dropped()
+			continue
+		}
+
+		ticket.Started, ticket.Barista = time.Now(), id
+		making := time.Duration(makeMinutes[ticket.Drink] * float64(minute))
+		if p.cfg.Drain {
+			time.Sleep(making)
+		} else {
+			select {
+			case <-time.After(making):
+			case <-ctx.Done():
+				p.Baristas.worked(time.Since(ticket.Started))
+				p.Baristas.dropped()
+				continue
+			}
+		}
+		ticket.Ready = time.Now()
+		p.Baristas.worked(ticket.Ready.Sub(ticket.Started))
+
+		// A full counter holds the barista up: backpressure from pickup.
+		// No ctx here: pickup reads until the counter closes, so this
+		// send always completes and a made drink is never thrown away.
+		counter <- ticket
+		p.Baristas.sent(time.Since(ticket.Ready), len(counter))
+	}
+}
+
+// pickup hands drinks over one at a time until the counter is closed.
+// Drinks already made are always handed over, even after a cancel.
+func (p *Pipeline) pickup(counter <-chan *Ticket) []*Ticket {
+	var done []*Ticket
+	for ticket := range counter {
+		p.Pickup.received()
+		time.Sleep(p.cfg.PickupTime)
+		p.Pickup.worked(p.cfg.PickupTime)
+
+		ticket.PickedUp = time.Now()
+		p.Pickup.sent(0, 0)
+		done = append(done, ticket)
+	}
+	return done
+}
+
+// === Reporting ===
+
+func minutes(d time.Duration) float64 {
+	return float64(d) / float64(minute)
+}
+
+// percentile returns the p-th percentile (0-100) of sorted durations
+func percentile(sorted []time.Duration, p int) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	i := (len(sorted)*p + 99) / 100
+	return sorted[max(i-1, 0)]
+}
+
+func report(p *Pipeline, orders []Order, done []*Ticket) {
+	fmt.Printf("  %-9s %4s %4s %8s %10s %12s %10s\n", "Stage", "In", "Out", "Dropped", "Max queue", "Blocked min", "Busy min")
+	for _, m := range []*StageMetrics{p.Intake, p.Baristas, p.Pickup} {
+		fmt.Printf("  %-9s %4d %4d %8d %10d %12.1f %10.1f\n",
+			m.Name, m.In, m.Out, m.Dropped, m.MaxDepth, minutes(m.Blocked), minutes(m.Busy))
+	}
+
+	var waits, totals []time.Duration
+	for _, t := range done {
+		waits = append(waits, t.Started.Sub(t.Queued))
+		totals = append(totals, t.PickedUp.Sub(t.Queued))
+	}
+	sort.Slice(waits, func(i, j int) bool { return waits[i] < waits[j] })
+	sort.Slice(totals, func(i, j int) bool { return totals[i] < totals[j] })
+
+	utilization := minutes(p.Baristas.Busy) / (minutes(p.Elapsed) * float64(p.cfg.Baristas)) * 100
+	fmt.Printf("  Served %d of %d orders in %.0f min, baristas %.0f%% busy\n",
+		len(done), len(orders), minutes(p.Elapsed), utilization)
+	fmt.Printf("  Wait for a barista: p50 %.1f min, p95 %.1f min\n",
+		minutes(percentile(waits, 50)), minutes(percentile(waits, 95)))
+	fmt.Printf("  Order to pickup:    p50 %.1f min, p95 %.1f min\n",
+		minutes(percentile(totals, 50)), minutes(percentile(totals, 95)))
+}
+
+// rushHour makes a reproducible morning: customers every 0.5 min on
+// average for 40 minutes
+func rushHour(seed int64) []Order {
+	rng := rand.New(rand.NewSource(seed))
+	drinks := []string{"Latte", "Latte", "Cappuccino", "Americano", "Mocha", "Espresso", "Cold Brew"}
+	names := []string{"Alice", "Bob", "Carol", "Dan", "Eve", "Frank", "Grace", "Heidi"}
+
+	var orders []Order
+	var at time.Duration
+	for id := 1; ; id++ {
+		at += time.Duration(rng.ExpFloat64() * 0.5 * float64(minute))
+		if at > 40*minute {
+			return orders
+		}
+		orders = append(orders, Order{
+			ID:       id,
+			Customer: names[rng.Intn(len(names))],
+			Drink:    drinks[rng.Intn(len(drinks))],
+			Arrives:  at,
+		})
+	}
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Rush-Hour Order Pipeline ===")
+	fmt.Println()
+
+	orders := rushHour(7)
+	fmt.Printf("%d orders over 40 minutes (1 simulated minute = %v)\n", len(orders), minute)
+	fmt.Println("Timings vary a little between runs: these are real goroutines.")
+
+	base := Config{Baristas: 4, QueueSize: 6, CounterCap: 4, PickupTime: minute / 4, OnFull: Block, Drain: true}
+
+	runs := []struct {
+		title   string
+		cfg     func(Config) Config
+		closeAt time.Duration // Cancel the context this long after opening
+	}{
+		{"4 baristas, customers wait when the queue is full", func(c Config) Config { return c }, 0},
+		{"3 baristas, customers wait", func(c Config) Config { c.Baristas = 3; return c }, 0},
+		{"3 baristas, customers turned away when the queue is full", func(c Config) Config {
+			c.Baristas, c.OnFull = 3, Reject
+			return c
+		}, 0},
+		{"4 baristas, doors close at 20 min, queue is drained", func(c Config) Config { return c }, 20 * minute},
+		{"4 baristas, power cut at 20 min, everything stops", func(c Config) Config { c.Drain = false; return c }, 20 * minute},
+		{"Nobody on shift: intake would wait on the queue forever", func(c Config) Config { c.Baristas = 0; return c }, 0},
+	}
+
+	for i, run := range runs {
+		cfg := run.cfg(base)
+		fmt.Printf("\n%d. %s\n", i+1, run.title)
+		fmt.Printf("   (queue %d, counter %d, on full: %v)\n", cfg.QueueSize, cfg.CounterCap, cfg.OnFull)
+
+		ctx, cancel := context.Background(), context.CancelFunc(func() {})
+		if run.closeAt > 0 {
+			ctx, cancel = context.WithTimeout(context.Background(), run.closeAt)
+		}
+
+		pipeline, err := NewPipeline(cfg)
+		if err != nil {
+			fmt.Printf("   ❌ %v\n", err)
+			cancel()
+			continue
+		}
+		done := pipeline.Run(ctx, orders)
+		cancel()
+		report(pipeline, orders, done)
+	}
+
+	fmt.Println()
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Println("Pipeline rules:")
+	fmt.Println("1. Bounded channels turn a slow stage into backpressure, not memory growth")
+	fmt.Println("2. The sender closes the channel; range loops end on their own")
+	fmt.Println("3. A wait that could last forever also selects on ctx.Done();")
+	fmt.Println("   work already promised (draining, pickup) finishes without it")
+	fmt.Println("4. Metrics per stage show where the time actually goes")
+}
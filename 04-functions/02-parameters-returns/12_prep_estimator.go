@@ -0,0 +1,446 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+// estimatePreparationTime in 09_real_world_examples.go guesses 2 minutes
+// an item, 30 seconds a customization and a minute more for coffee, and
+// ignores the line in front of the order. But every order already records
+// when it was paid for and when it was ready (PaidAt/ReadyAt in
+// 05_state_machine.go). This estimator learns from those timestamps how
+// long each item and customization really takes, and how much each order
+// ahead adds per barista on shift, then quotes customers a ready time.
+//
+// The hardcoded rules are its starting point: with little history it
+// stays close to them, and the more orders it sees the more it trusts
+// what it measured.
+
+type OrderItem struct {
+	Name           string
+	Quantity       int
+	Customizations []string
+}
+
+type CoffeeOrder struct {
+	ID      int
+	Items   []OrderItem
+	PaidAt  *time.Time
+	ReadyAt *time.Time
+}
+
+// Conditions is what the shop looked like when the order was paid for
+type Conditions struct {
+	Ahead int // Orders in the queue in front of it
+	Staff int // Baristas on shift
+}
+
+var coffees = map[string]bool{
+	"Espresso": true, "Americano": true, "Latte": true, "Cappuccino": true, "Mocha": true,
+}
+
+// === Features ===
+
+// An order is described by how many of each item and customization it
+// has, plus the work waiting in front of it. The estimate is the sum of
+// each feature times its learned duration in seconds.
+const queueFeature = "queue: each order ahead, per barista"
+
+func itemFeature(name string) string   { return "item: " + name }
+func customFeature(name string) string { return "extra: " + name }
+
+func features(o CoffeeOrder, c Conditions) map[string]float64 {
+	f := map[string]float64{}
+	for _, item := range o.Items {
+		f[itemFeature(item.Name)] += float64(item.Quantity)
+		for _, custom := range item.Customizations {
+			f[customFeature(custom)] += float64(item.Quantity)
+		}
+	}
+	if c.Staff > 0 {
+		f[queueFeature] = float64(c.Ahead) / float64(c.Staff)
+	}
+	return f
+}
+
+// prior is what the old rules say a feature costs, in seconds
+func prior(feature string) float64 {
+	switch {
+	case feature == queueFeature:
+		return 180 // The old rules' time for one coffee
+	case strings.HasPrefix(feature, "extra: "):
+		return 30
+	case coffees[strings.TrimPrefix(feature, "item: ")]:
+		return 180
+	}
+	return 120
+}
+
+// === Estimator ===
+
+var (
+	ErrNotFinished = errors.New("order has no PaidAt/ReadyAt")
+	ErrBadTimes    = errors.New("order was ready before it was paid for")
+)
+
+type observation struct {
+	features map[string]float64
+	seconds  float64
+}
+
+// Estimator learns durations from finished orders
+type Estimator struct {
+	// Strength is how many orders' worth of evidence the old rules count
+	// for. Higher trusts them longer.
+	Strength float64
+
+	history []observation
+	weights map[string]float64 // Seconds per feature
+	spread  float64            // Typical error in seconds
+	fitted  bool
+}
+
+func NewEstimator() *Estimator {
+	return &Estimator{Strength: 5}
+}
+
+// Observe learns from a finished order
+func (e *Estimator) Observe(o CoffeeOrder, c Conditions) error {
+	if o.PaidAt == nil || o.ReadyAt == nil {
+		return fmt.Errorf("order #%d: %w", o.ID, ErrNotFinished)
+	}
+	took := o.ReadyAt.Sub(*o.PaidAt)
+	if took < 0 {
+		return fmt.Errorf("order #%d: %w", o.ID, ErrBadTimes)
+	}
+
+	e.history = append(e.history, observation{features(o, c), took.Seconds()})
+	e.fitted = false
+	return nil
+}
+
+// Observed is how many orders the estimator has learned from
+func (e *Estimator) Observed() int {
+	return len(e.history)
+}
+
+// fit solves for the durations that best explain the history while
+// staying near the prior: minimize |Xw - y|² + Strength·|w - prior|²
+func (e *Estimator) fit() {
+	if e.fitted {
+		return
+	}
+	e.fitted = true
+
+	names := map[string]int{}
+	var order []string
+	for _, obs := range e.history {
+		for name := range obs.features {
+			if _, ok := names[name]; !ok {
+				names[name] = len(order)
+				order = append(order, name)
+			}
+		}
+	}
+
+	n := len(order)
+	a := make([][]float64, n)
+	b := make([]float64, n)
+	for i, name := range order {
+		a[i] = make([]float64, n)
+		a[i][i] = e.Strength
+		b[i] = e.Strength * prior(name)
+	}
+	for _, obs := range e.history {
+		for fi, xi := range obs.features {
+			i := names[fi]
+			b[i] += xi * obs.seconds
+			for fj, xj := range obs.features {
+				a[i][names[fj]] += xi * xj
+			}
+		}
+	}
+
+	w := solve(a, b)
+	e.weights = map[string]float64{}
+	for i, name := range order {
+		e.weights[name] = w[i]
+	}
+
+	var squares float64
+	for _, obs := range e.history {
+		diff := e.seconds(obs.features) - obs.seconds
+		squares += diff * diff
+	}
+	e.spread = 60 // A minute either way until there's history
+	if len(e.history) > n {
+		e.spread = math.Sqrt(squares / float64(len(e.history)-n))
+	}
+}
+
+func (e *Estimator) seconds(f map[string]float64) float64 {
+	total := 0.0
+	for name, x := range f {
+		w, ok := e.weights[name]
+		if !ok {
+			w = prior(name) // Never seen: fall back to the old rules
+		}
+		total += x * w
+	}
+	return total
+}
+
+// Duration is what the estimator has learned for a feature, or the old
+// rule if it hasn't seen it
+func (e *Estimator) Duration(feature string) time.Duration {
+	e.fit()
+	w, ok := e.weights[feature]
+	if !ok {
+		w = prior(feature)
+	}
+	return time.Duration(w * float64(time.Second)).Round(time.Second)
+}
+
+// Estimate is how long from payment until the order is ready
+func (e *Estimator) Estimate(o CoffeeOrder, c Conditions) time.Duration {
+	e.fit()
+	return time.Duration(max(e.seconds(features(o, c)), 30) * float64(time.Second))
+}
+
+// Quote is what the customer is told
+type Quote struct {
+	Ready    time.Time
+	Earliest time.Time
+	Latest   time.Time
+}
+
+func (q Quote) String() string {
+	return fmt.Sprintf("ready around %s (%s-%s)",
+		q.Ready.Format("3:04"), q.Earliest.Format("3:04"), q.Latest.Format("3:04 PM"))
+}
+
+// Quote gives a ready time rounded up to the minute, with a range that
+// should hold for about four customers in five
+func (e *Estimator) Quote(o CoffeeOrder, c Conditions, now time.Time) Quote {
+	estimate := e.Estimate(o, c)
+	margin := time.Duration(1.28 * e.spread * float64(time.Second))
+
+	roundUp := func(t time.Time) time.Time {
+		if r := t.Truncate(time.Minute); r.Before(t) {
+			return r.Add(time.Minute)
+		}
+		return t
+	}
+	return Quote{
+		Ready:    roundUp(now.Add(estimate)),
+		Earliest: now.Add(max(estimate-margin, 0)).Truncate(time.Minute),
+		Latest:   roundUp(now.Add(estimate + margin)),
+	}
+}
+
+// solve does Gaussian elimination with partial pivoting on a small,
+// well-conditioned system
+func solve(a [][]float64, b []float64) []float64 {
+	n := len(b)
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		b[col], b[pivot] = b[pivot], b[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := a[row][col] / a[col][col]
+			for k := col; k < n; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+			b[row] -= factor * b[col]
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := b[row]
+		for k := row + 1; k < n; k++ {
+			sum -= a[row][k] * x[k]
+		}
+		x[row] = sum / a[row][row]
+	}
+	return x
+}
+
+// oldEstimate is estimatePreparationTime from 09_real_world_examples.go
+func oldEstimate(o CoffeeOrder) time.Duration {
+	total := time.Duration(0)
+	for _, item := range o.Items {
+		t := 2 * time.Minute * time.Duration(item.Quantity)
+		t += 30 * time.Second * time.Duration(len(item.Customizations))
+		if coffees[item.Name] {
+			t += time.Minute
+		}
+		total += t
+	}
+	return total
+}
+
+// === Simulated history ===
+
+// How long things really take at the Seattle store, in seconds. The
+// estimator never sees this table; it only sees timestamps.
+var (
+	trueItems = map[string]float64{
+		"Espresso": 60, "Americano": 90, "Latte": 150, "Cappuccino": 165, "Mocha": 200,
+		"Croissant": 45, "Muffin": 20, "Sandwich": 240,
+	}
+	trueCustoms = map[string]float64{
+		"Extra Shot": 25, "Oat Milk": 10, "Vanilla": 15, "Extra Hot": 40, "Decaf": 5,
+	}
+	trueQueue = 110.0
+)
+
+var opening = time.Date(2024, time.March, 11, 7, 0, 0, 0, time.Local)
+
+func randomOrder(rng *rand.Rand, id int) (CoffeeOrder, Conditions) {
+	names := []string{"Espresso", "Americano", "Latte", "Latte", "Cappuccino", "Mocha", "Croissant", "Muffin", "Sandwich"}
+	customs := []string{"Extra Shot", "Oat Milk", "Vanilla", "Extra Hot", "Decaf"}
+
+	o := CoffeeOrder{ID: id}
+	for i := rng.Intn(3) + 1; i > 0; i-- {
+		item := OrderItem{Name: names[rng.Intn(len(names))], Quantity: 1 + rng.Intn(4)/3}
+		if coffees[item.Name] {
+			for j := rng.Intn(3); j > 0; j-- {
+				item.Customizations = append(item.Customizations, customs[rng.Intn(len(customs))])
+			}
+		}
+		o.Items = append(o.Items, item)
+	}
+	return o, Conditions{Ahead: rng.Intn(9), Staff: 1 + rng.Intn(3)}
+}
+
+// fulfil stamps PaidAt and ReadyAt the way the real shop would have
+func fulfil(rng *rand.Rand, o *CoffeeOrder, c Conditions) {
+	seconds := trueQueue * float64(c.Ahead) / float64(c.Staff)
+	for _, item := range o.Items {
+		seconds += trueItems[item.Name] * float64(item.Quantity)
+		for _, custom := range item.Customizations {
+			seconds += trueCustoms[custom] * float64(item.Quantity)
+		}
+	}
+	seconds = max(seconds+rng.NormFloat64()*30, 20)
+
+	paid := opening.Add(time.Duration(o.ID) * 20 * time.Second)
+	ready := paid.Add(time.Duration(seconds * float64(time.Second)))
+	o.PaidAt, o.ReadyAt = &paid, &ready
+}
+
+func history(seed int64, count int) ([]CoffeeOrder, []Conditions) {
+	rng := rand.New(rand.NewSource(seed))
+	orders := make([]CoffeeOrder, count)
+	conditions := make([]Conditions, count)
+	for i := range orders {
+		orders[i], conditions[i] = randomOrder(rng, i+1)
+		fulfil(rng, &orders[i], conditions[i])
+	}
+	return orders, conditions
+}
+
+// meanError is the average miss, in seconds, of an estimate over orders
+func meanError(orders []CoffeeOrder, conditions []Conditions, estimate func(CoffeeOrder, Conditions) time.Duration) float64 {
+	total := 0.0
+	for i, o := range orders {
+		actual := o.ReadyAt.Sub(*o.PaidAt)
+		total += math.Abs((estimate(o, conditions[i]) - actual).Seconds())
+	}
+	return total / float64(len(orders))
+}
+
+func describe(o CoffeeOrder) string {
+	var parts []string
+	for _, item := range o.Items {
+		s := fmt.Sprintf("%d %s", item.Quantity, item.Name)
+		if len(item.Customizations) > 0 {
+			s += " (" + strings.Join(item.Customizations, ", ") + ")"
+		}
+		parts = append(parts, s)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Preparation-Time Estimator ===")
+	fmt.Println()
+
+	train, trainConditions := history(1, 400)
+	test, testConditions := history(2, 150)
+
+	// 1. Learn from finished orders; unfinished ones are skipped
+	estimator := NewEstimator()
+	for i, o := range train {
+		estimator.Observe(o, trainConditions[i])
+	}
+	inProgress := CoffeeOrder{ID: 401, Items: []OrderItem{{Name: "Latte", Quantity: 1}}, PaidAt: train[0].PaidAt}
+	err := estimator.Observe(inProgress, Conditions{Ahead: 2, Staff: 2})
+	fmt.Printf("1. Learned from %d orders (an order still being made: %v)\n", estimator.Observed(), err)
+
+	// 2. What it learned, next to the old rules
+	fmt.Println("\n2. Learned durations")
+	fmt.Printf("  %-38s %8s %8s %8s\n", "", "Old rule", "Learned", "Actual")
+	show := func(feature string, actual float64) {
+		fmt.Printf("  %-38s %8v %8v %8v\n", feature,
+			time.Duration(prior(feature)*float64(time.Second)),
+			estimator.Duration(feature),
+			time.Duration(actual*float64(time.Second)))
+	}
+	var items, customs []string
+	for name := range trueItems {
+		items = append(items, name)
+	}
+	for name := range trueCustoms {
+		customs = append(customs, name)
+	}
+	sort.Strings(items)
+	sort.Strings(customs)
+	for _, name := range items {
+		show(itemFeature(name), trueItems[name])
+	}
+	for _, name := range customs {
+		show(customFeature(name), trueCustoms[name])
+	}
+	show(queueFeature, trueQueue)
+
+	// 3. Accuracy on orders it hasn't seen
+	old := meanError(test, testConditions, func(o CoffeeOrder, _ Conditions) time.Duration { return oldEstimate(o) })
+	learned := meanError(test, testConditions, estimator.Estimate)
+	fmt.Printf("\n3. Average miss on %d new orders: old rules %.0fs, estimator %.0fs\n", len(test), old, learned)
+
+	// 4. The old rules are the fallback while history is short
+	fmt.Println("\n4. Average miss by how much history it has")
+	for _, n := range []int{0, 5, 20, 100, 400} {
+		e := NewEstimator()
+		for i := 0; i < n; i++ {
+			e.Observe(train[i], trainConditions[i])
+		}
+		fmt.Printf("  %3d orders: %4.0fs\n", n, meanError(test, testConditions, e.Estimate))
+	}
+
+	// 5. Quotes at the register
+	fmt.Println("\n5. Quotes at 8:05 AM")
+	now := time.Date(2024, time.March, 12, 8, 5, 0, 0, time.Local)
+	order := CoffeeOrder{ID: 1001, Items: []OrderItem{
+		{Name: "Latte", Quantity: 2, Customizations: []string{"Oat Milk", "Extra Shot"}},
+		{Name: "Croissant", Quantity: 1},
+	}}
+	fmt.Printf("  Order: %s (old rule: %v whatever the line)\n", describe(order), oldEstimate(order))
+	for _, c := range []Conditions{{Ahead: 0, Staff: 2}, {Ahead: 6, Staff: 2}, {Ahead: 6, Staff: 3}} {
+		fmt.Printf("  %d ahead, %d on shift: %s\n", c.Ahead, c.Staff, estimator.Quote(order, c, now))
+	}
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-tutorial/04-functions/02-parameters-returns/shoperr"
+)
+
+// 06_error_returns.go has ValidationError and BusinessError as value types
+// that handleErrorTypes picks apart by hand, and an error made with
+// fmt.Errorf can only be matched by its text.
+//
+// The shoperr package (in ./shoperr) is one error taxonomy for the whole
+// shop: sentinel kinds with stable codes, *shoperr.Error for where it
+// happened and the details, and shoperr.Wrap to add context on the way up.
+// This file is the shop using it; createOrder, processOrderPayment and
+// findCustomerByID in 09_real_world_examples.go use it too.
+
+// === The shop, using them ===
+
+type Customer struct {
+	ID   string
+	Name string
+}
+
+type Status string
+
+const (
+	StatusNew       Status = "New"
+	StatusPaid      Status = "Paid"
+	StatusPreparing Status = "Preparing"
+	StatusReady     Status = "Ready"
+	StatusCompleted Status = "Completed"
+	StatusCancelled Status = "Cancelled"
+)
+
+var transitions = map[Status][]Status{
+	StatusNew:       {StatusPaid, StatusCancelled},
+	StatusPaid:      {StatusPreparing, StatusCancelled},
+	StatusPreparing: {StatusReady},
+	StatusReady:     {StatusCompleted},
+}
+
+type Order struct {
+	ID         string
+	CustomerID string
+	Items      map[string]int
+	Total      float64
+	Status     Status
+}
+
+// ErrCardDeclined is what the card network says; the shop turns it into
+// shoperr.ErrPaymentDeclined but keeps it as the cause
+var ErrCardDeclined = errors.New("card network: insufficient funds")
+
+type Shop struct {
+	customers []Customer
+	stock     map[string]int
+	prices    map[string]float64
+	orders    map[string]*Order
+	next      int
+}
+
+func findCustomerByID(customers []Customer, id string) (*Customer, error) {
+	for i := range customers {
+		if customers[i].ID == id {
+			return &customers[i], nil
+		}
+	}
+	return nil, shoperr.E(shoperr.ErrNotFound, "customer.find", "customer "+id, "customer_id", id)
+}
+
+func (s *Shop) createOrder(customerID string, items map[string]int) (*Order, error) {
+	const op = "order.create"
+	if _, err := findCustomerByID(s.customers, customerID); err != nil {
+		return nil, shoperr.Wrap(op, err)
+	}
+
+	names := make([]string, 0, len(items))
+	for name := range items {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	total := 0.0
+	for _, name := range names {
+		qty := items[name]
+		price, ok := s.prices[name]
+		switch {
+		case qty <= 0:
+			return nil, shoperr.E(shoperr.ErrInvalidInput, op, fmt.Sprintf("quantity %d for %s", qty, name), "item", name, "quantity", qty)
+		case !ok:
+			return nil, shoperr.E(shoperr.ErrNotFound, op, "menu item "+name, "item", name)
+		case s.stock[name] < qty:
+			return nil, shoperr.E(shoperr.ErrOutOfStock, op, fmt.Sprintf("%s: need %d, have %d", name, qty, s.stock[name]),
+				"item", name, "need", qty, "have", s.stock[name])
+		}
+		total += price * float64(qty)
+	}
+	for _, name := range names {
+		s.stock[name] -= items[name]
+	}
+
+	s.next++
+	order := &Order{ID: fmt.Sprintf("ORD-%03d", s.next), CustomerID: customerID, Items: items, Total: total, Status: StatusNew}
+	s.orders[order.ID] = order
+	return order, nil
+}
+
+func (s *Shop) advance(orderID string, to Status) error {
+	const op = "order.advance"
+	order, ok := s.orders[orderID]
+	if !ok {
+		return shoperr.E(shoperr.ErrNotFound, op, "order "+orderID, "order_id", orderID)
+	}
+	for _, allowed := range transitions[order.Status] {
+		if allowed == to {
+			order.Status = to
+			return nil
+		}
+	}
+	return shoperr.E(shoperr.ErrInvalidTransition, op, fmt.Sprintf("%s → %s", order.Status, to),
+		"order_id", orderID, "from", order.Status, "to", to)
+}
+
+// charge is the card network
+func charge(card string, amount float64) error {
+	if strings.HasSuffix(card, "0000") {
+		return ErrCardDeclined
+	}
+	return nil
+}
+
+func (s *Shop) processOrderPayment(orderID, card string) error {
+	const op = "payment.process"
+	order, ok := s.orders[orderID]
+	if !ok {
+		return shoperr.E(shoperr.ErrNotFound, op, "order "+orderID, "order_id", orderID)
+	}
+	if err := charge(card, order.Total); err != nil {
+		return shoperr.E(shoperr.ErrPaymentDeclined, op, fmt.Sprintf("$%.2f", order.Total),
+			"order_id", orderID, "amount", order.Total).Because(err)
+	}
+	return shoperr.Wrap(op, s.advance(orderID, StatusPaid))
+}
+
+// cancel calls off an unpaid or paid order and puts its items back in
+// stock
+func (s *Shop) cancel(orderID string) error {
+	if err := s.advance(orderID, StatusCancelled); err != nil {
+		return shoperr.Wrap("order.cancel", err)
+	}
+	for name, qty := range s.orders[orderID].Items {
+		s.stock[name] += qty
+	}
+	return nil
+}
+
+// checkout is the register: create, then pay. Errors pick up both ops. An
+// order that can't be paid for is cancelled, so its stock isn't held by an
+// order nobody will collect.
+func (s *Shop) checkout(customerID string, items map[string]int, card string) (*Order, error) {
+	const op = "register.checkout"
+	order, err := s.createOrder(customerID, items)
+	if err != nil {
+		return nil, shoperr.Wrap(op, err)
+	}
+	if err := s.processOrderPayment(order.ID, card); err != nil {
+		if cancelErr := s.cancel(order.ID); cancelErr != nil {
+			return order, shoperr.Wrap(op, errors.Join(err, cancelErr))
+		}
+		return order, shoperr.Wrap(op, err)
+	}
+	return order, nil
+}
+
+// === Demo ===
+
+func main() {
+	fmt.Println("=== GoCoffee Domain Errors ===")
+	fmt.Println()
+
+	shop := &Shop{
+		customers: []Customer{{"CUST-001", "Alice"}, {"CUST-002", "Bob"}},
+		stock:     map[string]int{"Latte": 10, "Croissant": 1, "Oat Milk": 0},
+		prices:    map[string]float64{"Latte": 4.50, "Croissant": 3.25, "Oat Milk": 0.75},
+		orders:    map[string]*Order{},
+	}
+
+	// 1. Every failure has a kind, a code and details
+	fmt.Println("1. Checkouts")
+	type attempt struct {
+		customer string
+		items    map[string]int
+		card     string
+	}
+	var failures []error
+	for _, a := range []attempt{
+		{"CUST-001", map[string]int{"Latte": 2}, "4111111111111111"},
+		{"CUST-404", map[string]int{"Latte": 1}, "4111111111111111"},
+		{"CUST-002", map[string]int{"Croissant": 2, "Latte": 1}, "4111111111111111"},
+		{"CUST-002", map[string]int{"Latte": 1, "Oat Milk": 1}, "4111111111111111"},
+		{"CUST-002", map[string]int{"Mocha": 1}, "4111111111111111"},
+		{"CUST-002", map[string]int{"Latte": 1}, "4000000000000000"},
+	} {
+		order, err := shop.checkout(a.customer, a.items, a.card)
+		if err != nil {
+			fmt.Printf("  ❌ %-18s %v\n", shoperr.CodeOf(err), err)
+			if order != nil {
+				fmt.Printf("     %s is %s; Latte stock is back to %d\n", order.ID, order.Status, shop.stock["Latte"])
+			}
+			failures = append(failures, err)
+			continue
+		}
+		fmt.Printf("  ✅ %s paid $%.2f\n", order.ID, order.Total)
+	}
+
+	// 2. Callers branch on kinds, not messages
+	fmt.Println("\n2. What the register does about each")
+	for _, err := range failures {
+		var domain *shoperr.Error
+		switch {
+		case errors.Is(err, shoperr.ErrOutOfStock) && errors.As(err, &domain):
+			fmt.Printf("  %-18s → offer something else instead of %v\n", shoperr.CodeOf(err), shoperr.DetailsOf(err)["item"])
+		case errors.Is(err, ErrCardDeclined):
+			fmt.Printf("  %-18s → the card network declined it; ask for another card\n", shoperr.CodeOf(err))
+		case errors.Is(err, shoperr.ErrNotFound):
+			fmt.Printf("  %-18s → check the %s\n", shoperr.CodeOf(err), strings.Join(keys(shoperr.DetailsOf(err)), ", "))
+		default:
+			fmt.Printf("  %-18s → %v\n", shoperr.CodeOf(err), err)
+		}
+	}
+
+	// 3. Status changes the order's lifecycle doesn't allow
+	fmt.Println("\n3. Status changes")
+	for _, to := range []Status{StatusPreparing, StatusReady, StatusPaid, StatusCompleted} {
+		if err := shop.advance("ORD-001", to); err != nil {
+			var domain *shoperr.Error
+			errors.As(err, &domain)
+			fmt.Printf("  → %-9s ❌ %v (from %v)\n", to, err, domain.Details["from"])
+			continue
+		}
+		fmt.Printf("  → %-9s ✅\n", to)
+	}
+
+	// 4. The same errors in an API response and a log line
+	fmt.Println("\n4. API responses and logs")
+	outOfStock := failures[1]
+	declined := failures[len(failures)-1]
+	for _, err := range []error{outOfStock, declined, shoperr.Wrap("report.daily", errors.New("disk full"))} {
+		status, body := shoperr.ToAPI(err)
+		data, _ := json.Marshal(body)
+		fmt.Printf("  HTTP %d %s\n", status, data)
+		fmt.Printf("  log: %s\n\n", shoperr.LogLine(err))
+	}
+}
+
+func keys(m map[string]any) []string {
+	var ks []string
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
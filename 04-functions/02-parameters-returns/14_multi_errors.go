@@ -0,0 +1,189 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go-tutorial/04-functions/02-parameters-returns/multierr"
+)
+
+// The shop collects errors four different ways: MergeErrors in
+// 03-variadic-functions/08_best_practices.go glues messages into one
+// string, processMultipleItems returns ([]string, []error),
+// processItemsWithErrors in 06_error_returns.go returned a bare []error,
+// and SendNotifications returns []error too. None of them say which line
+// of the order a problem is on, and none work with errors.Is.
+//
+// multierr.Errors (in ./multierr) is the one way. It's a list of errors
+// with the same meaning as errors.Join: errors.Is and errors.As look
+// through every member. multierr.FieldError adds which line and which
+// field each problem is about, so order validation can report everything
+// wrong in one go. 06_error_returns.go uses it too.
+
+// === Order validation ===
+
+var (
+	ErrRequired    = errors.New("is required")
+	ErrUnknown     = errors.New("is not on the menu")
+	ErrOutOfRange  = errors.New("is out of range")
+	ErrUnavailable = errors.New("is not available for this drink")
+)
+
+type OrderLine struct {
+	Item     string
+	Size     string
+	Quantity int
+	Extras   []string
+}
+
+type Order struct {
+	Customer string
+	Pickup   string
+	Lines    []OrderLine
+}
+
+var menu = map[string]struct {
+	sized  bool
+	extras bool
+}{
+	"Espresso":   {false, true},
+	"Latte":      {true, true},
+	"Cappuccino": {true, true},
+	"Croissant":  {false, false},
+	"Muffin":     {false, false},
+}
+
+var sizes = map[string]bool{"Small": true, "Medium": true, "Large": true}
+
+var extras = map[string]bool{"Extra Shot": true, "Oat Milk": true, "Vanilla": true}
+
+// validateOrder reports every problem with an order, not just the first
+func validateOrder(o Order) error {
+	var errs multierr.Errors
+
+	if strings.TrimSpace(o.Customer) == "" {
+		errs.Field(0, "", "customer", ErrRequired)
+	}
+	if len(o.Lines) == 0 {
+		errs.Field(0, "", "lines", ErrRequired)
+	}
+
+	for i, l := range o.Lines {
+		n := i + 1
+		item, onMenu := menu[l.Item]
+		if !onMenu {
+			errs.Field(n, l.Item, "item", fmt.Errorf("%q %w", l.Item, ErrUnknown))
+			continue
+		}
+		if l.Quantity < 1 || l.Quantity > 20 {
+			errs.Field(n, l.Item, "quantity", fmt.Errorf("%d %w (1-20)", l.Quantity, ErrOutOfRange))
+		}
+		switch {
+		case item.sized && l.Size == "":
+			errs.Field(n, l.Item, "size", ErrRequired)
+		case item.sized && !sizes[l.Size]:
+			errs.Field(n, l.Item, "size", fmt.Errorf("%q %w", l.Size, ErrUnknown))
+		case !item.sized && l.Size != "":
+			errs.Field(n, l.Item, "size", fmt.Errorf("%q %w", l.Size, ErrUnavailable))
+		}
+		for _, extra := range l.Extras {
+			switch {
+			case !item.extras:
+				errs.Field(n, l.Item, "extras", fmt.Errorf("%q %w", extra, ErrUnavailable))
+			case !extras[extra]:
+				errs.Field(n, l.Item, "extras", fmt.Errorf("%q %w", extra, ErrUnknown))
+			}
+		}
+	}
+	return errs.Err()
+}
+
+// checkStock is a second, independent check; its errors join validation's
+func checkStock(o Order, stock map[string]int) error {
+	var errs multierr.Errors
+	for i, l := range o.Lines {
+		if have, tracked := stock[l.Item]; tracked && have < l.Quantity {
+			errs.Field(i+1, l.Item, "quantity", fmt.Errorf("only %d left", have))
+		}
+	}
+	return errs.Err()
+}
+
+// === Demo ===
+
+func main() {
+	fmt.Println("=== GoCoffee Multi-Error Validation ===")
+	fmt.Println()
+
+	bad := Order{
+		Customer: " ",
+		Lines: []OrderLine{
+			{Item: "Latte", Size: "Large", Quantity: 2, Extras: []string{"Oat Milk"}},
+			{Item: "Cappuccino", Quantity: 0},
+			{Item: "Croissant", Size: "Large", Quantity: 1, Extras: []string{"Vanilla"}},
+			{Item: "Mochaccino", Size: "Small", Quantity: 1},
+			{Item: "Espresso", Quantity: 1, Extras: []string{"Whipped Cream"}},
+		},
+	}
+
+	// 1. Every problem at once
+	err := validateOrder(bad)
+	fmt.Println("1. Validating an order with several mistakes")
+	fmt.Println(err)
+
+	// 2. errors.Is and errors.As search every member
+	fmt.Println("\n2. Asking about it")
+	for _, target := range []error{ErrRequired, ErrUnknown, ErrOutOfRange, ErrUnavailable} {
+		fmt.Printf("  errors.Is(err, %q): %t\n", target, errors.Is(err, target))
+	}
+	var first *multierr.FieldError
+	if errors.As(err, &first) {
+		fmt.Printf("  errors.As finds the first field error: line %d, %s\n", first.Line, first.Field)
+	}
+
+	// 3. Grouped for the order screen
+	fmt.Println("\n3. By line, for the order screen")
+	lines := multierr.ByLine(err)
+	var numbers []int
+	for n := range lines {
+		numbers = append(numbers, n)
+	}
+	sort.Ints(numbers)
+	for _, n := range numbers {
+		label := "Order"
+		if n > 0 {
+			label = fmt.Sprintf("Line %d", n)
+		}
+		var fields []string
+		for _, fe := range lines[n] {
+			fields = append(fields, fe.Field)
+		}
+		fmt.Printf("  %-7s highlight %s\n", label, strings.Join(fields, ", "))
+	}
+
+	// 4. Combining checks. Another collection is flattened; an errors.Join
+	// from elsewhere stays one member, its two causes together.
+	fmt.Println("\n4. Validation plus stock, plus an errors.Join from elsewhere")
+	good := Order{Customer: "Alice", Lines: []OrderLine{
+		{Item: "Latte", Size: "Medium", Quantity: 3},
+		{Item: "Croissant", Quantity: 2},
+	}}
+	stock := map[string]int{"Croissant": 1}
+	payment := errors.Join(errors.New("card expired"), errors.New("billing zip missing"))
+	combined := multierr.Append(validateOrder(good), checkStock(good, stock), payment)
+	fmt.Println(combined)
+	var all *multierr.Errors
+	if errors.As(combined, &all) {
+		fmt.Printf("  %d members, the errors.Join being one of them\n", all.Len())
+	}
+
+	// 5. Nothing wrong means a plain nil
+	fmt.Println("\n5. A good order")
+	good.Lines[1].Quantity = 1
+	err = multierr.Append(validateOrder(good), checkStock(good, stock))
+	fmt.Printf("  err == nil: %t\n", err == nil)
+	single := multierr.Append(nil, checkStock(Order{Lines: []OrderLine{{Item: "Croissant", Quantity: 5}}}, stock), nil)
+	fmt.Printf("  One problem stays one error: %v (%T)\n", single, single)
+}
@@ -0,0 +1,140 @@
+// Package multierr collects several errors into one. Errors has the same
+// meaning as errors.Join: errors.Is and errors.As look through every
+// member. FieldError adds which line and which field each problem is
+// about, so a validator can report everything wrong in one go.
+package multierr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// === Field errors ===
+
+// FieldError is a problem with one field of one line. Line is 1-based;
+// 0 means the order as a whole.
+type FieldError struct {
+	Line  int
+	Item  string // What's on the line, to help people find it
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	var where []string
+	if e.Line > 0 {
+		line := fmt.Sprintf("line %d", e.Line)
+		if e.Item != "" {
+			line += " (" + e.Item + ")"
+		}
+		where = append(where, line)
+	}
+	if e.Field != "" {
+		where = append(where, e.Field)
+	}
+	problem := "invalid"
+	if e.Err != nil {
+		problem = e.Err.Error()
+	}
+	if len(where) == 0 {
+		return problem
+	}
+	return strings.Join(where, ": ") + ": " + problem
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// === Errors ===
+
+// Errors collects errors. The zero value is ready to use.
+type Errors struct {
+	errs []error
+}
+
+// Add appends errors, skipping nils and flattening another Errors. Any
+// other error that wraps several, like an errors.Join or a domain error
+// that unwraps to its kind and its cause, stays one member: splitting it
+// would lose the message that ties its parts together.
+func (e *Errors) Add(errs ...error) {
+	for _, err := range errs {
+		switch inner := err.(type) {
+		case nil:
+		case *Errors:
+			e.errs = append(e.errs, inner.errs...)
+		default:
+			e.errs = append(e.errs, err)
+		}
+	}
+}
+
+// Field records a problem on a line's field. A nil err records nothing,
+// so a validator's result can be passed straight in.
+func (e *Errors) Field(line int, item, field string, err error) {
+	if err == nil {
+		return
+	}
+	e.Add(&FieldError{Line: line, Item: item, Field: field, Err: err})
+}
+
+func (e *Errors) Len() int { return len(e.errs) }
+
+// Err returns nil if nothing was collected, one error on its own, or the
+// collection. Return Err(), never the *Errors itself: a nil *Errors in an
+// error interface isn't == nil.
+func (e *Errors) Err() error {
+	switch len(e.errs) {
+	case 0:
+		return nil
+	case 1:
+		return e.errs[0]
+	}
+	return e
+}
+
+func (e *Errors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d problems:", len(e.errs))
+	for _, err := range e.errs {
+		// A member with several lines of its own, like an errors.Join,
+		// stays indented under its bullet
+		b.WriteString("\n  - " + strings.ReplaceAll(err.Error(), "\n", "\n    "))
+	}
+	return b.String()
+}
+
+// Unwrap is what makes errors.Is and errors.As search every member
+func (e *Errors) Unwrap() []error {
+	return e.errs
+}
+
+// Append is MergeErrors done right: nil if every err is nil, the error
+// itself if there's one, a collection otherwise
+func Append(errs ...error) error {
+	var all Errors
+	all.Add(errs...)
+	return all.Err()
+}
+
+// ByLine groups the field errors in err by line, 0 for order-level ones.
+// It looks through wrapping of both kinds, so a FieldError inside an
+// fmt.Errorf("...: %w") or an errors.Join is still found.
+func ByLine(err error) map[int][]*FieldError {
+	lines := map[int][]*FieldError{}
+	var walk func(error)
+	walk = func(err error) {
+		if fe, ok := err.(*FieldError); ok {
+			lines[fe.Line] = append(lines[fe.Line], fe)
+			return
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		}
+	}
+	walk(err)
+	return lines
+}
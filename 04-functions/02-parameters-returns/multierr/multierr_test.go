@@ -0,0 +1,151 @@
+package multierr
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+var (
+	errRequired = errors.New("is required")
+	errUnknown  = errors.New("is not on the menu")
+)
+
+// pair unwraps to two errors, like a domain error holding its kind and
+// its cause
+type pair struct{ kind, cause error }
+
+func (p pair) Error() string   { return p.kind.Error() + ": " + p.cause.Error() }
+func (p pair) Unwrap() []error { return []error{p.kind, p.cause} }
+
+func TestErrSizes(t *testing.T) {
+	var none Errors
+	if err := none.Err(); err != nil {
+		t.Errorf("empty Err() = %v, want nil", err)
+	}
+
+	var one Errors
+	one.Add(nil, errRequired, nil)
+	if err := one.Err(); err != errRequired {
+		t.Errorf("single Err() = %v, want the error itself", err)
+	}
+
+	var two Errors
+	two.Add(errRequired, errUnknown)
+	if err := two.Err(); err != &two {
+		t.Errorf("Err() = %T, want the collection", err)
+	}
+}
+
+func TestFieldSkipsNil(t *testing.T) {
+	validate := func(size string) error {
+		if size == "" {
+			return errRequired
+		}
+		return nil
+	}
+
+	var errs Errors
+	errs.Field(1, "Latte", "size", validate("Large"))
+	if err := errs.Err(); err != nil {
+		t.Fatalf("Err() after a passing check = %v, want nil", err)
+	}
+	errs.Field(2, "Mocha", "size", validate(""))
+	if got, want := errs.Err().Error(), "line 2 (Mocha): size: is required"; got != want {
+		t.Errorf("Err() = %q, want %q", got, want)
+	}
+}
+
+func TestFieldErrorWithoutErr(t *testing.T) {
+	fe := &FieldError{Line: 3, Item: "Bagel", Field: "extras"}
+	if got, want := fe.Error(), "line 3 (Bagel): extras: invalid"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got := (&FieldError{}).Error(); got != "invalid" {
+		t.Errorf("empty FieldError = %q, want %q", got, "invalid")
+	}
+}
+
+func TestAddFlattensOnlyErrors(t *testing.T) {
+	var inner Errors
+	inner.Add(errRequired, errUnknown)
+	joined := errors.Join(errors.New("card expired"), errors.New("billing zip missing"))
+	domain := pair{errors.New("payment declined"), errors.New("insufficient funds")}
+
+	var all Errors
+	all.Add(&inner, joined, domain)
+
+	want := []error{errRequired, errUnknown, joined, domain}
+	if !reflect.DeepEqual(all.Unwrap(), want) {
+		t.Errorf("members = %v, want %v", all.Unwrap(), want)
+	}
+}
+
+func TestIsAndAsSearchEveryMember(t *testing.T) {
+	var errs Errors
+	errs.Field(0, "", "customer", errRequired)
+	errs.Field(2, "Mocha", "item", fmt.Errorf("%q %w", "Mocha", errUnknown))
+	err := errs.Err()
+
+	for _, target := range []error{errRequired, errUnknown} {
+		if !errors.Is(err, target) {
+			t.Errorf("errors.Is(err, %v) = false", target)
+		}
+	}
+	var fe *FieldError
+	if !errors.As(err, &fe) || fe.Field != "customer" {
+		t.Errorf("errors.As found %+v, want the first field error", fe)
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	var errs Errors
+	errs.Field(0, "", "customer", errRequired)
+	errs.Field(3, "Croissant", "size", errors.New(`"Large" is not available`))
+	errs.Add(errors.Join(errors.New("card expired"), errors.New("billing zip missing")))
+
+	want := `3 problems:
+  - customer: is required
+  - line 3 (Croissant): size: "Large" is not available
+  - card expired
+    billing zip missing`
+	if got := errs.Error(); got != want {
+		t.Errorf("Error() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAppend(t *testing.T) {
+	if err := Append(nil, nil); err != nil {
+		t.Errorf("Append(nil, nil) = %v, want nil", err)
+	}
+	if err := Append(nil, errRequired); err != errRequired {
+		t.Errorf("Append(nil, err) = %v, want err", err)
+	}
+	var all *Errors
+	if err := Append(errRequired, errUnknown); !errors.As(err, &all) || all.Len() != 2 {
+		t.Errorf("Append(a, b) = %v, want a collection of 2", err)
+	}
+}
+
+func TestByLine(t *testing.T) {
+	var errs Errors
+	errs.Field(0, "", "customer", errRequired)
+	errs.Field(2, "Latte", "size", errRequired)
+	stock := &FieldError{Line: 2, Item: "Latte", Field: "quantity", Err: errors.New("only 1 left")}
+	extras := &FieldError{Line: 4, Item: "Espresso", Field: "extras", Err: errUnknown}
+
+	// One field error behind a %w, another inside an errors.Join
+	err := Append(errs.Err(), fmt.Errorf("stock: %w", stock), errors.Join(errors.New("card expired"), extras))
+
+	got := map[int][]string{}
+	for line, fes := range ByLine(err) {
+		for _, fe := range fes {
+			got[line] = append(got[line], fe.Field)
+		}
+	}
+	want := map[int][]string{0: {"customer"}, 2: {"size", "quantity"}, 4: {"extras"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ByLine fields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,213 @@
+// Package shoperr is one error taxonomy for the whole shop:
+//   - Sentinel errors say what kind of failure it was (ErrNotFound,
+//     ErrOutOfStock, ErrPaymentDeclined, ErrInvalidTransition, ...), each
+//     with a stable code that's safe to put in an API response or a log.
+//   - *Error adds where it happened and the details, and can wrap the
+//     cause, so errors.Is finds both the kind and the cause, and errors.As
+//     gets the details back.
+//   - Wrap adds context on the way up without losing any of that.
+package shoperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// === Kinds ===
+
+// Code is a stable, machine-readable error code. Messages may be reworded;
+// codes never change.
+type Code string
+
+// Kind is a sentinel error: compare with errors.Is, never by message
+type Kind struct {
+	code    Code
+	message string
+	status  int // HTTP status for API responses
+}
+
+func (k *Kind) Error() string { return k.message }
+func (k *Kind) Code() Code    { return k.code }
+
+var (
+	ErrNotFound          = &Kind{"not_found", "not found", http.StatusNotFound}
+	ErrOutOfStock        = &Kind{"out_of_stock", "out of stock", http.StatusConflict}
+	ErrPaymentDeclined   = &Kind{"payment_declined", "payment declined", http.StatusPaymentRequired}
+	ErrInvalidTransition = &Kind{"invalid_transition", "invalid status change", http.StatusConflict}
+	ErrInvalidInput      = &Kind{"invalid_input", "invalid input", http.StatusBadRequest}
+	ErrInternal          = &Kind{"internal", "internal error", http.StatusInternalServerError}
+)
+
+// === Errors ===
+
+// Error is a domain failure: what kind, where, about what, and why
+type Error struct {
+	Kind    *Kind
+	Op      string         // Where, like "order.create"
+	Message string         // What, for people
+	Details map[string]any // About what, for machines
+	Err     error          // Why, if something else failed first
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	if e.Op != "" {
+		b.WriteString(e.Op + ": ")
+	}
+	// Wrap adds only context; the error it wraps already names the kind.
+	// A cause of another kind still needs this one named.
+	var inner *Kind
+	if e.Message == "" && errors.As(e.Err, &inner) && inner == e.kind() {
+		b.WriteString(e.Err.Error())
+		return b.String()
+	}
+	b.WriteString(e.kind().message)
+	if e.Message != "" {
+		b.WriteString(": " + e.Message)
+	}
+	if e.Err != nil {
+		b.WriteString(": " + e.Err.Error())
+	}
+	return b.String()
+}
+
+// Unwrap lets errors.Is and errors.As see both the kind and the cause
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.kind()}
+	}
+	return []error{e.kind(), e.Err}
+}
+
+func (e *Error) Code() Code { return e.kind().code }
+
+// kind is e.Kind, or ErrInternal for an Error built without one
+func (e *Error) kind() *Kind {
+	if e.Kind == nil {
+		return ErrInternal
+	}
+	return e.Kind
+}
+
+// E builds an error. details are key, value pairs. A nil kind or a key
+// without a value is a bug at the call site, so E panics rather than
+// build an error that loses what it was meant to say.
+func E(kind *Kind, op, message string, details ...any) *Error {
+	if kind == nil {
+		panic(fmt.Sprintf("shoperr.E(%q): nil kind", op))
+	}
+	if len(details)%2 != 0 {
+		panic(fmt.Sprintf("shoperr.E(%q): detail %v has no value", op, details[len(details)-1]))
+	}
+	e := &Error{Kind: kind, Op: op, Message: message}
+	if len(details) > 0 {
+		e.Details = map[string]any{}
+		for i := 0; i < len(details); i += 2 {
+			e.Details[fmt.Sprint(details[i])] = details[i+1]
+		}
+	}
+	return e
+}
+
+// Because records the error that caused this one
+func (e *Error) Because(err error) *Error {
+	e.Err = err
+	return e
+}
+
+// Wrap adds where err passed through, keeping its kind, code and details.
+// Errors that aren't domain errors become ErrInternal.
+func Wrap(op string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var domain *Error
+	if errors.As(err, &domain) {
+		return &Error{Kind: domain.kind(), Op: op, Err: err}
+	}
+	var kind *Kind
+	if errors.As(err, &kind) {
+		return &Error{Kind: kind, Op: op, Err: err}
+	}
+	return &Error{Kind: ErrInternal, Op: op, Err: err}
+}
+
+// CodeOf is the code for any error; ones from outside the domain are
+// "internal"
+func CodeOf(err error) Code {
+	var kind *Kind
+	if errors.As(err, &kind) {
+		return kind.code
+	}
+	if err == nil {
+		return ""
+	}
+	return ErrInternal.code
+}
+
+// DetailsOf merges the details of every domain error in the chain, the
+// outermost winning
+func DetailsOf(err error) map[string]any {
+	details := map[string]any{}
+	var walk func(error)
+	walk = func(err error) {
+		if e, ok := err.(*Error); ok {
+			for k, v := range e.Details {
+				if _, seen := details[k]; !seen {
+					details[k] = v
+				}
+			}
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		}
+	}
+	walk(err)
+	return details
+}
+
+// === Output ===
+
+// APIError is the JSON body an API returns. Internal errors don't leak
+// their message.
+type APIError struct {
+	Code    Code           `json:"code"`
+	Message string         `json:"message"`
+	Details map[string]any `json:"details,omitempty"`
+}
+
+func ToAPI(err error) (int, APIError) {
+	var kind *Kind
+	if !errors.As(err, &kind) || kind == ErrInternal {
+		return http.StatusInternalServerError, APIError{Code: ErrInternal.code, Message: "something went wrong"}
+	}
+	body := APIError{Code: kind.code, Message: err.Error(), Details: DetailsOf(err)}
+	if len(body.Details) == 0 {
+		body.Details = nil
+	}
+	return kind.status, body
+}
+
+// LogLine formats an error as key=value pairs for structured logs
+func LogLine(err error) string {
+	fields := []string{"code=" + string(CodeOf(err))}
+	details := DetailsOf(err)
+	keys := make([]string, 0, len(details))
+	for k := range details {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fields = append(fields, fmt.Sprintf("%s=%v", k, details[k]))
+	}
+	fields = append(fields, fmt.Sprintf("err=%q", err.Error()))
+	return strings.Join(fields, " ")
+}
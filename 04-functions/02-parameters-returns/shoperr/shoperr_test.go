@@ -0,0 +1,170 @@
+package shoperr
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+var errCardDeclined = errors.New("card network: insufficient funds")
+
+func declined() error {
+	return E(ErrPaymentDeclined, "payment.process", "$4.50", "order_id", "ORD-002", "amount", 4.5).Because(errCardDeclined)
+}
+
+func TestErrorMessage(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"kind only", E(ErrNotFound, "", ""), "not found"},
+		{"op and message", E(ErrNotFound, "customer.find", "customer C9"), "customer.find: not found: customer C9"},
+		{"with cause", declined(), "payment.process: payment declined: $4.50: card network: insufficient funds"},
+		{"wrapped", Wrap("register.checkout", declined()), "register.checkout: payment.process: payment declined: $4.50: card network: insufficient funds"},
+		{"wrapped kind", Wrap("order.create", ErrOutOfStock), "order.create: out of stock"},
+		{"wrapped foreign", Wrap("report.daily", errors.New("disk full")), "report.daily: internal error: disk full"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapKeepsKindAndCause(t *testing.T) {
+	err := Wrap("register.checkout", Wrap("payment.process", declined()))
+
+	if !errors.Is(err, ErrPaymentDeclined) {
+		t.Error("errors.Is(err, ErrPaymentDeclined) = false")
+	}
+	if !errors.Is(err, errCardDeclined) {
+		t.Error("errors.Is(err, cause) = false")
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("errors.Is(err, ErrNotFound) = true")
+	}
+	var domain *Error
+	if !errors.As(err, &domain) || domain.Op != "register.checkout" {
+		t.Errorf("errors.As found %+v, want the outermost error", domain)
+	}
+	if got := CodeOf(err); got != "payment_declined" {
+		t.Errorf("CodeOf = %q, want payment_declined", got)
+	}
+}
+
+func TestWrapNil(t *testing.T) {
+	if err := Wrap("order.create", nil); err != nil {
+		t.Errorf("Wrap(nil) = %v, want nil", err)
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Code
+	}{
+		{nil, ""},
+		{ErrOutOfStock, "out_of_stock"},
+		{E(ErrInvalidTransition, "order.advance", "Ready → Paid"), "invalid_transition"},
+		{errors.New("disk full"), "internal"},
+	}
+	for _, tt := range tests {
+		if got := CodeOf(tt.err); got != tt.want {
+			t.Errorf("CodeOf(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestDetailsOfOutermostWins(t *testing.T) {
+	inner := E(ErrOutOfStock, "order.create", "Croissant", "item", "Croissant", "have", 1)
+	outer := E(ErrOutOfStock, "register.checkout", "", "item", "Muffin").Because(inner)
+
+	want := map[string]any{"item": "Muffin", "have": 1}
+	if got := DetailsOf(outer); !reflect.DeepEqual(got, want) {
+		t.Errorf("DetailsOf = %v, want %v", got, want)
+	}
+}
+
+func TestToAPI(t *testing.T) {
+	status, body := ToAPI(Wrap("register.checkout", declined()))
+	if status != http.StatusPaymentRequired {
+		t.Errorf("status = %d, want %d", status, http.StatusPaymentRequired)
+	}
+	want := APIError{
+		Code:    "payment_declined",
+		Message: "register.checkout: payment.process: payment declined: $4.50: card network: insufficient funds",
+		Details: map[string]any{"order_id": "ORD-002", "amount": 4.5},
+	}
+	if !reflect.DeepEqual(body, want) {
+		t.Errorf("body = %+v, want %+v", body, want)
+	}
+}
+
+func TestToAPIHidesInternalErrors(t *testing.T) {
+	for _, err := range []error{
+		errors.New("disk full"),
+		Wrap("report.daily", errors.New("disk full")),
+		E(ErrInternal, "report.daily", "db password rejected"),
+	} {
+		status, body := ToAPI(err)
+		want := APIError{Code: "internal", Message: "something went wrong"}
+		if status != http.StatusInternalServerError || !reflect.DeepEqual(body, want) {
+			t.Errorf("ToAPI(%v) = %d %+v, want 500 %+v", err, status, body, want)
+		}
+	}
+}
+
+func TestLogLine(t *testing.T) {
+	want := `code=payment_declined amount=4.5 order_id=ORD-002 err="payment.process: payment declined: $4.50: card network: insufficient funds"`
+	if got := LogLine(declined()); got != want {
+		t.Errorf("LogLine =\n  %s\nwant\n  %s", got, want)
+	}
+}
+
+// A cause of another kind doesn't stand in for this error's kind
+func TestErrorNamesItsOwnKind(t *testing.T) {
+	err := E(ErrPaymentDeclined, "checkout", "").Because(ErrNotFound)
+	if got, want := err.Error(), "checkout: payment declined: not found"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got := CodeOf(err); got != "payment_declined" {
+		t.Errorf("CodeOf = %q, want payment_declined", got)
+	}
+}
+
+func TestNilKind(t *testing.T) {
+	err := &Error{Op: "report.daily", Err: errors.New("disk full")}
+	if got, want := err.Error(), "report.daily: internal error: disk full"; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+	if got := err.Code(); got != "internal" {
+		t.Errorf("Code() = %q, want internal", got)
+	}
+	if got := CodeOf(Wrap("report.send", err)); got != "internal" {
+		t.Errorf("CodeOf(Wrap) = %q, want internal", got)
+	}
+}
+
+func TestERejectsBadArguments(t *testing.T) {
+	tests := []struct {
+		name string
+		call func()
+	}{
+		{"nil kind", func() { E(nil, "order.create", "") }},
+		{"odd details", func() { E(ErrOutOfStock, "order.create", "", "item", "Muffin", "have") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("E didn't panic")
+				}
+			}()
+			tt.call()
+		})
+	}
+}
@@ -3,7 +3,7 @@ package main
 import (
 	"fmt"
 	"strings"
-	"math"
+	"sort"
 )
 
 // === Common Utility Functions ===
@@ -188,6 +188,16 @@ func WithNotes(notes string) OrderOption {
 	}
 }
 
+// Schedule returns the orders in the order the baristas make them:
+// priority orders first, everything else in the order it arrived
+func Schedule(orders ...*Order) []*Order {
+	queue := append([]*Order(nil), orders...)
+	sort.SliceStable(queue, func(i, j int) bool {
+		return queue[i].Priority && !queue[j].Priority
+	})
+	return queue
+}
+
 func main() {
 	fmt.Println("=== Useful Variadic Function Patterns ===")
 	fmt.Println()
@@ -245,6 +255,19 @@ func main() {
 	fmt.Printf("  Items: %v\n", order.Items)
 	fmt.Printf("  Priority: %v\n", order.Priority)
 	fmt.Printf("  Notes: %s\n", order.Notes)
+	fmt.Println()
+	
+	// The scheduler reads the priority flag: ORD-789 arrived last but is
+	// made first
+	queue := Schedule(
+		NewOrder("ORD-787", WithItems("Latte")),
+		NewOrder("ORD-788", WithItems("Mocha", "Croissant")),
+		order,
+	)
+	fmt.Println("Make order:")
+	for i, o := range queue {
+		fmt.Printf("  %d. %s %v priority=%v\n", i+1, o.ID, o.Items, o.Priority)
+	}
 }
 
 // Variadic functions enable:
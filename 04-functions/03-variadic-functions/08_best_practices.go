@@ -51,6 +51,8 @@ func WithVerbose() Config {
 }
 
 // 3. For aggregation operations
+// (MergeErrors loses the original errors; see Append in
+// 02-parameters-returns/multierr, or errors.Join)
 func MergeErrors(errs ...error) error {
 	var nonNil []error
 	for _, err := range errs {
@@ -195,6 +197,8 @@ func Pipeline(input string, transforms ...Transform) string {
 }
 
 // 3. Event system
+// (11_event_bus.go grows this into a publish/subscribe bus with topics,
+// async delivery and retries)
 type EventHandler func(Event)
 
 type Event struct {
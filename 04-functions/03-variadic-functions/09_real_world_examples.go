@@ -97,6 +97,7 @@ type Notification struct {
 }
 
 // NotificationService handles multiple notification channels
+// (see 12_notification_service.go for templates, push, retries and fallbacks)
 type NotificationService struct {
 	handlers map[NotificationType]func(Notification) error
 }
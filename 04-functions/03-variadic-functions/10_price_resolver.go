@@ -0,0 +1,336 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// GoCoffee grew four different ways to price a drink:
+//   - getCoffeeBasePrice × getSizeMultiplier (function practice)
+//   - calculatePrice(coffeeType, size) with a switch (return values)
+//   - MenuItem.Prices map[string]float64 with absolute prices per size (menu display)
+//   - MenuItem.Options map[string]float64 for add-ons (real world examples)
+//
+// The PriceResolver below replaces all of them: one base item, a size that
+// is either an absolute price or a multiplier, and any number of modifiers
+// (passed variadically!) with optional per-size upcharges.
+
+// === Money ===
+
+// Cents keeps money as whole cents so totals never drift (see 06_money_handling.go)
+type Cents int64
+
+func (c Cents) String() string {
+	sign := ""
+	if c < 0 {
+		sign = "-"
+		c = -c
+	}
+	return fmt.Sprintf("%s$%d.%02d", sign, c/100, c%100)
+}
+
+// === Items and Sizes ===
+
+// SizePrice is either an absolute price or a multiplier of the base price
+type SizePrice struct {
+	Absolute   Cents
+	Multiplier float64
+}
+
+type PricedItem struct {
+	Name      string
+	Category  string
+	BasePrice Cents
+	Sizes     map[string]SizePrice
+}
+
+type ItemOption func(*PricedItem)
+
+// NewPricedItem builds an item with any number of sizing options
+func NewPricedItem(name, category string, basePrice Cents, options ...ItemOption) PricedItem {
+	item := PricedItem{
+		Name:      name,
+		Category:  category,
+		BasePrice: basePrice,
+		Sizes:     make(map[string]SizePrice),
+	}
+
+	for _, opt := range options {
+		opt(&item)
+	}
+
+	return item
+}
+
+// WithSizePrice sets an absolute price for one size (like MenuItem.Prices)
+func WithSizePrice(size string, price Cents) ItemOption {
+	return func(item *PricedItem) {
+		item.Sizes[size] = SizePrice{Absolute: price}
+	}
+}
+
+// WithSizeMultiplier scales the base price for one size (like getSizeMultiplier)
+func WithSizeMultiplier(size string, multiplier float64) ItemOption {
+	return func(item *PricedItem) {
+		item.Sizes[size] = SizePrice{Multiplier: multiplier}
+	}
+}
+
+// sizePrice resolves the price of an item in the requested size
+func (item PricedItem) sizePrice(size string) (Cents, error) {
+	if len(item.Sizes) == 0 {
+		return item.BasePrice, nil // Single-size items like pastries
+	}
+
+	sp, exists := item.Sizes[size]
+	if !exists {
+		return 0, fmt.Errorf("%s is not available in size %q (sizes: %s)",
+			item.Name, size, strings.Join(item.sizeNames(), ", "))
+	}
+
+	if sp.Multiplier > 0 {
+		return Cents(math.Round(float64(item.BasePrice) * sp.Multiplier)), nil
+	}
+	return sp.Absolute, nil
+}
+
+func (item PricedItem) sizeNames() []string {
+	names := make([]string, 0, len(item.Sizes))
+	for name := range item.Sizes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// === Modifiers ===
+
+// Modifier is an add-on such as a milk type, extra shot or syrup.
+// Modifiers in the same Group are mutually exclusive (one milk per drink).
+type Modifier struct {
+	Name          string
+	Group         string
+	Upcharge      Cents
+	SizeUpcharges map[string]Cents
+}
+
+type ModifierOption func(*Modifier)
+
+func NewModifier(name, group string, upcharge Cents, options ...ModifierOption) Modifier {
+	mod := Modifier{
+		Name:          name,
+		Group:         group,
+		Upcharge:      upcharge,
+		SizeUpcharges: make(map[string]Cents),
+	}
+
+	for _, opt := range options {
+		opt(&mod)
+	}
+
+	return mod
+}
+
+// WithSizeUpcharge overrides the upcharge for one size (more oat milk in a Large)
+func WithSizeUpcharge(size string, upcharge Cents) ModifierOption {
+	return func(mod *Modifier) {
+		mod.SizeUpcharges[size] = upcharge
+	}
+}
+
+func (mod Modifier) upchargeFor(size string) Cents {
+	if upcharge, exists := mod.SizeUpcharges[size]; exists {
+		return upcharge
+	}
+	return mod.Upcharge
+}
+
+// === Price Resolver ===
+
+type PriceLine struct {
+	Label  string
+	Amount Cents
+}
+
+// PriceBreakdown is the itemized result of resolving one drink
+type PriceBreakdown struct {
+	Item  string
+	Size  string
+	Lines []PriceLine
+	Total Cents
+}
+
+type PriceResolver struct {
+	items     map[string]PricedItem
+	modifiers map[string]Modifier
+}
+
+func NewPriceResolver() *PriceResolver {
+	return &PriceResolver{
+		items:     make(map[string]PricedItem),
+		modifiers: make(map[string]Modifier),
+	}
+}
+
+// AddItems registers menu items - variadic so a whole menu fits in one call
+func (r *PriceResolver) AddItems(items ...PricedItem) {
+	for _, item := range items {
+		r.items[strings.ToLower(item.Name)] = item
+	}
+}
+
+func (r *PriceResolver) AddModifiers(mods ...Modifier) {
+	for _, mod := range mods {
+		r.modifiers[strings.ToLower(mod.Name)] = mod
+	}
+}
+
+// Resolve prices an item in a size with any number of modifiers
+func (r *PriceResolver) Resolve(itemName, size string, modifiers ...string) (PriceBreakdown, error) {
+	item, exists := r.items[strings.ToLower(itemName)]
+	if !exists {
+		return PriceBreakdown{}, fmt.Errorf("unknown item: %s", itemName)
+	}
+
+	price, err := item.sizePrice(size)
+	if err != nil {
+		return PriceBreakdown{}, err
+	}
+
+	label := item.Name
+	if len(item.Sizes) > 0 {
+		label = size + " " + item.Name
+	}
+
+	breakdown := PriceBreakdown{
+		Item:  item.Name,
+		Size:  size,
+		Lines: []PriceLine{{Label: label, Amount: price}},
+		Total: price,
+	}
+
+	usedGroups := make(map[string]string)
+	for _, name := range modifiers {
+		mod, exists := r.modifiers[strings.ToLower(name)]
+		if !exists {
+			return PriceBreakdown{}, fmt.Errorf("unknown modifier: %s", name)
+		}
+
+		if mod.Group != "" {
+			if previous, taken := usedGroups[mod.Group]; taken {
+				return PriceBreakdown{}, fmt.Errorf("cannot combine %s with %s (both %s)",
+					mod.Name, previous, mod.Group)
+			}
+			usedGroups[mod.Group] = mod.Name
+		}
+
+		upcharge := mod.upchargeFor(size)
+		breakdown.Lines = append(breakdown.Lines, PriceLine{Label: "+ " + mod.Name, Amount: upcharge})
+		breakdown.Total += upcharge
+	}
+
+	return breakdown, nil
+}
+
+func (b PriceBreakdown) Print() {
+	for _, line := range b.Lines {
+		fmt.Printf("  %-24s %8s\n", line.Label, line.Amount)
+	}
+	fmt.Printf("  %s\n", strings.Repeat("-", 33))
+	fmt.Printf("  %-24s %8s\n", "Total", b.Total)
+}
+
+// newGoCoffeeResolver wires up the menu that used to live in four places
+func newGoCoffeeResolver() *PriceResolver {
+	resolver := NewPriceResolver()
+
+	resolver.AddItems(
+		// Multiplier pricing, like getCoffeeBasePrice × getSizeMultiplier
+		NewPricedItem("Latte", "Coffee", 400,
+			WithSizeMultiplier("Small", 0.8),
+			WithSizeMultiplier("Medium", 1.0),
+			WithSizeMultiplier("Large", 1.3),
+		),
+		NewPricedItem("Cappuccino", "Coffee", 350,
+			WithSizeMultiplier("Small", 0.8),
+			WithSizeMultiplier("Medium", 1.0),
+			WithSizeMultiplier("Large", 1.3),
+		),
+		// Absolute pricing, like MenuItem.Prices
+		NewPricedItem("Vanilla Latte", "Hot Drinks", 475,
+			WithSizePrice("Small", 400),
+			WithSizePrice("Medium", 475),
+			WithSizePrice("Large", 550),
+		),
+		NewPricedItem("Espresso", "Coffee", 250,
+			WithSizePrice("Solo", 250),
+			WithSizePrice("Doppio", 350),
+		),
+		// Single-size items
+		NewPricedItem("Croissant", "Pastry", 325),
+	)
+
+	resolver.AddModifiers(
+		NewModifier("Oat Milk", "milk", 75, WithSizeUpcharge("Large", 90)),
+		NewModifier("Soy Milk", "milk", 50, WithSizeUpcharge("Large", 65)),
+		NewModifier("Almond Milk", "milk", 60),
+		NewModifier("Extra Shot", "", 75),
+		NewModifier("Vanilla Syrup", "", 50, WithSizeUpcharge("Small", 40)),
+		NewModifier("Caramel Syrup", "", 50, WithSizeUpcharge("Small", 40)),
+	)
+
+	return resolver
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Price Resolver ===")
+	fmt.Println()
+
+	resolver := newGoCoffeeResolver()
+
+	orders := []struct {
+		item      string
+		size      string
+		modifiers []string
+	}{
+		{"Latte", "Large", []string{"Oat Milk", "Extra Shot"}},
+		{"Latte", "Small", []string{"Vanilla Syrup"}},
+		{"Vanilla Latte", "Medium", nil},
+		{"Espresso", "Doppio", []string{"Extra Shot"}},
+		{"Croissant", "", nil},
+	}
+
+	for _, order := range orders {
+		// Spread a slice into the variadic parameter with ...
+		breakdown, err := resolver.Resolve(order.item, order.size, order.modifiers...)
+		if err != nil {
+			fmt.Printf("❌ %v\n\n", err)
+			continue
+		}
+		fmt.Printf("🧾 %s\n", breakdown.Item)
+		breakdown.Print()
+		fmt.Println()
+	}
+
+	// The resolver catches mistakes the old pricing functions silently ignored
+	fmt.Println("Validation:")
+	invalid := []func() (PriceBreakdown, error){
+		func() (PriceBreakdown, error) { return resolver.Resolve("Latte", "Venti") },
+		func() (PriceBreakdown, error) { return resolver.Resolve("Latte", "Medium", "Oat Milk", "Soy Milk") },
+		func() (PriceBreakdown, error) { return resolver.Resolve("Latte", "Medium", "Whipped Cream") },
+		func() (PriceBreakdown, error) { return resolver.Resolve("Frappuccino", "Large") },
+	}
+	for _, attempt := range invalid {
+		if _, err := attempt(); err != nil {
+			fmt.Printf("  ❌ %v\n", err)
+		}
+	}
+}
+
+// Why one resolver?
+// 1. A Large Latte costs the same on the menu board, the receipt and the report
+// 2. Sizes can mix absolute prices and multipliers per item
+// 3. Modifiers carry per-size upcharges instead of a flat $0.50
+// 4. The breakdown explains every cent to the customer
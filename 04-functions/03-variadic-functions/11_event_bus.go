@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"variadic-functions/eventbus"
+)
+
+// TriggerEvent in 08_best_practices.go calls each handler in turn: if one
+// panics the rest never run, an error has nowhere to go, and the caller
+// has to know every handler. NotificationService in 09_real_world_examples.go
+// is never told when an order changes at all.
+//
+// The Bus in ./eventbus decouples them. The order code publishes what
+// happened; notifications, inventory and analytics subscribe to the topics
+// they care about. Subscriptions take variadic options, the same pattern as
+// WithPriority in 07_useful_functions.go:
+//
+//	bus.Subscribe("analytics", handler, eventbus.Topics(TopicOrderPaid), eventbus.Async())
+//
+// Each subscriber is isolated: its errors and panics are retried, then
+// recorded, and never stop the other subscribers from getting the event.
+
+// === Events ===
+
+const (
+	TopicOrderCreated eventbus.Topic = "order.created"
+	TopicOrderPaid    eventbus.Topic = "order.paid"
+	TopicOrderReady   eventbus.Topic = "order.ready"
+	TopicStockLow     eventbus.Topic = "stock.low"
+)
+
+type OrderItem struct {
+	Name     string
+	Quantity int
+}
+
+type OrderCreated struct {
+	OrderID  string
+	Customer string
+	Items    []OrderItem
+}
+
+type OrderPaid struct {
+	OrderID string
+	Amount  float64
+	Method  string
+}
+
+type OrderReady struct {
+	OrderID  string
+	Customer string
+}
+
+type StockLow struct {
+	Item string
+	Left int
+}
+
+func (OrderCreated) Topic() eventbus.Topic { return TopicOrderCreated }
+func (OrderPaid) Topic() eventbus.Topic    { return TopicOrderPaid }
+func (OrderReady) Topic() eventbus.Topic   { return TopicOrderReady }
+func (StockLow) Topic() eventbus.Topic     { return TopicStockLow }
+
+// === Subscribers ===
+
+// Each of these knows about events, not about the order code or each other
+
+// Inventory deducts stock when an order is created, and publishes
+// StockLow when an item drops under its threshold
+type Inventory struct {
+	bus       *eventbus.Bus
+	mu        sync.Mutex
+	stock     map[string]int
+	threshold int
+}
+
+func (inv *Inventory) Handle(e eventbus.Event) error {
+	created, ok := e.(OrderCreated)
+	if !ok {
+		return nil
+	}
+	var low []eventbus.Event
+	inv.mu.Lock()
+	for _, item := range created.Items {
+		before := inv.stock[item.Name]
+		inv.stock[item.Name] -= item.Quantity
+		if before >= inv.threshold && inv.stock[item.Name] < inv.threshold {
+			low = append(low, StockLow{Item: item.Name, Left: inv.stock[item.Name]})
+		}
+	}
+	inv.mu.Unlock()
+	return inv.bus.Publish(low...)
+}
+
+// Analytics counts what happens; it's async so reporting never slows an order
+type Analytics struct {
+	mu      sync.Mutex
+	counts  map[eventbus.Topic]int
+	revenue float64
+	methods map[string]int
+}
+
+func (a *Analytics) Handle(e eventbus.Event) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.counts[e.Topic()]++
+	if paid, ok := e.(OrderPaid); ok {
+		a.revenue += paid.Amount
+		a.methods[paid.Method]++
+	}
+	return nil
+}
+
+func notify(e eventbus.Event) error {
+	switch e := e.(type) {
+	case OrderCreated:
+		fmt.Printf("    📧 %s: we got your order %s\n", e.Customer, e.OrderID)
+	case OrderReady:
+		fmt.Printf("    📱 %s: order %s is ready at the counter!\n", e.Customer, e.OrderID)
+	}
+	return nil
+}
+
+func restock(e eventbus.Event) error {
+	low := e.(StockLow)
+	fmt.Printf("    📦 Reorder %s (%d left)\n", low.Item, low.Left)
+	return nil
+}
+
+// flakyLoyalty is a points service that times out twice on every order
+func flakyLoyalty() eventbus.Handler {
+	calls := map[string]int{}
+	return func(e eventbus.Event) error {
+		paid := e.(OrderPaid)
+		calls[paid.OrderID]++
+		if calls[paid.OrderID] <= 2 {
+			return fmt.Errorf("loyalty service timeout")
+		}
+		fmt.Printf("    ⭐ %s: %.0f points, on try %d\n", paid.OrderID, paid.Amount*10, calls[paid.OrderID])
+		return nil
+	}
+}
+
+// === Orders ===
+
+// The order code only publishes; it has no idea who's listening
+type OrderService struct {
+	bus  *eventbus.Bus
+	next int
+}
+
+func (o *OrderService) Create(customer string, items ...OrderItem) string {
+	o.next++
+	id := fmt.Sprintf("ORD-%03d", o.next)
+	if err := o.bus.Publish(OrderCreated{OrderID: id, Customer: customer, Items: items}); err != nil {
+		fmt.Printf("    ⚠️  %v\n", err)
+	}
+	return id
+}
+
+func main() {
+	fmt.Println("=== GoCoffee Order Event Bus ===")
+	fmt.Println()
+
+	bus := eventbus.NewBus()
+	inventory := &Inventory{bus: bus, stock: map[string]int{"Latte": 6, "Croissant": 3, "Muffin": 10}, threshold: 3}
+	analytics := &Analytics{counts: map[eventbus.Topic]int{}, methods: map[string]int{}}
+
+	bus.Subscribe("notifications", notify, eventbus.Topics(TopicOrderCreated, TopicOrderReady))
+	bus.Subscribe("inventory", inventory.Handle, eventbus.Topics(TopicOrderCreated))
+	bus.Subscribe("purchasing", restock, eventbus.Topics(TopicStockLow))
+	bus.Subscribe("analytics", analytics.Handle, eventbus.Async())
+	bus.Subscribe("loyalty", flakyLoyalty(), eventbus.Topics(TopicOrderPaid), eventbus.Retry(3, time.Millisecond))
+	bus.Subscribe("receipt-printer", func(e eventbus.Event) error {
+		panic("printer out of paper")
+	}, eventbus.Topics(TopicOrderPaid))
+
+	err := bus.Subscribe("analytics", analytics.Handle)
+	fmt.Printf("Subscribing twice: %v (ErrDuplicate: %t)\n", err, errors.Is(err, eventbus.ErrDuplicate))
+
+	orders := &OrderService{bus: bus}
+
+	// 1. Orders come in; inventory reacts, and its StockLow reaches purchasing
+	fmt.Println("\n1. Orders")
+	fmt.Println("  Alice orders 2 lattes and a croissant")
+	alice := orders.Create("Alice", OrderItem{"Latte", 2}, OrderItem{"Croissant", 1})
+	fmt.Println("  Bob orders 2 lattes and a croissant")
+	bob := orders.Create("Bob", OrderItem{"Latte", 2}, OrderItem{"Croissant", 1})
+
+	// 2. Payments: loyalty fails twice and is retried; the printer panics,
+	// and neither stops the other subscribers
+	fmt.Println("\n2. Payments")
+	err = bus.Publish(
+		OrderPaid{OrderID: alice, Amount: 12.25, Method: "card"},
+		OrderPaid{OrderID: bob, Amount: 12.25, Method: "cash"},
+	)
+	var failed *eventbus.DeliveryError
+	if errors.As(err, &failed) {
+		fmt.Printf("  Publish reported: %v\n", failed)
+	}
+
+	// 3. Ready
+	fmt.Println("\n3. Drinks ready")
+	bus.Publish(OrderReady{OrderID: alice, Customer: "Alice"}, OrderReady{OrderID: bob, Customer: "Bob"})
+
+	// 4. Analytics caught up in the background
+	bus.Close()
+	fmt.Println("\n4. Analytics (async, read after Close)")
+	var topics []string
+	for t, n := range analytics.counts {
+		topics = append(topics, fmt.Sprintf("%s=%d", t, n))
+	}
+	sort.Strings(topics)
+	fmt.Printf("  Events: %s\n", strings.Join(topics, ", "))
+	fmt.Printf("  Revenue: $%.2f (card %d, cash %d)\n", analytics.revenue, analytics.methods["card"], analytics.methods["cash"])
+	fmt.Printf("  Stock left: %v\n", inventory.stock)
+
+	// 5. Failed deliveries are kept for someone to look at
+	fmt.Println("\n5. Failed deliveries")
+	for _, f := range bus.Failures() {
+		fmt.Printf("  ❌ %v\n", f)
+	}
+	fmt.Printf("\nPublishing after Close: %v\n", bus.Publish(OrderReady{OrderID: "ORD-999"}))
+}
@@ -0,0 +1,449 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// NotificationService in 09_real_world_examples.go registers email and SMS
+// handlers that print; NotifyPush has no handler, so push targets are
+// silently skipped, and SendNotifications overwrites every target's
+// Message with the same string.
+//
+// This service fixes all three:
+//   - Each event has a template per channel, with {customer}, {order},
+//     {items} and {ready} placeholders.
+//   - Push has a handler, and the channels are tried in order
+//     (push → SMS → email, or the customer's own order), each with retries
+//     and backoff before falling back to the next.
+//   - Quiet hours hold back anything that buzzes a phone; held
+//     notifications wait in a queue until Flush sends them.
+//   - Every attempt, skip and failure goes in a delivery log.
+//
+// Like NewOrder in 07_useful_functions.go, it's configured with variadic
+// options.
+
+// === Channels and customers ===
+
+type NotificationType string
+
+const (
+	NotifyPush  NotificationType = "push"
+	NotifySMS   NotificationType = "sms"
+	NotifyEmail NotificationType = "email"
+)
+
+// DefaultFallback is the order channels are tried in when a customer
+// hasn't chosen one
+var DefaultFallback = []NotificationType{NotifyPush, NotifySMS, NotifyEmail}
+
+// intrusive channels buzz a phone and wait for the end of quiet hours
+var intrusive = map[NotificationType]bool{NotifyPush: true, NotifySMS: true}
+
+type Customer struct {
+	ID          string
+	Name        string
+	Email       string
+	Phone       string
+	DeviceToken string
+
+	// Prefers is the channels to try, in order; empty means DefaultFallback
+	Prefers []NotificationType
+
+	// QuietFrom and QuietTo are hours of the day (0-23); QuietFrom ==
+	// QuietTo means no quiet hours. They may wrap midnight.
+	QuietFrom, QuietTo int
+}
+
+// address is where a channel reaches the customer, if it can
+func (c Customer) address(channel NotificationType) string {
+	switch channel {
+	case NotifyPush:
+		return c.DeviceToken
+	case NotifySMS:
+		return c.Phone
+	case NotifyEmail:
+		return c.Email
+	}
+	return ""
+}
+
+func (c Customer) quiet(t time.Time) bool {
+	h := t.Hour()
+	if c.QuietFrom == c.QuietTo {
+		return false
+	}
+	if c.QuietFrom < c.QuietTo {
+		return h >= c.QuietFrom && h < c.QuietTo
+	}
+	return h >= c.QuietFrom || h < c.QuietTo
+}
+
+// === Events and templates ===
+
+type EventType string
+
+const (
+	OrderConfirmed EventType = "order_confirmed"
+	OrderReady     EventType = "order_ready"
+	OrderDelayed   EventType = "order_delayed"
+)
+
+// OrderInfo is what a template can mention
+type OrderInfo struct {
+	ID    string
+	Items []string
+	Ready time.Time
+}
+
+var placeholder = regexp.MustCompile(`\{[a-z]+\}`)
+
+var placeholders = map[string]bool{"{customer}": true, "{order}": true, "{items}": true, "{ready}": true}
+
+// ErrTemplate is a template using a placeholder that doesn't exist
+var ErrTemplate = errors.New("unknown placeholder")
+
+func render(template string, c Customer, o OrderInfo) string {
+	return strings.NewReplacer(
+		"{customer}", c.Name,
+		"{order}", o.ID,
+		"{items}", strings.Join(o.Items, ", "),
+		"{ready}", o.Ready.Format("3:04 PM"),
+	).Replace(template)
+}
+
+// === Delivery log ===
+
+type Status string
+
+const (
+	Sent     Status = "sent"
+	Failed   Status = "failed"
+	Skipped  Status = "skipped"
+	Deferred Status = "deferred"
+)
+
+// Delivery is one line of the log
+type Delivery struct {
+	At       time.Time
+	Customer string
+	Event    EventType
+	Channel  NotificationType
+	Attempt  int
+	Status   Status
+	Detail   string // The message when sent, otherwise why not
+}
+
+func (d Delivery) String() string {
+	attempt := ""
+	if d.Attempt > 0 {
+		attempt = fmt.Sprintf(" #%d", d.Attempt)
+	}
+	return fmt.Sprintf("%s %-6s %-15s %-5s%-3s %-8s %s",
+		d.At.Format("15:04:05"), d.Customer, d.Event, d.Channel, attempt, d.Status, d.Detail)
+}
+
+// === Service ===
+
+// Sender delivers one message to one address
+type Sender func(to, message string) error
+
+var (
+	ErrNoChannel = errors.New("no channel could reach the customer")
+	ErrQuiet     = errors.New("held for quiet hours")
+	ErrNoEvent   = errors.New("no template for event")
+)
+
+type NotificationService struct {
+	senders   map[NotificationType]Sender
+	templates map[EventType]map[NotificationType]string
+	attempts  int
+	backoff   time.Duration
+	now       func() time.Time
+	sleep     func(time.Duration)
+	log       []Delivery
+	held      []heldNotification
+}
+
+// heldNotification is a Notify call that quiet hours put off
+type heldNotification struct {
+	event EventType
+	order OrderInfo
+	c     Customer
+}
+
+type Option func(*NotificationService)
+
+// WithSender sets the sender for a channel
+func WithSender(channel NotificationType, send Sender) Option {
+	return func(ns *NotificationService) {
+		ns.senders[channel] = send
+	}
+}
+
+// WithRetry tries each channel up to attempts times, waiting backoff after
+// the first failure and doubling it each time. Every channel gets at least
+// one attempt.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(ns *NotificationService) {
+		ns.attempts = max(attempts, 1)
+		ns.backoff = backoff
+	}
+}
+
+// WithClock replaces time.Now and time.Sleep, so quiet hours and backoff
+// can be simulated
+func WithClock(now func() time.Time, sleep func(time.Duration)) Option {
+	return func(ns *NotificationService) {
+		ns.now = now
+		ns.sleep = sleep
+	}
+}
+
+func NewNotificationService(opts ...Option) *NotificationService {
+	ns := &NotificationService{
+		senders:   map[NotificationType]Sender{},
+		templates: map[EventType]map[NotificationType]string{},
+		attempts:  3,
+		backoff:   time.Second,
+		now:       time.Now,
+		sleep:     time.Sleep,
+	}
+	for _, opt := range opts {
+		opt(ns)
+	}
+	return ns
+}
+
+// SetTemplate sets the message for an event on a channel. A template with
+// an unknown placeholder is rejected now rather than sent half-filled.
+func (ns *NotificationService) SetTemplate(event EventType, channel NotificationType, template string) error {
+	for _, p := range placeholder.FindAllString(template, -1) {
+		if !placeholders[p] {
+			return fmt.Errorf("%s/%s template: %w %s", event, channel, ErrTemplate, p)
+		}
+	}
+	if ns.templates[event] == nil {
+		ns.templates[event] = map[NotificationType]string{}
+	}
+	ns.templates[event][channel] = template
+	return nil
+}
+
+func (ns *NotificationService) record(c Customer, event EventType, channel NotificationType, attempt int, status Status, detail string) {
+	ns.log = append(ns.log, Delivery{
+		At: ns.now(), Customer: c.Name, Event: event, Channel: channel,
+		Attempt: attempt, Status: status, Detail: detail,
+	})
+}
+
+// Notify tells one customer about an event on the first channel that
+// works. It returns the channel used. If quiet hours were all that stopped
+// it, the notification is held for Flush and the error wraps ErrQuiet.
+func (ns *NotificationService) Notify(event EventType, order OrderInfo, c Customer) (NotificationType, error) {
+	channel, err := ns.notify(event, order, c, ns.now())
+	if errors.Is(err, ErrQuiet) {
+		ns.held = append(ns.held, heldNotification{event: event, order: order, c: c})
+	}
+	return channel, err
+}
+
+// notify tries each channel, treating quiet hours as they are at the
+// given time
+func (ns *NotificationService) notify(event EventType, order OrderInfo, c Customer, at time.Time) (NotificationType, error) {
+	templates, ok := ns.templates[event]
+	if !ok {
+		return "", fmt.Errorf("%s: %w", event, ErrNoEvent)
+	}
+
+	channels := c.Prefers
+	if len(channels) == 0 {
+		channels = DefaultFallback
+	}
+
+	held := false
+	for _, channel := range channels {
+		to := c.address(channel)
+		send, hasSender := ns.senders[channel]
+		template, hasTemplate := templates[channel]
+		switch {
+		case to == "":
+			ns.record(c, event, channel, 0, Skipped, "no address")
+			continue
+		case !hasSender:
+			ns.record(c, event, channel, 0, Skipped, "no sender")
+			continue
+		case !hasTemplate:
+			ns.record(c, event, channel, 0, Skipped, "no template")
+			continue
+		case intrusive[channel] && c.quiet(at):
+			ns.record(c, event, channel, 0, Deferred, "quiet hours")
+			held = true
+			continue
+		}
+
+		message := render(template, c, order)
+		wait := ns.backoff
+		for attempt := 1; attempt <= ns.attempts; attempt++ {
+			err := send(to, message)
+			if err == nil {
+				ns.record(c, event, channel, attempt, Sent, message)
+				return channel, nil
+			}
+			ns.record(c, event, channel, attempt, Failed, err.Error())
+			if attempt < ns.attempts {
+				ns.sleep(wait)
+				wait *= 2
+			}
+		}
+	}
+
+	if held {
+		return "", fmt.Errorf("%s for %s: %w", event, c.Name, ErrQuiet)
+	}
+	return "", fmt.Errorf("%s for %s: %w", event, c.Name, ErrNoChannel)
+}
+
+// NotifyAll tells every customer, carrying on past failures
+func (ns *NotificationService) NotifyAll(event EventType, order OrderInfo, customers ...Customer) []error {
+	var errs []error
+	for _, c := range customers {
+		if _, err := ns.Notify(event, order, c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// Held is how many notifications are waiting for quiet hours to end
+func (ns *NotificationService) Held() int {
+	return len(ns.held)
+}
+
+// Flush retries every held notification whose customer is out of quiet
+// hours at now. The rest stay held. It returns the failures, which are
+// not held again.
+func (ns *NotificationService) Flush(now time.Time) []error {
+	var errs []error
+	var still []heldNotification
+	for _, h := range ns.held {
+		if h.c.quiet(now) {
+			still = append(still, h)
+			continue
+		}
+		if _, err := ns.notify(h.event, h.order, h.c, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	ns.held = still
+	return errs
+}
+
+// Log is every delivery attempt so far
+func (ns *NotificationService) Log() []Delivery {
+	return append([]Delivery(nil), ns.log...)
+}
+
+// === Demo ===
+
+// flaky fails the first n sends, like a gateway having a bad minute
+func flaky(name string, n int) Sender {
+	calls := 0
+	return func(to, message string) error {
+		calls++
+		if calls <= n {
+			return fmt.Errorf("%s gateway timeout", name)
+		}
+		return nil
+	}
+}
+
+func down(name string) Sender {
+	return func(to, message string) error {
+		return fmt.Errorf("%s gateway unavailable", name)
+	}
+}
+
+func ok(to, message string) error { return nil }
+
+func main() {
+	fmt.Println("=== GoCoffee Notifications ===")
+	fmt.Println()
+
+	// A simulated clock: backoff moves it forward instead of sleeping
+	now := time.Date(2024, time.March, 11, 8, 15, 0, 0, time.Local)
+	clock := WithClock(func() time.Time { return now }, func(d time.Duration) { now = now.Add(d) })
+
+	ns := NewNotificationService(
+		WithSender(NotifyPush, flaky("push", 2)),
+		WithSender(NotifySMS, down("sms")),
+		WithSender(NotifyEmail, ok),
+		WithRetry(3, 2*time.Second),
+		clock,
+	)
+
+	templates := []struct {
+		event    EventType
+		channel  NotificationType
+		template string
+	}{
+		{OrderConfirmed, NotifyPush, "Thanks {customer}! {items} coming up, ready by {ready}"},
+		{OrderConfirmed, NotifySMS, "GoCoffee: order {order} confirmed, ready by {ready}"},
+		{OrderConfirmed, NotifyEmail, "Hi {customer}, we've got order {order}: {items}. It'll be ready by {ready}."},
+		{OrderReady, NotifyPush, "☕ {customer}, {order} is ready at the counter!"},
+		{OrderReady, NotifySMS, "GoCoffee: {order} is ready for pickup"},
+		{OrderReady, NotifyEmail, "Hi {customer}, your order {order} ({items}) is ready."},
+		{OrderDelayed, NotifyPush, "Sorry {customer}, {order} is running late - new time {ready}"},
+		{OrderDelayed, NotifyEmail, "Hi {customer}, {order} is delayed until {ready}. Your next drink is on us, {coupon}."},
+	}
+	fmt.Println("1. Templates")
+	for _, t := range templates {
+		if err := ns.SetTemplate(t.event, t.channel, t.template); err != nil {
+			fmt.Printf("  ❌ %v (ErrTemplate: %t)\n", err, errors.Is(err, ErrTemplate))
+		}
+	}
+
+	alice := Customer{ID: "C1", Name: "Alice", Email: "alice@example.com", Phone: "+15550101", DeviceToken: "tok-alice"}
+	bob := Customer{ID: "C2", Name: "Bob", Email: "bob@example.com", Phone: "+15550102", Prefers: []NotificationType{NotifySMS, NotifyEmail}}
+	carol := Customer{ID: "C3", Name: "Carol", Phone: "+15550103", DeviceToken: "tok-carol", QuietFrom: 21, QuietTo: 9}
+	dave := Customer{ID: "C4", Name: "Dave"}
+
+	order := OrderInfo{ID: "ORD-042", Items: []string{"Oat Latte", "Croissant"}, Ready: now.Add(8 * time.Minute)}
+
+	// 2. Push fails twice and goes through on the third try
+	fmt.Println("\n2. Alice: push, with retries")
+	channel, err := ns.Notify(OrderConfirmed, order, alice)
+	fmt.Printf("  Delivered by %s (err: %v)\n", channel, err)
+
+	// 3. Bob prefers SMS, which is down, so he falls back to email
+	fmt.Println("\n3. Bob: SMS first, then email")
+	channel, err = ns.Notify(OrderReady, order, bob)
+	fmt.Printf("  Delivered by %s (err: %v)\n", channel, err)
+
+	// 4. Carol's quiet hours run until 9; Dave can't be reached at all
+	fmt.Println("\n4. Everyone, variadically")
+	for _, err := range ns.NotifyAll(OrderReady, order, alice, carol, dave) {
+		fmt.Printf("  ❌ %v (quiet: %t, unreachable: %t)\n", err, errors.Is(err, ErrQuiet), errors.Is(err, ErrNoChannel))
+	}
+
+	// 5. Carol's notification is held; Flush sends it once she's out of
+	// quiet hours
+	fmt.Printf("\n5. Held for quiet hours: %d\n", ns.Held())
+	now = time.Date(2024, time.March, 11, 8, 45, 0, 0, time.Local)
+	errs := ns.Flush(now)
+	fmt.Printf("  Flush at 8:45: %d errors, %d still held\n", len(errs), ns.Held())
+	now = time.Date(2024, time.March, 11, 9, 0, 0, 0, time.Local)
+	errs = ns.Flush(now)
+	fmt.Printf("  Flush at 9:00: %d errors, %d still held\n", len(errs), ns.Held())
+
+	_, err = ns.Notify("order_refunded", order, alice)
+	fmt.Printf("\n6. An event with no templates: %v\n", err)
+
+	fmt.Println("\n7. Delivery log")
+	for _, d := range ns.Log() {
+		fmt.Printf("  %v\n", d)
+	}
+}
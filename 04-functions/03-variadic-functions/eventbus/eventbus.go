@@ -0,0 +1,306 @@
+// Package eventbus is an in-process publish/subscribe bus for GoCoffee's
+// order events. Subscriptions take variadic options:
+//
+//	bus.Subscribe("analytics", handler, eventbus.Topics(TopicOrderPaid), eventbus.Async())
+//
+// Each subscriber is isolated: its errors and panics are retried, then
+// recorded, and never stop the other subscribers from getting the event.
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Topic names a kind of event
+type Topic string
+
+// Event is anything that can be published
+type Event interface {
+	Topic() Topic
+}
+
+// Handler reacts to an event. Returning an error asks for a retry.
+type Handler func(Event) error
+
+// === Subscriptions ===
+
+type subscription struct {
+	name    string
+	handler Handler
+	topics  map[Topic]bool // Empty means every topic
+	async   bool
+	retries int
+	backoff time.Duration
+
+	queue *queue // Async only
+}
+
+func (s *subscription) wants(t Topic) bool {
+	return len(s.topics) == 0 || s.topics[t]
+}
+
+// SubscribeOption configures a subscription
+type SubscribeOption func(*subscription)
+
+// Topics limits a subscriber to some topics; without it, it gets everything
+func Topics(topics ...Topic) SubscribeOption {
+	return func(s *subscription) {
+		for _, t := range topics {
+			s.topics[t] = true
+		}
+	}
+}
+
+// Async delivers events on the subscriber's own goroutine, so a slow
+// subscriber doesn't hold up Publish
+func Async() SubscribeOption {
+	return func(s *subscription) {
+		s.async = true
+	}
+}
+
+// Retry retries a failed delivery up to n more times, waiting backoff
+// before the first retry and twice as long before each one after
+func Retry(n int, backoff time.Duration) SubscribeOption {
+	return func(s *subscription) {
+		s.retries = n
+		s.backoff = backoff
+	}
+}
+
+// === Bus ===
+
+// DeliveryError is a delivery that failed even after its retries.
+// Attempts is 0 if the handler was never called.
+type DeliveryError struct {
+	Subscriber string
+	Event      Event
+	Attempts   int
+	Err        error
+}
+
+func (e *DeliveryError) Error() string {
+	switch e.Attempts {
+	case 0:
+		return fmt.Sprintf("%s: %s not delivered: %v", e.Subscriber, e.Event.Topic(), e.Err)
+	case 1:
+		return fmt.Sprintf("%s: %s failed after 1 attempt: %v", e.Subscriber, e.Event.Topic(), e.Err)
+	}
+	return fmt.Sprintf("%s: %s failed after %d attempts: %v", e.Subscriber, e.Event.Topic(), e.Attempts, e.Err)
+}
+
+func (e *DeliveryError) Unwrap() error { return e.Err }
+
+var (
+	ErrClosed    = errors.New("event bus is closed")
+	ErrDuplicate = errors.New("subscriber already registered")
+)
+
+// Bus is an in-process publish/subscribe bus. It is safe to use from
+// several goroutines, and handlers may publish events of their own.
+type Bus struct {
+	mu     sync.RWMutex
+	subs   []*subscription
+	closed bool
+	wg     sync.WaitGroup
+
+	failMu   sync.Mutex
+	failures []*DeliveryError
+}
+
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe registers a handler under a unique name
+func (b *Bus) Subscribe(name string, handler Handler, opts ...SubscribeOption) error {
+	s := &subscription{name: name, handler: handler, topics: map[Topic]bool{}}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return ErrClosed
+	}
+	for _, existing := range b.subs {
+		if existing.name == name {
+			return fmt.Errorf("%q: %w", name, ErrDuplicate)
+		}
+	}
+
+	if s.async {
+		s.queue = newQueue()
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			for {
+				events, ok := s.queue.take()
+				if !ok {
+					return
+				}
+				for _, e := range events {
+					b.deliver(s, e)
+				}
+			}
+		}()
+	}
+	b.subs = append(b.subs, s)
+	return nil
+}
+
+// Publish sends events to every subscriber of their topics. Synchronous
+// subscribers have run by the time it returns; their failures are
+// returned, joined. Asynchronous ones are only queued, and queuing never
+// blocks, so an async handler can publish as much as it likes.
+func (b *Bus) Publish(events ...Event) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+	// Copy, and let go of the lock before any delivery, so handlers can
+	// publish or subscribe without deadlocking
+	subs := append([]*subscription(nil), b.subs...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, e := range events {
+		for _, s := range subs {
+			if s.async && s.wants(e.Topic()) && !s.queue.push(e) {
+				// Close won the race; the event can't be delivered now
+				errs = append(errs, b.fail(s, e, 0, ErrClosed))
+			}
+		}
+	}
+
+	for _, e := range events {
+		for _, s := range subs {
+			if !s.async && s.wants(e.Topic()) {
+				if err := b.deliver(s, e); err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliver runs one handler with retries, turning panics into errors
+func (b *Bus) deliver(s *subscription, e Event) error {
+	wait := s.backoff
+	var err error
+	attempt := 1
+	for ; ; attempt++ {
+		if err = call(s.handler, e); err == nil {
+			return nil
+		}
+		if attempt > s.retries {
+			break
+		}
+		time.Sleep(wait)
+		wait *= 2
+	}
+
+	return b.fail(s, e, attempt, err)
+}
+
+// fail records a delivery that gave up
+func (b *Bus) fail(s *subscription, e Event, attempts int, err error) *DeliveryError {
+	failure := &DeliveryError{Subscriber: s.name, Event: e, Attempts: attempts, Err: err}
+	b.failMu.Lock()
+	b.failures = append(b.failures, failure)
+	b.failMu.Unlock()
+	return failure
+}
+
+func call(h Handler, e Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return h(e)
+}
+
+// Failures returns every delivery that gave up, oldest first
+func (b *Bus) Failures() []*DeliveryError {
+	b.failMu.Lock()
+	defer b.failMu.Unlock()
+	return append([]*DeliveryError(nil), b.failures...)
+}
+
+// Close stops accepting events and waits for async subscribers to finish
+// what they've been given
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, s := range b.subs {
+		if s.async {
+			s.queue.close()
+		}
+	}
+	b.mu.Unlock()
+	b.wg.Wait()
+}
+
+// === Queues ===
+
+// queue holds an async subscriber's undelivered events. It has no limit:
+// a handler that publishes to its own topic, or a burst of orders, must
+// never block Publish.
+type queue struct {
+	mu     sync.Mutex
+	ready  *sync.Cond
+	events []Event
+	closed bool
+}
+
+func newQueue() *queue {
+	q := &queue{}
+	q.ready = sync.NewCond(&q.mu)
+	return q
+}
+
+// push adds an event, or reports false if the queue is closed
+func (q *queue) push(e Event) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return false
+	}
+	q.events = append(q.events, e)
+	q.ready.Signal()
+	return true
+}
+
+// take waits for events and returns all of them. After close it returns
+// what's left, then false once the queue is empty.
+func (q *queue) take() ([]Event, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.events) == 0 && !q.closed {
+		q.ready.Wait()
+	}
+	if len(q.events) == 0 {
+		return nil, false
+	}
+	events := q.events
+	q.events = nil
+	return events, true
+}
+
+func (q *queue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+	q.ready.Broadcast()
+}
@@ -0,0 +1,170 @@
+package eventbus
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type created struct{ n int }
+type stockLow struct{ n int }
+
+func (created) Topic() Topic  { return "order.created" }
+func (stockLow) Topic() Topic { return "stock.low" }
+
+// finish closes the bus, failing the test instead of hanging if an async
+// subscriber never drains
+func finish(t *testing.T, b *Bus) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		b.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close didn't return: an async subscriber is stuck")
+	}
+}
+
+// An async subscriber that publishes from its handler used to deadlock
+// once its queue filled: Publish blocked sending to the queue that only
+// the publishing handler itself could drain.
+func TestAsyncSubscriberRepublishes(t *testing.T) {
+	b := NewBus()
+	var stockEvents, lowEvents atomic.Int64
+
+	err := b.Subscribe("inventory", func(e Event) error {
+		stockEvents.Add(1)
+		return b.Publish(stockLow{e.(created).n})
+	}, Topics("order.created", "stock.low"), Async())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Subscribe("purchasing", func(Event) error {
+		lowEvents.Add(1)
+		return nil
+	}, Topics("stock.low"), Async()); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 200; i++ {
+		if err := b.Publish(created{i}); err != nil {
+			t.Fatalf("Publish %d: %v", i, err)
+		}
+	}
+
+	// Wait until every re-published event has been queued, then close
+	deadline := time.Now().Add(5 * time.Second)
+	for lowEvents.Load() < 200 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	finish(t, b)
+
+	if got := lowEvents.Load(); got != 200 {
+		t.Errorf("purchasing got %d stock.low events, want 200", got)
+	}
+	if got := stockEvents.Load(); got != 400 {
+		t.Errorf("inventory got %d events, want 400 (200 created + its own 200 stock.low)", got)
+	}
+}
+
+func TestAsyncDrainsOnClose(t *testing.T) {
+	b := NewBus()
+	var mu sync.Mutex
+	var got []int
+	release := make(chan struct{})
+
+	b.Subscribe("slow", func(e Event) error {
+		<-release
+		mu.Lock()
+		got = append(got, e.(created).n)
+		mu.Unlock()
+		return nil
+	}, Async())
+
+	for i := 0; i < 500; i++ {
+		if err := b.Publish(created{i}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(release)
+	finish(t, b)
+
+	if len(got) != 500 {
+		t.Fatalf("delivered %d events, want 500", len(got))
+	}
+	for i, n := range got {
+		if n != i {
+			t.Fatalf("event %d delivered as #%d; async delivery must keep order", n, i)
+		}
+	}
+}
+
+func TestSyncRetriesAndIsolation(t *testing.T) {
+	b := NewBus()
+	calls := 0
+	b.Subscribe("flaky", func(Event) error {
+		calls++
+		if calls < 3 {
+			return errors.New("timeout")
+		}
+		return nil
+	}, Retry(3, time.Microsecond))
+	b.Subscribe("broken", func(Event) error { panic("out of paper") })
+	reached := false
+	b.Subscribe("after", func(Event) error { reached = true; return nil })
+
+	err := b.Publish(created{1})
+	if calls != 3 {
+		t.Errorf("flaky was called %d times, want 3", calls)
+	}
+	if !reached {
+		t.Error("a panicking subscriber stopped the next one")
+	}
+
+	var failed *DeliveryError
+	if !errors.As(err, &failed) || failed.Subscriber != "broken" || failed.Attempts != 1 {
+		t.Fatalf("Publish = %v, want broken's DeliveryError after 1 attempt", err)
+	}
+	if len(b.Failures()) != 1 {
+		t.Errorf("Failures = %v, want just broken", b.Failures())
+	}
+	finish(t, b)
+}
+
+func TestTopicsFilter(t *testing.T) {
+	b := NewBus()
+	var onlyLow, all int
+	b.Subscribe("low", func(Event) error { onlyLow++; return nil }, Topics("stock.low"))
+	b.Subscribe("all", func(Event) error { all++; return nil })
+
+	b.Publish(created{1}, stockLow{1}, created{2})
+	if onlyLow != 1 || all != 3 {
+		t.Errorf("low got %d, all got %d; want 1 and 3", onlyLow, all)
+	}
+	finish(t, b)
+}
+
+func TestSubscribeAndPublishErrors(t *testing.T) {
+	b := NewBus()
+	handler := func(Event) error { return nil }
+	if err := b.Subscribe("a", handler); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Subscribe("a", handler); !errors.Is(err, ErrDuplicate) {
+		t.Errorf("second Subscribe = %v, want ErrDuplicate", err)
+	}
+
+	finish(t, b)
+	b.Close() // Twice is fine
+	if err := b.Publish(created{1}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Publish after Close = %v, want ErrClosed", err)
+	}
+	if err := b.Subscribe("b", handler); !errors.Is(err, ErrClosed) {
+		t.Errorf("Subscribe after Close = %v, want ErrClosed", err)
+	}
+}
@@ -6,13 +6,12 @@ echo "=== Running Variadic Functions Examples ==="
 echo
 
 # Run each example
-for i in {1..9}; do
-    file=$(printf "%02d_*.go" $i)
-    if [ -f $file ]; then
+for file in [0-9][0-9]_*.go; do
+    if [ -f "$file" ]; then
         echo "----------------------------------------"
         echo "Running: $file"
         echo "----------------------------------------"
-        go run $file
+        go run "$file"
         echo
         echo "Press Enter to continue..."
         read